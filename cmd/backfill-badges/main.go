@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"user-rewards-api/internal/app"
+)
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	slog.SetDefault(logger)
+
+	users, awarded, err := app.BackfillBadges(context.Background())
+	if err != nil {
+		slog.Error("Ошибка пересчета значков", "users", users, "awarded", awarded, "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Значки пересчитаны", "users", users, "awarded", awarded)
+}
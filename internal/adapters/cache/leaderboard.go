@@ -0,0 +1,394 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+)
+
+// warmPageSize размер страницы при загрузке таблицы лидеров из базы данных
+const warmPageSize = 5000
+
+// LeaderboardCache декоратор usecases.PostgreSQLAdapter, который хранит таблицу лидеров
+// за все время в памяти и отвечает на запросы мест за O(log n) без обращения к базе данных.
+//
+// Изменения баланса применяются к кэшу только после фиксации транзакции, в которой они
+// были сделаны. Изменения, сделанные другими экземплярами приложения, попадают в кэш
+// при очередной сверке с базой данных (Reconcile).
+type LeaderboardCache struct {
+	usecases.PostgreSQLAdapter
+
+	mu      sync.RWMutex
+	list    *skipList
+	members map[string]member
+	ready   bool
+	// replay накапливает изменения, примененные во время перезагрузки кэша
+	replay *pendingUpdates
+}
+
+// NewLeaderboardCache создает кэш таблицы лидеров поверх адаптера postgres.
+// До вызова Reconcile запросы таблицы лидеров передаются в postgres.
+func NewLeaderboardCache(postgres usecases.PostgreSQLAdapter) *LeaderboardCache {
+	return &LeaderboardCache{
+		PostgreSQLAdapter: postgres,
+		list:              newSkipList(),
+		members:           make(map[string]member),
+	}
+}
+
+// pendingKey типизированный ключ для хранения изменений текущей транзакции в контексте
+type pendingKey struct{}
+
+// pendingUpdates изменения кэша, ожидающие фиксации транзакции
+type pendingUpdates struct {
+	users    []member
+	balances []balanceUpdate
+}
+
+type balanceUpdate struct {
+	userID  string
+	balance int
+}
+
+func (p *pendingUpdates) merge(child *pendingUpdates) {
+	p.users = append(p.users, child.users...)
+	p.balances = append(p.balances, child.balances...)
+}
+
+// WithTransaction выполняет функцию в транзакции и применяет изменения кэша после ее фиксации.
+// Изменения вложенной транзакции передаются во внешнюю и отбрасываются при откате.
+func (c *LeaderboardCache) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	parent, nested := ctx.Value(pendingKey{}).(*pendingUpdates)
+	pending := &pendingUpdates{}
+
+	err := c.PostgreSQLAdapter.WithTransaction(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, pendingKey{}, pending))
+	})
+	if err != nil {
+		return err
+	}
+
+	if nested {
+		parent.merge(pending)
+	} else {
+		c.apply(pending)
+	}
+	return nil
+}
+
+// CreateUser создает пользователя и добавляет его в таблицу лидеров
+func (c *LeaderboardCache) CreateUser(ctx context.Context, user domain.User) error {
+	if err := c.PostgreSQLAdapter.CreateUser(ctx, user); err != nil {
+		return err
+	}
+
+	c.record(ctx, func(p *pendingUpdates) {
+		p.users = append(p.users, userMember(user))
+	})
+	return nil
+}
+
+// AddLedgerEntry добавляет запись в журнал поинтов и обновляет место пользователя
+func (c *LeaderboardCache) AddLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, error) {
+	balance, err := c.PostgreSQLAdapter.AddLedgerEntry(ctx, entry)
+	if err != nil {
+		return balance, err
+	}
+
+	c.record(ctx, func(p *pendingUpdates) {
+		p.balances = append(p.balances, balanceUpdate{userID: entry.UserID.String(), balance: balance.Value()})
+	})
+	return balance, nil
+}
+
+// DebitLedgerEntry списывает поинты и обновляет место пользователя, если списание прошло
+func (c *LeaderboardCache) DebitLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, bool, error) {
+	balance, debited, err := c.PostgreSQLAdapter.DebitLedgerEntry(ctx, entry)
+	if err != nil || !debited {
+		return balance, debited, err
+	}
+
+	c.record(ctx, func(p *pendingUpdates) {
+		p.balances = append(p.balances, balanceUpdate{userID: entry.UserID.String(), balance: balance.Value()})
+	})
+	return balance, true, nil
+}
+
+// GetLeaderboard получает страницу таблицы лидеров из кэша
+func (c *LeaderboardCache) GetLeaderboard(ctx context.Context, limit int, after *usecases.LeaderboardCursor) ([]usecases.LeaderboardEntry, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.ready {
+		return c.PostgreSQLAdapter.GetLeaderboard(ctx, limit, after)
+	}
+
+	node, rank := c.list.first(), 1
+	if after != nil {
+		last, lastRank := c.list.lastNotAfter(cursorMember(*after))
+		if last != nil {
+			node, rank = last.levels[0].forward, lastRank+1
+		}
+	}
+
+	result := make([]usecases.LeaderboardEntry, 0, min(limit, c.list.length))
+	for ; node != nil && len(result) < limit; node = node.levels[0].forward {
+		result = append(result, toEntry(node.member, rank))
+		rank++
+	}
+	return result, nil
+}
+
+// GetLeaderboardBefore получает участников, стоящих непосредственно перед before
+func (c *LeaderboardCache) GetLeaderboardBefore(ctx context.Context, before usecases.LeaderboardCursor, limit int) ([]usecases.LeaderboardEntry, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.ready {
+		return c.PostgreSQLAdapter.GetLeaderboardBefore(ctx, before, limit)
+	}
+
+	node, rank := c.list.lastBefore(cursorMember(before))
+
+	count := min(limit, rank)
+	result := make([]usecases.LeaderboardEntry, count)
+	for i := count - 1; i >= 0; i-- {
+		result[i] = toEntry(node.member, rank)
+		node, rank = node.backward, rank-1
+	}
+	return result, nil
+}
+
+// GetLeaderboardPosition получает место пользователя из кэша.
+// Пользователя, которого еще нет в кэше, например зарегистрированного другим экземпляром
+// приложения после последней сверки, кэш загружает из базы данных по ID и добавляет в таблицу.
+// Поэтому место считается в памяти, а не перебором строк таблицы лидеров в базе данных.
+func (c *LeaderboardCache) GetLeaderboardPosition(ctx context.Context, userID domain.UserID) (*usecases.LeaderboardEntry, error) {
+	entry, ready, found := c.position(userID.String())
+	if !ready {
+		return c.PostgreSQLAdapter.GetLeaderboardPosition(ctx, userID)
+	}
+	if found {
+		return &entry, nil
+	}
+
+	user, err := c.PostgreSQLAdapter.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	c.record(ctx, func(p *pendingUpdates) {
+		p.users = append(p.users, userMember(*user))
+	})
+
+	if entry, _, found = c.position(userID.String()); found {
+		return &entry, nil
+	}
+	// Пользователь создан в текущей транзакции и попадет в кэш после ее фиксации
+	return c.PostgreSQLAdapter.GetLeaderboardPosition(ctx, userID)
+}
+
+// position получает место участника из кэша. ready ложно, пока кэш не прогрет.
+func (c *LeaderboardCache) position(userID string) (entry usecases.LeaderboardEntry, ready, found bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.ready {
+		return usecases.LeaderboardEntry{}, false, false
+	}
+
+	m, ok := c.members[userID]
+	if !ok {
+		return usecases.LeaderboardEntry{}, true, false
+	}
+
+	_, rank := c.list.lastNotAfter(m)
+	return toEntry(m, rank), true, true
+}
+
+// CountLeaderboardUsers получает число участников таблицы лидеров
+func (c *LeaderboardCache) CountLeaderboardUsers(ctx context.Context) (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.ready {
+		return c.PostgreSQLAdapter.CountLeaderboardUsers(ctx)
+	}
+	return c.list.length, nil
+}
+
+// Reconcile загружает таблицу лидеров из базы данных и заменяет ею содержимое кэша.
+// Первый вызов прогревает кэш. Изменения, зафиксированные во время загрузки, применяются
+// поверх загруженных данных. Возвращает число участников, место или баланс которых
+// в кэше расходились с базой данных.
+func (c *LeaderboardCache) Reconcile(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	c.replay = &pendingUpdates{}
+	c.mu.Unlock()
+
+	loaded, err := c.load(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	replay := c.replay
+	c.replay = nil
+	if err != nil {
+		return 0, err
+	}
+
+	list := newSkipList()
+	members := make(map[string]member, len(loaded))
+	for _, m := range loaded {
+		list.insert(m)
+		members[m.UserID] = m
+	}
+	applyUpdates(list, members, replay)
+
+	mismatches := 0
+	if c.ready {
+		mismatches = countMismatches(c.list, list, members)
+	}
+
+	c.list, c.members, c.ready = list, members, true
+	return mismatches, nil
+}
+
+// load постранично загружает всех участников таблицы лидеров из базы данных
+func (c *LeaderboardCache) load(ctx context.Context) ([]member, error) {
+	var result []member
+	var after *usecases.LeaderboardCursor
+
+	for {
+		entries, err := c.PostgreSQLAdapter.GetLeaderboard(ctx, warmPageSize, after)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка загрузки таблицы лидеров: %w", err)
+		}
+
+		for _, entry := range entries {
+			result = append(result, member{
+				UserID:    entry.UserID,
+				Username:  entry.Username,
+				Balance:   entry.Balance,
+				CreatedAt: entry.CreatedAt,
+			})
+		}
+
+		if len(entries) < warmPageSize {
+			return result, nil
+		}
+		cursor := entries[len(entries)-1].Cursor()
+		after = &cursor
+	}
+}
+
+// record применяет изменение к кэшу сразу или откладывает его до фиксации текущей транзакции
+func (c *LeaderboardCache) record(ctx context.Context, fn func(*pendingUpdates)) {
+	if pending, ok := ctx.Value(pendingKey{}).(*pendingUpdates); ok {
+		fn(pending)
+		return
+	}
+
+	updates := &pendingUpdates{}
+	fn(updates)
+	c.apply(updates)
+}
+
+// apply применяет зафиксированные изменения к кэшу
+func (c *LeaderboardCache) apply(updates *pendingUpdates) {
+	if len(updates.users) == 0 && len(updates.balances) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	applyUpdates(c.list, c.members, updates)
+	if c.replay != nil {
+		c.replay.merge(updates)
+	}
+}
+
+// applyUpdates применяет изменения к списку. Изменения баланса пользователей,
+// отсутствующих в кэше, пропускаются: они появятся после очередной сверки.
+func applyUpdates(list *skipList, members map[string]member, updates *pendingUpdates) {
+	for _, m := range updates.users {
+		if old, ok := members[m.UserID]; ok {
+			list.remove(old)
+		}
+		list.insert(m)
+		members[m.UserID] = m
+	}
+
+	for _, update := range updates.balances {
+		m, ok := members[update.userID]
+		if !ok || m.Balance == update.balance {
+			continue
+		}
+
+		list.remove(m)
+		m.Balance = update.balance
+		list.insert(m)
+		members[m.UserID] = m
+	}
+}
+
+// countMismatches считает участников, место или баланс которых в old отличается от fresh
+func countMismatches(old, fresh *skipList, freshMembers map[string]member) int {
+	mismatches := old.length - fresh.length
+	if mismatches < 0 {
+		mismatches = -mismatches
+	}
+
+	a, b := old.first(), fresh.first()
+	for ; a != nil && b != nil; a, b = a.levels[0].forward, b.levels[0].forward {
+		if a.member.UserID != b.member.UserID || a.member.Balance != b.member.Balance {
+			mismatches++
+		}
+	}
+	return mismatches
+}
+
+// userMember возвращает участника таблицы лидеров для пользователя
+func userMember(user domain.User) member {
+	return member{
+		UserID:    user.ID.String(),
+		Username:  user.Username.String(),
+		Balance:   user.Balance.Value(),
+		CreatedAt: databaseTimestamp(user.CreatedAt),
+	}
+}
+
+func cursorMember(cursor usecases.LeaderboardCursor) member {
+	return member{
+		UserID:    cursor.UserID,
+		Balance:   cursor.Balance,
+		CreatedAt: cursor.CreatedAt,
+	}
+}
+
+func toEntry(m member, rank int) usecases.LeaderboardEntry {
+	return usecases.LeaderboardEntry{
+		Rank:      rank,
+		UserID:    m.UserID,
+		Username:  m.Username,
+		Balance:   m.Balance,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// databaseTimestamp приводит время к виду, в котором оно возвращается из колонки TIMESTAMP:
+// время часового пояса, записанное как UTC, округленное до микросекунды так же, как
+// округляет PostgreSQL. Без этого порядок участников с одинаковым балансом в кэше
+// мог бы отличаться от базы данных.
+func databaseTimestamp(t time.Time) time.Time {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Round(time.Microsecond)
+}
@@ -0,0 +1,483 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+)
+
+var errRollback = errors.New("rollback")
+
+// fakeLeaderboardStore таблица лидеров в памяти, которая ведет себя как PostgreSQL:
+// хранит время так, как его хранит колонка TIMESTAMP, и откатывает транзакции.
+type fakeLeaderboardStore struct {
+	usecases.PostgreSQLAdapter
+
+	users  map[string]member
+	sorted []member
+	// positionQueries число запросов места, посчитанных базой данных
+	positionQueries int
+	// afterPage вызывается после чтения страницы таблицы лидеров, до возврата результата
+	afterPage func()
+}
+
+func newFakeLeaderboardStore() *fakeLeaderboardStore {
+	return &fakeLeaderboardStore{users: make(map[string]member)}
+}
+
+// postgresTimestamp повторяет запись времени в колонку TIMESTAMP: lib/pq передает время
+// с наносекундами и смещением, PostgreSQL отбрасывает смещение и округляет до микросекунды
+func postgresTimestamp(t time.Time) time.Time {
+	const layout = "2006-01-02 15:04:05.999999999"
+	wall, err := time.Parse(layout, t.Format(layout))
+	if err != nil {
+		panic(err)
+	}
+	return time.UnixMicro((wall.UnixNano() + 500) / 1000).UTC()
+}
+
+func (s *fakeLeaderboardStore) leaderboard() []member {
+	if s.sorted == nil {
+		s.sorted = make([]member, 0, len(s.users))
+		for _, m := range s.users {
+			s.sorted = append(s.sorted, m)
+		}
+		sortMembers(s.sorted)
+	}
+	return s.sorted
+}
+
+func (s *fakeLeaderboardStore) set(m member) {
+	s.users[m.UserID] = m
+	s.sorted = nil
+}
+
+func (s *fakeLeaderboardStore) entries(sorted []member, from, to int) []usecases.LeaderboardEntry {
+	result := make([]usecases.LeaderboardEntry, 0, to-from)
+	for i := from; i < to; i++ {
+		result = append(result, toEntry(sorted[i], i+1))
+	}
+	return result
+}
+
+func (s *fakeLeaderboardStore) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	snapshot := maps.Clone(s.users)
+	if err := fn(ctx); err != nil {
+		s.users, s.sorted = snapshot, nil
+		return err
+	}
+	return nil
+}
+
+func (s *fakeLeaderboardStore) CreateUser(ctx context.Context, user domain.User) error {
+	s.set(member{
+		UserID:    user.ID.String(),
+		Username:  user.Username.String(),
+		Balance:   user.Balance.Value(),
+		CreatedAt: postgresTimestamp(user.CreatedAt),
+	})
+	return nil
+}
+
+func (s *fakeLeaderboardStore) AddLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, error) {
+	m, ok := s.users[entry.UserID.String()]
+	if !ok {
+		return domain.Balance{}, domain.ErrUserNotFound
+	}
+	m.Balance += entry.Amount
+	s.set(m)
+	return domain.NewBalance(m.Balance), nil
+}
+
+func (s *fakeLeaderboardStore) GetLeaderboard(ctx context.Context, limit int, after *usecases.LeaderboardCursor) ([]usecases.LeaderboardEntry, error) {
+	sorted := s.leaderboard()
+
+	from := 0
+	if after != nil {
+		cursor := cursorMember(*after)
+		from = oracleRank(sorted, func(m member) bool { return !oracleLess(cursor, m) })
+	}
+
+	result := s.entries(sorted, from, min(from+limit, len(sorted)))
+	if s.afterPage != nil {
+		s.afterPage()
+	}
+	return result, nil
+}
+
+func (s *fakeLeaderboardStore) GetLeaderboardBefore(ctx context.Context, before usecases.LeaderboardCursor, limit int) ([]usecases.LeaderboardEntry, error) {
+	sorted := s.leaderboard()
+
+	cursor := cursorMember(before)
+	to := oracleRank(sorted, func(m member) bool { return oracleLess(m, cursor) })
+	return s.entries(sorted, max(0, to-limit), to), nil
+}
+
+func (s *fakeLeaderboardStore) GetLeaderboardPosition(ctx context.Context, userID domain.UserID) (*usecases.LeaderboardEntry, error) {
+	s.positionQueries++
+
+	m, ok := s.users[userID.String()]
+	if !ok {
+		return nil, nil
+	}
+
+	rank := oracleRank(s.leaderboard(), func(other member) bool { return !oracleLess(m, other) })
+	entry := toEntry(m, rank)
+	return &entry, nil
+}
+
+func (s *fakeLeaderboardStore) GetUserByID(ctx context.Context, userID domain.UserID) (*domain.User, error) {
+	m, ok := s.users[userID.String()]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	username, err := domain.NewUsername(m.Username)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.User{
+		ID:        userID,
+		Username:  username,
+		Balance:   domain.NewBalance(m.Balance),
+		CreatedAt: m.CreatedAt,
+	}, nil
+}
+
+func (s *fakeLeaderboardStore) CountLeaderboardUsers(ctx context.Context) (int, error) {
+	return len(s.users), nil
+}
+
+// leaderboardFixture кэш поверх фейковой базы и созданные через него пользователи
+type leaderboardFixture struct {
+	rng   *rand.Rand
+	store *fakeLeaderboardStore
+	cache *LeaderboardCache
+	users []domain.UserID
+}
+
+func newLeaderboardFixture(seed int64) *leaderboardFixture {
+	store := newFakeLeaderboardStore()
+	return &leaderboardFixture{
+		rng:   rand.New(rand.NewSource(seed)),
+		store: store,
+		cache: NewLeaderboardCache(store),
+	}
+}
+
+// createUser создает пользователя с временем регистрации не в UTC и с наносекундами,
+// часть которых PostgreSQL округляет до следующей секунды
+func (f *leaderboardFixture) createUser(t *testing.T, ctx context.Context) {
+	t.Helper()
+
+	n := len(f.users)
+	user, err := domain.NewUser(fmt.Sprintf("user%d", n), fmt.Sprintf("user%d@example.com", n))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offsets := []time.Duration{0, 400, 500, 1500, 999_999_700, time.Second}
+	moscow := time.FixedZone("MSK", 3*60*60)
+	user.CreatedAt = time.Date(2026, 1, 1, 12, 0, 0, 0, moscow).Add(offsets[f.rng.Intn(len(offsets))])
+
+	if err := f.cache.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	f.users = append(f.users, user.ID)
+}
+
+func (f *leaderboardFixture) credit(ctx context.Context, userID domain.UserID, amount int) error {
+	entry, err := domain.NewLedgerEntry(userID, amount, domain.LedgerSourceAdmin, "test")
+	if err != nil {
+		return err
+	}
+	_, err = f.cache.AddLedgerEntry(ctx, entry)
+	return err
+}
+
+func (f *leaderboardFixture) randomUser() domain.UserID {
+	return f.users[f.rng.Intn(len(f.users))]
+}
+
+// step выполняет случайное изменение: регистрацию, начисление, откат или откат вложенной транзакции
+func (f *leaderboardFixture) step(t *testing.T, ctx context.Context) {
+	t.Helper()
+
+	var err error
+	switch op := f.rng.Intn(10); {
+	case op < 2 || len(f.users) == 0:
+		f.createUser(t, ctx)
+	case op < 5:
+		err = f.credit(ctx, f.randomUser(), 1+f.rng.Intn(3))
+	case op < 7:
+		err = f.cache.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := f.credit(ctx, f.randomUser(), 1+f.rng.Intn(3)); err != nil {
+				return err
+			}
+			return f.credit(ctx, f.randomUser(), 1+f.rng.Intn(3))
+		})
+	case op < 8:
+		err = f.cache.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := f.credit(ctx, f.randomUser(), 100); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if errors.Is(err, errRollback) {
+			err = nil
+		}
+	default:
+		err = f.cache.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := f.credit(ctx, f.randomUser(), 2); err != nil {
+				return err
+			}
+
+			err := f.cache.WithTransaction(ctx, func(ctx context.Context) error {
+				if err := f.credit(ctx, f.randomUser(), 100); err != nil {
+					return err
+				}
+				return errRollback
+			})
+			if !errors.Is(err, errRollback) {
+				return fmt.Errorf("вложенная транзакция: %v", err)
+			}
+			return nil
+		})
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertEntries(t *testing.T, name string, got, want []usecases.LeaderboardEntry) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: %d записей, ожидалось %d", name, len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Rank != w.Rank || g.UserID != w.UserID || g.Username != w.Username ||
+			g.Balance != w.Balance || !g.CreatedAt.Equal(w.CreatedAt) {
+			t.Fatalf("%s, запись %d: %+v, ожидалось %+v", name, i, g, w)
+		}
+	}
+}
+
+// assertMatchesDatabase сравнивает ответы кэша с фейковой базой: все страницы таблицы лидеров
+// при разных размерах страниц, страницы перед каждой границей, места всех пользователей
+// и страницы относительно курсоров, не совпадающих ни с одним участником
+func (f *leaderboardFixture) assertMatchesDatabase(t *testing.T, ctx context.Context) {
+	t.Helper()
+
+	gotCount, _ := f.cache.CountLeaderboardUsers(ctx)
+	wantCount, _ := f.store.CountLeaderboardUsers(ctx)
+	if gotCount != wantCount {
+		t.Fatalf("число участников %d, ожидалось %d", gotCount, wantCount)
+	}
+
+	for _, size := range []int{1, 3, 10, 64} {
+		var after *usecases.LeaderboardCursor
+		for page := 0; ; page++ {
+			got, _ := f.cache.GetLeaderboard(ctx, size, after)
+			want, _ := f.store.GetLeaderboard(ctx, size, after)
+			assertEntries(t, fmt.Sprintf("GetLeaderboard size=%d page=%d", size, page), got, want)
+
+			if len(got) == 0 {
+				break
+			}
+
+			before := got[0].Cursor()
+			got, _ = f.cache.GetLeaderboardBefore(ctx, before, size)
+			want, _ = f.store.GetLeaderboardBefore(ctx, before, size)
+			assertEntries(t, fmt.Sprintf("GetLeaderboardBefore size=%d page=%d", size, page), got, want)
+
+			if len(want) < size {
+				break
+			}
+			cursor := want[len(want)-1].Cursor()
+			after = &cursor
+		}
+	}
+
+	for _, userID := range f.users {
+		got, _ := f.cache.GetLeaderboardPosition(ctx, userID)
+		want, _ := f.store.GetLeaderboardPosition(ctx, userID)
+		assertEntries(t, "GetLeaderboardPosition "+userID.String(),
+			[]usecases.LeaderboardEntry{*got}, []usecases.LeaderboardEntry{*want})
+	}
+
+	for i := 0; i < 20; i++ {
+		cursor := usecases.LeaderboardCursor{
+			Balance:   f.rng.Intn(30),
+			CreatedAt: postgresTimestamp(time.Date(2026, 1, 1, 9, 0, 0, f.rng.Intn(2_000_000_000), time.UTC)),
+			UserID:    fmt.Sprintf("%08x", f.rng.Uint32()),
+		}
+
+		got, _ := f.cache.GetLeaderboard(ctx, 5, &cursor)
+		want, _ := f.store.GetLeaderboard(ctx, 5, &cursor)
+		assertEntries(t, fmt.Sprintf("GetLeaderboard after %+v", cursor), got, want)
+
+		got, _ = f.cache.GetLeaderboardBefore(ctx, cursor, 5)
+		want, _ = f.store.GetLeaderboardBefore(ctx, cursor, 5)
+		assertEntries(t, fmt.Sprintf("GetLeaderboardBefore %+v", cursor), got, want)
+	}
+}
+
+func TestLeaderboardCacheMatchesDatabase(t *testing.T) {
+	ctx := context.Background()
+	f := newLeaderboardFixture(1)
+
+	for i := 0; i < 50; i++ {
+		f.createUser(t, ctx)
+	}
+	if _, err := f.cache.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+	f.assertMatchesDatabase(t, ctx)
+
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 50; i++ {
+			f.step(t, ctx)
+		}
+		f.assertMatchesDatabase(t, ctx)
+	}
+
+	mismatches, err := f.cache.Reconcile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mismatches != 0 {
+		t.Fatalf("сверка нашла %d расхождений", mismatches)
+	}
+	f.assertMatchesDatabase(t, ctx)
+}
+
+func TestLeaderboardCacheReconcileReplaysConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+	f := newLeaderboardFixture(2)
+
+	for i := 0; i < 20; i++ {
+		f.createUser(t, ctx)
+	}
+	if _, err := f.cache.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Изменения фиксируются после того, как страница уже прочитана,
+	// поэтому загруженные данные их не содержат
+	credited, created := f.users[0], 0
+	f.store.afterPage = func() {
+		f.store.afterPage = nil
+
+		if err := f.cache.WithTransaction(ctx, func(ctx context.Context) error {
+			return f.credit(ctx, credited, 1000)
+		}); err != nil {
+			t.Error(err)
+		}
+		f.createUser(t, ctx)
+		created++
+	}
+
+	mismatches, err := f.cache.Reconcile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 1 {
+		t.Fatalf("изменения во время загрузки не выполнены")
+	}
+	if mismatches != 0 {
+		t.Fatalf("сверка нашла %d расхождений", mismatches)
+	}
+
+	position, _ := f.cache.GetLeaderboardPosition(ctx, credited)
+	if position.Rank != 1 || position.Balance < 1000 {
+		t.Fatalf("начисление во время загрузки потеряно: %+v", position)
+	}
+	f.assertMatchesDatabase(t, ctx)
+}
+
+func TestLeaderboardCacheLoadsAllPages(t *testing.T) {
+	ctx := context.Background()
+	f := newLeaderboardFixture(3)
+
+	for i := 0; i < 2*warmPageSize+1; i++ {
+		f.createUser(t, ctx)
+	}
+	if _, err := f.cache.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.cache.list.length != 2*warmPageSize+1 {
+		t.Fatalf("загружено %d участников, ожидалось %d", f.cache.list.length, 2*warmPageSize+1)
+	}
+	for _, userID := range f.users[:100] {
+		got, _ := f.cache.GetLeaderboardPosition(ctx, userID)
+		want, _ := f.store.GetLeaderboardPosition(ctx, userID)
+		assertEntries(t, "GetLeaderboardPosition "+userID.String(),
+			[]usecases.LeaderboardEntry{*got}, []usecases.LeaderboardEntry{*want})
+	}
+}
+
+func TestLeaderboardCacheLoadsMissingUserForPosition(t *testing.T) {
+	ctx := context.Background()
+	f := newLeaderboardFixture(4)
+
+	for i := 0; i < 50; i++ {
+		f.createUser(t, ctx)
+	}
+	if _, err := f.cache.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Пользователь зарегистрирован другим экземпляром приложения и в кэш не попал
+	user, err := domain.NewUser("other_instance", "other@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.store.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := f.cache.GetLeaderboardPosition(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := f.store.GetLeaderboardPosition(ctx, user.ID)
+	assertEntries(t, "GetLeaderboardPosition", []usecases.LeaderboardEntry{*got}, []usecases.LeaderboardEntry{*want})
+
+	if f.store.positionQueries != 1 {
+		t.Fatalf("место посчитано базой данных %d раз, ожидалось только проверочное", f.store.positionQueries)
+	}
+	if _, ok := f.cache.members[user.ID.String()]; !ok {
+		t.Fatal("пользователь не добавлен в кэш")
+	}
+
+	missing, err := domain.NewUserID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position, err := f.cache.GetLeaderboardPosition(ctx, missing); err != nil || position != nil {
+		t.Fatalf("место несуществующего пользователя: %+v, %v", position, err)
+	}
+}
+
+func TestDatabaseTimestampMatchesPostgres(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	base := time.Date(2026, 1, 1, 23, 59, 59, 0, moscow)
+
+	for _, ns := range []int{0, 1, 499, 501, 1_500, 999_999_499, 999_999_501} {
+		value := base.Add(time.Duration(ns))
+		if got, want := databaseTimestamp(value), postgresTimestamp(value); !got.Equal(want) {
+			t.Errorf("databaseTimestamp(%v) = %v, PostgreSQL хранит %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	skipListMaxLevel    = 32
+	skipListProbability = 0.25
+)
+
+// member участник таблицы лидеров, хранящийся в кэше
+type member struct {
+	UserID    string
+	Username  string
+	Balance   int
+	CreatedAt time.Time
+}
+
+// less сравнивает участников в порядке таблицы лидеров: balance DESC, created_at ASC, id ASC
+func less(a, b member) bool {
+	if a.Balance != b.Balance {
+		return a.Balance > b.Balance
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.UserID < b.UserID
+}
+
+type skipLevel struct {
+	forward *skipNode
+	// span число участников между узлом и forward, используется для вычисления места
+	span int
+}
+
+type skipNode struct {
+	member   member
+	backward *skipNode
+	levels   []skipLevel
+}
+
+// skipList индексируемый список с пропусками. Поиск, вставка, удаление
+// и вычисление места выполняются в среднем за O(log n).
+// Не потокобезопасен, синхронизация выполняется в LeaderboardCache.
+type skipList struct {
+	head   *skipNode
+	tail   *skipNode
+	length int
+	level  int
+}
+
+func newSkipList() *skipList {
+	return &skipList{
+		head:  &skipNode{levels: make([]skipLevel, skipListMaxLevel)},
+		level: 1,
+	}
+}
+
+func randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.Float64() < skipListProbability {
+		level++
+	}
+	return level
+}
+
+// insert добавляет участника. Участник с тем же ключом не должен уже присутствовать в списке.
+func (l *skipList) insert(m member) {
+	var update [skipListMaxLevel]*skipNode
+	var rank [skipListMaxLevel]int
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		if i < l.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].forward != nil && less(x.levels[i].forward.member, m) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+
+	level := randomLevel()
+	if level > l.level {
+		for i := l.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = l.head
+			update[i].levels[i].span = l.length
+		}
+		l.level = level
+	}
+
+	node := &skipNode{member: m, levels: make([]skipLevel, level)}
+	for i := 0; i < level; i++ {
+		node.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = node
+
+		node.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < l.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != l.head {
+		node.backward = update[0]
+	}
+	if node.levels[0].forward != nil {
+		node.levels[0].forward.backward = node
+	} else {
+		l.tail = node
+	}
+	l.length++
+}
+
+// remove удаляет участника с ключом m. Возвращает false, если участник не найден.
+func (l *skipList) remove(m member) bool {
+	var update [skipListMaxLevel]*skipNode
+
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && less(x.levels[i].forward.member, m) {
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+
+	x = x.levels[0].forward
+	if x == nil || x.member.UserID != m.UserID || less(m, x.member) || less(x.member, m) {
+		return false
+	}
+
+	for i := 0; i < l.level; i++ {
+		if update[i].levels[i].forward == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].forward = x.levels[i].forward
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x.backward
+	} else {
+		l.tail = x.backward
+	}
+	for l.level > 1 && l.head.levels[l.level-1].forward == nil {
+		l.level--
+	}
+	l.length--
+	return true
+}
+
+// lastNotAfter возвращает последний узел, который не стоит после m, и его место.
+// Если таких узлов нет, возвращает nil и 0.
+func (l *skipList) lastNotAfter(m member) (*skipNode, int) {
+	return l.seek(func(node member) bool { return !less(m, node) })
+}
+
+// lastBefore возвращает последний узел, стоящий строго перед m, и его место.
+// Если таких узлов нет, возвращает nil и 0.
+func (l *skipList) lastBefore(m member) (*skipNode, int) {
+	return l.seek(func(node member) bool { return less(node, m) })
+}
+
+// seek продвигается по списку, пока узлы удовлетворяют условию before
+func (l *skipList) seek(before func(member) bool) (*skipNode, int) {
+	x := l.head
+	rank := 0
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && before(x.levels[i].forward.member) {
+			rank += x.levels[i].span
+			x = x.levels[i].forward
+		}
+	}
+	if x == l.head {
+		return nil, 0
+	}
+	return x, rank
+}
+
+// first возвращает первый узел списка
+func (l *skipList) first() *skipNode {
+	return l.head.levels[0].forward
+}
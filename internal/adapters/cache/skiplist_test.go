@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+var testEpoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// oracleLess порядок таблицы лидеров, записанный независимо от less:
+// balance DESC, created_at ASC, id ASC
+func oracleLess(a, b member) bool {
+	switch {
+	case a.Balance != b.Balance:
+		return a.Balance > b.Balance
+	case a.CreatedAt.UnixNano() != b.CreatedAt.UnixNano():
+		return a.CreatedAt.UnixNano() < b.CreatedAt.UnixNano()
+	default:
+		return a.UserID < b.UserID
+	}
+}
+
+func sortMembers(members []member) {
+	sort.Slice(members, func(i, j int) bool { return oracleLess(members[i], members[j]) })
+}
+
+// oracleRank возвращает число участников, для которых before истинно
+func oracleRank(sorted []member, before func(member) bool) int {
+	return sort.Search(len(sorted), func(i int) bool { return !before(sorted[i]) })
+}
+
+// randomMember создает участника с небольшим разбросом баланса и времени,
+// чтобы в списке было много участников с одинаковыми балансом и временем
+func randomMember(rng *rand.Rand, id int) member {
+	return member{
+		UserID:    fmt.Sprintf("user-%06d", id),
+		Balance:   rng.Intn(20),
+		CreatedAt: testEpoch.Add(time.Duration(rng.Intn(5)) * time.Second),
+	}
+}
+
+func checkSkipList(t *testing.T, rng *rand.Rand, list *skipList, members map[string]member) {
+	t.Helper()
+
+	sorted := make([]member, 0, len(members))
+	for _, m := range members {
+		sorted = append(sorted, m)
+	}
+	sortMembers(sorted)
+
+	if list.length != len(sorted) {
+		t.Fatalf("длина %d, ожидалось %d", list.length, len(sorted))
+	}
+
+	i := 0
+	for node := list.first(); node != nil; node = node.levels[0].forward {
+		if node.member != sorted[i] {
+			t.Fatalf("позиция %d: %s, ожидался %s", i+1, node.member.UserID, sorted[i].UserID)
+		}
+		i++
+	}
+
+	i = len(sorted) - 1
+	for node := list.tail; node != nil; node = node.backward {
+		if node.member != sorted[i] {
+			t.Fatalf("обратный обход, позиция %d: %s, ожидался %s", i+1, node.member.UserID, sorted[i].UserID)
+		}
+		i--
+	}
+	if i != -1 {
+		t.Fatalf("обратный обход остановился на позиции %d", i+1)
+	}
+
+	probes := make([]member, 0, 2*len(sorted)+1)
+	probes = append(probes, sorted...)
+	for i := 0; i <= len(sorted); i++ {
+		probes = append(probes, randomMember(rng, rng.Intn(1_000_000)))
+	}
+
+	for _, probe := range probes {
+		node, rank := list.lastNotAfter(probe)
+		want := oracleRank(sorted, func(m member) bool { return !oracleLess(probe, m) })
+		if rank != want || (want > 0) != (node != nil) || (node != nil && node.member != sorted[want-1]) {
+			t.Fatalf("lastNotAfter(%+v): место %d, ожидалось %d", probe, rank, want)
+		}
+
+		node, rank = list.lastBefore(probe)
+		want = oracleRank(sorted, func(m member) bool { return oracleLess(m, probe) })
+		if rank != want || (want > 0) != (node != nil) || (node != nil && node.member != sorted[want-1]) {
+			t.Fatalf("lastBefore(%+v): место %d, ожидалось %d", probe, rank, want)
+		}
+	}
+}
+
+func TestSkipListMatchesSortedOracle(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	list := newSkipList()
+	members := make(map[string]member)
+	var ids []string
+	nextID := 0
+
+	for step := 0; step < 3000; step++ {
+		op := rng.Intn(10)
+		if op < 5 || len(ids) == 0 {
+			m := randomMember(rng, nextID)
+			nextID++
+			list.insert(m)
+			members[m.UserID] = m
+			ids = append(ids, m.UserID)
+		} else {
+			idx := rng.Intn(len(ids))
+			existing := members[ids[idx]]
+			if !list.remove(existing) {
+				t.Fatalf("шаг %d: участник %s не удален", step, existing.UserID)
+			}
+
+			if op < 7 {
+				delete(members, existing.UserID)
+				ids[idx] = ids[len(ids)-1]
+				ids = ids[:len(ids)-1]
+			} else {
+				existing.Balance = rng.Intn(20)
+				list.insert(existing)
+				members[existing.UserID] = existing
+			}
+		}
+
+		if step%100 == 0 {
+			checkSkipList(t, rng, list, members)
+		}
+	}
+	checkSkipList(t, rng, list, members)
+
+	missing := randomMember(rng, nextID)
+	if list.remove(missing) {
+		t.Fatalf("удален отсутствующий участник")
+	}
+}
+
+// benchmarkSizes размеры таблицы лидеров для бенчмарков
+var benchmarkSizes = []int{100_000, 1_000_000}
+
+func newBenchmarkList(size int) (*skipList, []member) {
+	rng := rand.New(rand.NewSource(1))
+	list := newSkipList()
+	members := make([]member, size)
+	for i := range members {
+		members[i] = member{
+			UserID:    fmt.Sprintf("user-%08d", i),
+			Balance:   rng.Intn(size / 10),
+			CreatedAt: testEpoch.Add(time.Duration(i) * time.Second),
+		}
+		list.insert(members[i])
+	}
+	return list, members
+}
+
+func BenchmarkSkipListInsert(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("members=%d", size), func(b *testing.B) {
+			list, members := newBenchmarkList(size)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				m := members[i%size]
+				m.UserID = fmt.Sprintf("new-%08d", i)
+				list.insert(m)
+
+				b.StopTimer()
+				list.remove(m)
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkSkipListRemove(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("members=%d", size), func(b *testing.B) {
+			list, members := newBenchmarkList(size)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				m := members[i%size]
+				list.remove(m)
+
+				b.StopTimer()
+				list.insert(m)
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkSkipListRank(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("members=%d", size), func(b *testing.B) {
+			list, members := newBenchmarkList(size)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				list.lastNotAfter(members[i%size])
+			}
+		})
+	}
+}
+
+// BenchmarkSkipListUpdate измеряет изменение баланса участника: удаление и повторную вставку
+func BenchmarkSkipListUpdate(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("members=%d", size), func(b *testing.B) {
+			list, members := newBenchmarkList(size)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				idx := i % size
+				list.remove(members[idx])
+				members[idx].Balance++
+				list.insert(members[idx])
+			}
+		})
+	}
+}
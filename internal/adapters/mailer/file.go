@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"user-rewards-api/internal/usecases"
+)
+
+// FileMailer сохраняет письма в файлы вместо отправки. Используется для локальной разработки.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer создает адаптер, сохраняющий письма в директорию dir
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ошибка создания директории для писем: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send сохраняет письмо в файл .eml
+func (m *FileMailer) Send(ctx context.Context, message usecases.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), message.To)
+	path := filepath.Join(m.dir, filepath.Base(name))
+
+	if err := os.WriteFile(path, buildMessage(m.from, message), 0o600); err != nil {
+		return fmt.Errorf("ошибка сохранения письма: %w", err)
+	}
+
+	slog.Info("Письмо сохранено в файл", "to", message.To, "path", path)
+	return nil
+}
+
+// MemoryMailer хранит отправленные письма в памяти. Используется в тестах.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []usecases.EmailMessage
+}
+
+// NewMemoryMailer создает адаптер, хранящий письма в памяти
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send сохраняет письмо в памяти
+func (m *MemoryMailer) Send(ctx context.Context, message usecases.EmailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// Messages возвращает копию всех сохраненных писем
+func (m *MemoryMailer) Messages() []usecases.EmailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]usecases.EmailMessage, len(m.messages))
+	copy(result, m.messages)
+	return result
+}
@@ -2,6 +2,7 @@ package postgresql
 
 import (
 	"context"
+	"time"
 
 	"user-rewards-api/internal/domain"
 	"user-rewards-api/internal/usecases"
@@ -11,19 +12,44 @@ import (
 
 // PostgreSQLAdapter объединяет все адаптеры PostgreSQL
 type PostgreSQLAdapter struct {
-	user        *PostgreSQLUserAdapter
-	task        *PostgreSQLTaskAdapter
-	referral    *PostgreSQLReferralAdapter
-	transaction *PostgreSQLTransactionAdapter
+	user         *PostgreSQLUserAdapter
+	task         *PostgreSQLTaskAdapter
+	taskCatalog  *PostgreSQLTaskCatalogAdapter
+	submission   *PostgreSQLSubmissionAdapter
+	referral     *PostgreSQLReferralAdapter
+	referralCode *PostgreSQLReferralCodeAdapter
+	commission   *PostgreSQLCommissionAdapter
+	clientEvent  *PostgreSQLClientEventAdapter
+	external     *PostgreSQLExternalAccountAdapter
+	ledger       *PostgreSQLLedgerAdapter
+	season       *PostgreSQLSeasonAdapter
+	reward       *PostgreSQLRewardAdapter
+	badge        *PostgreSQLBadgeAdapter
+	refreshToken *PostgreSQLRefreshTokenAdapter
+	loginCode    *PostgreSQLLoginCodeAdapter
+	transaction  *PostgreSQLTransactionAdapter
 }
 
-// NewPostgreSQLAdapter создает новый объединенный адаптер PostgreSQL
-func NewPostgreSQLAdapter(db *sqlx.DB) *PostgreSQLAdapter {
+// NewPostgreSQLAdapter создает новый объединенный адаптер PostgreSQL.
+// pointsExpiry задает срок действия начисляемых поинтов.
+func NewPostgreSQLAdapter(db *sqlx.DB, pointsExpiry domain.PointsExpiryPolicy) *PostgreSQLAdapter {
 	return &PostgreSQLAdapter{
-		user:        NewPostgreSQLUserAdapter(db),
-		task:        NewPostgreSQLTaskAdapter(db),
-		referral:    NewPostgreSQLReferralAdapter(db),
-		transaction: NewPostgreSQLTransactionAdapter(db),
+		user:         NewPostgreSQLUserAdapter(db),
+		task:         NewPostgreSQLTaskAdapter(db),
+		taskCatalog:  NewPostgreSQLTaskCatalogAdapter(db),
+		submission:   NewPostgreSQLSubmissionAdapter(db),
+		referral:     NewPostgreSQLReferralAdapter(db),
+		referralCode: NewPostgreSQLReferralCodeAdapter(db),
+		commission:   NewPostgreSQLCommissionAdapter(db),
+		clientEvent:  NewPostgreSQLClientEventAdapter(db),
+		external:     NewPostgreSQLExternalAccountAdapter(db),
+		ledger:       NewPostgreSQLLedgerAdapter(db, pointsExpiry),
+		season:       NewPostgreSQLSeasonAdapter(db),
+		reward:       NewPostgreSQLRewardAdapter(db),
+		badge:        NewPostgreSQLBadgeAdapter(db),
+		refreshToken: NewPostgreSQLRefreshTokenAdapter(db),
+		loginCode:    NewPostgreSQLLoginCodeAdapter(db),
+		transaction:  NewPostgreSQLTransactionAdapter(db),
 	}
 }
 
@@ -44,27 +70,54 @@ func (a *PostgreSQLAdapter) GetUserByEmail(ctx context.Context, email string) (*
 	return a.user.GetUserByEmail(ctx, email)
 }
 
-func (a *PostgreSQLAdapter) UpdateUserBalance(ctx context.Context, userID domain.UserID, balance domain.Balance) error {
-	return a.user.UpdateUserBalance(ctx, userID, balance)
+func (a *PostgreSQLAdapter) LockUser(ctx context.Context, userID domain.UserID) error {
+	return a.user.LockUser(ctx, userID)
 }
 
-func (a *PostgreSQLAdapter) GetLeaderboard(ctx context.Context, limit int) ([]usecases.LeaderboardEntry, error) {
-	entries, err := a.user.GetLeaderboard(ctx, limit)
+func (a *PostgreSQLAdapter) ListUserIDs(ctx context.Context, after *domain.UserID, limit int) ([]domain.UserID, error) {
+	return a.user.ListUserIDs(ctx, after, limit)
+}
+
+func (a *PostgreSQLAdapter) GetLeaderboard(ctx context.Context, limit int, after *usecases.LeaderboardCursor) ([]usecases.LeaderboardEntry, error) {
+	entries, err := a.user.GetLeaderboard(ctx, limit, after)
 	if err != nil {
 		return nil, err
 	}
+	return toLeaderboardEntries(entries), nil
+}
 
-	result := make([]usecases.LeaderboardEntry, len(entries))
-	for i := range entries {
-		result[i] = usecases.LeaderboardEntry{
-			Rank:     entries[i].Rank,
-			UserID:   entries[i].UserID,
-			Username: entries[i].Username,
-			Balance:  entries[i].Balance,
-		}
+func (a *PostgreSQLAdapter) GetLeaderboardBefore(ctx context.Context, before usecases.LeaderboardCursor, limit int) ([]usecases.LeaderboardEntry, error) {
+	entries, err := a.user.GetLeaderboardBefore(ctx, before, limit)
+	if err != nil {
+		return nil, err
 	}
+	return toLeaderboardEntries(entries), nil
+}
 
-	return result, nil
+func (a *PostgreSQLAdapter) GetLeaderboardPosition(ctx context.Context, userID domain.UserID) (*usecases.LeaderboardEntry, error) {
+	entry, err := a.user.GetLeaderboardPosition(ctx, userID)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	result := toLeaderboardEntries([]leaderboardEntry{*entry})[0]
+	return &result, nil
+}
+
+func (a *PostgreSQLAdapter) CountLeaderboardUsers(ctx context.Context) (int, error) {
+	return a.user.CountLeaderboardUsers(ctx)
+}
+
+func (a *PostgreSQLAdapter) GetPeriodLeaderboard(ctx context.Context, since, until time.Time, limit int, after *usecases.LeaderboardCursor) ([]usecases.LeaderboardEntry, error) {
+	entries, err := a.ledger.GetPeriodLeaderboard(ctx, since, until, limit, after)
+	if err != nil {
+		return nil, err
+	}
+	return toLeaderboardEntries(entries), nil
+}
+
+func (a *PostgreSQLAdapter) CountPeriodLeaderboardUsers(ctx context.Context, since, until time.Time) (int, error) {
+	return a.ledger.CountPeriodLeaderboardUsers(ctx, since, until)
 }
 
 // Методы для работы с заданиями
@@ -76,8 +129,46 @@ func (a *PostgreSQLAdapter) GetTasksByUserID(ctx context.Context, userID domain.
 	return a.task.GetTasksByUserID(ctx, userID)
 }
 
-func (a *PostgreSQLAdapter) GetTaskByUserAndType(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (*domain.UserTask, error) {
-	return a.task.GetTaskByUserAndType(ctx, userID, taskType)
+func (a *PostgreSQLAdapter) GetTaskCompletionStats(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (int, *time.Time, error) {
+	return a.task.GetTaskCompletionStats(ctx, userID, taskType)
+}
+
+// Методы для работы с каталогом заданий
+func (a *PostgreSQLAdapter) CreateCatalogTask(ctx context.Context, task domain.Task) error {
+	return a.taskCatalog.CreateCatalogTask(ctx, task)
+}
+
+func (a *PostgreSQLAdapter) UpdateCatalogTask(ctx context.Context, task domain.Task) error {
+	return a.taskCatalog.UpdateCatalogTask(ctx, task)
+}
+
+func (a *PostgreSQLAdapter) GetCatalogTask(ctx context.Context, key domain.TaskType) (*domain.Task, error) {
+	return a.taskCatalog.GetCatalogTask(ctx, key)
+}
+
+func (a *PostgreSQLAdapter) ListCatalogTasks(ctx context.Context, includeInactive bool) ([]domain.Task, error) {
+	return a.taskCatalog.ListCatalogTasks(ctx, includeInactive)
+}
+
+// Методы для работы с заявками на выполнение заданий
+func (a *PostgreSQLAdapter) CreateSubmission(ctx context.Context, submission domain.TaskSubmission) error {
+	return a.submission.CreateSubmission(ctx, submission)
+}
+
+func (a *PostgreSQLAdapter) GetSubmission(ctx context.Context, id domain.SubmissionID) (*domain.TaskSubmission, error) {
+	return a.submission.GetSubmission(ctx, id)
+}
+
+func (a *PostgreSQLAdapter) UpdateSubmissionReview(ctx context.Context, submission domain.TaskSubmission) error {
+	return a.submission.UpdateSubmissionReview(ctx, submission)
+}
+
+func (a *PostgreSQLAdapter) ListSubmissionsByStatus(ctx context.Context, status domain.SubmissionStatus, limit int) ([]domain.TaskSubmission, error) {
+	return a.submission.ListSubmissionsByStatus(ctx, status, limit)
+}
+
+func (a *PostgreSQLAdapter) ListSubmissionsByUserID(ctx context.Context, userID domain.UserID) ([]domain.TaskSubmission, error) {
+	return a.submission.ListSubmissionsByUserID(ctx, userID)
 }
 
 // Методы для работы с рефералами
@@ -89,11 +180,268 @@ func (a *PostgreSQLAdapter) GetReferralByReferredUserID(ctx context.Context, ref
 	return a.referral.GetReferralByReferredUserID(ctx, referredUserID)
 }
 
+func (a *PostgreSQLAdapter) ListPendingReferrals(ctx context.Context, after *domain.Referral, limit int) ([]domain.Referral, error) {
+	return a.referral.ListPendingReferrals(ctx, after, limit)
+}
+
+func (a *PostgreSQLAdapter) UpdateReferralStatus(ctx context.Context, referral domain.Referral, from domain.ReferralStatus) (bool, error) {
+	return a.referral.UpdateReferralStatus(ctx, referral, from)
+}
+
+func (a *PostgreSQLAdapter) GetReferral(ctx context.Context, referralID domain.ReferralID) (*domain.Referral, error) {
+	return a.referral.GetReferral(ctx, referralID)
+}
+
+func (a *PostgreSQLAdapter) ListReferralsByStatus(ctx context.Context, status domain.ReferralStatus, limit int) ([]domain.Referral, error) {
+	return a.referral.ListReferralsByStatus(ctx, status, limit)
+}
+
+func (a *PostgreSQLAdapter) CountReferralsByReferrerSince(ctx context.Context, referrerID domain.UserID, since time.Time) (int, error) {
+	return a.referral.CountReferralsByReferrerSince(ctx, referrerID, since)
+}
+
 func (a *PostgreSQLAdapter) CountReferralsByReferrerID(ctx context.Context, referrerID domain.UserID) (int, error) {
 	return a.referral.CountReferralsByReferrerID(ctx, referrerID)
 }
 
+func (a *PostgreSQLAdapter) ListReferredUsers(ctx context.Context, referrerID domain.UserID, after *usecases.ReferredUsersCursor, limit int) ([]usecases.ReferredUser, error) {
+	return a.referral.ListReferredUsers(ctx, referrerID, after, limit)
+}
+
+func (a *PostgreSQLAdapter) GetReferralLeaderboard(ctx context.Context, since time.Time, limit int, after *usecases.LeaderboardCursor) ([]usecases.LeaderboardEntry, error) {
+	entries, err := a.referral.GetReferralLeaderboard(ctx, since, limit, after)
+	if err != nil {
+		return nil, err
+	}
+	return toLeaderboardEntries(entries), nil
+}
+
+func (a *PostgreSQLAdapter) CountReferralLeaderboardUsers(ctx context.Context, since time.Time) (int, error) {
+	return a.referral.CountReferralLeaderboardUsers(ctx, since)
+}
+
+// Методы для работы с реферальными кодами
+func (a *PostgreSQLAdapter) CreateReferralCode(ctx context.Context, code domain.ReferralCode) (bool, error) {
+	return a.referralCode.CreateReferralCode(ctx, code)
+}
+
+func (a *PostgreSQLAdapter) GetReferralCode(ctx context.Context, code string) (*domain.ReferralCode, error) {
+	return a.referralCode.GetReferralCode(ctx, code)
+}
+
+func (a *PostgreSQLAdapter) GetReferralCodesByUserID(ctx context.Context, userID domain.UserID) ([]domain.ReferralCode, error) {
+	return a.referralCode.GetReferralCodesByUserID(ctx, userID)
+}
+
+// Методы для работы с отпечатками клиентов
+func (a *PostgreSQLAdapter) RecordClientEvent(ctx context.Context, event domain.ClientEvent) error {
+	return a.clientEvent.RecordClientEvent(ctx, event)
+}
+
+func (a *PostgreSQLAdapter) CountUsersSharingFingerprint(ctx context.Context, fingerprint domain.ClientFingerprint, excludeUserID domain.UserID, since time.Time) (int, error) {
+	return a.clientEvent.CountUsersSharingFingerprint(ctx, fingerprint, excludeUserID, since)
+}
+
+func (a *PostgreSQLAdapter) UserSharesFingerprint(ctx context.Context, userID domain.UserID, fingerprint domain.ClientFingerprint, since time.Time) (bool, error) {
+	return a.clientEvent.UserSharesFingerprint(ctx, userID, fingerprint, since)
+}
+
+// Методы для работы с реферальными комиссиями
+func (a *PostgreSQLAdapter) GetReferralChain(ctx context.Context, userID domain.UserID, depth int) ([]domain.UserID, error) {
+	return a.commission.GetReferralChain(ctx, userID, depth)
+}
+
+func (a *PostgreSQLAdapter) CreateReferralCommission(ctx context.Context, commission domain.ReferralCommission) (bool, error) {
+	return a.commission.CreateReferralCommission(ctx, commission)
+}
+
+func (a *PostgreSQLAdapter) GetCommissionSummary(ctx context.Context, beneficiaryID domain.UserID) ([]domain.CommissionLevelSummary, error) {
+	return a.commission.GetCommissionSummary(ctx, beneficiaryID)
+}
+
+// Методы для работы с внешними аккаунтами
+func (a *PostgreSQLAdapter) CreateExternalAccount(ctx context.Context, account domain.ExternalAccount) error {
+	return a.external.CreateExternalAccount(ctx, account)
+}
+
+func (a *PostgreSQLAdapter) GetExternalAccount(ctx context.Context, provider domain.ExternalProvider, externalID string) (*domain.ExternalAccount, error) {
+	return a.external.GetExternalAccount(ctx, provider, externalID)
+}
+
+func (a *PostgreSQLAdapter) RecordWebhookEvent(ctx context.Context, partner domain.ExternalProvider, eventID string) (bool, error) {
+	return a.external.RecordWebhookEvent(ctx, partner, eventID)
+}
+
+// Методы для работы с журналом поинтов
+func (a *PostgreSQLAdapter) AddLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, error) {
+	return a.ledger.AddLedgerEntry(ctx, entry)
+}
+
+func (a *PostgreSQLAdapter) DebitLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, bool, error) {
+	return a.ledger.DebitLedgerEntry(ctx, entry)
+}
+
+func (a *PostgreSQLAdapter) AlignBackfilledPointLots(ctx context.Context) (int, error) {
+	return a.ledger.AlignBackfilledPointLots(ctx)
+}
+
+func (a *PostgreSQLAdapter) ListUsersWithExpiredPoints(ctx context.Context, now time.Time, limit int) ([]domain.UserID, error) {
+	return a.ledger.ListUsersWithExpiredPoints(ctx, now, limit)
+}
+
+func (a *PostgreSQLAdapter) ExpirePointLots(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.PointLot, error) {
+	return a.ledger.ExpirePointLots(ctx, userID, now)
+}
+
+func (a *PostgreSQLAdapter) SumExpiringPoints(ctx context.Context, userID domain.UserID, until time.Time) (int, error) {
+	return a.ledger.SumExpiringPoints(ctx, userID, until)
+}
+
+// Методы для работы с магазином наград
+func (a *PostgreSQLAdapter) CreateReward(ctx context.Context, reward domain.Reward) error {
+	return a.reward.CreateReward(ctx, reward)
+}
+
+func (a *PostgreSQLAdapter) UpdateReward(ctx context.Context, reward domain.Reward) error {
+	return a.reward.UpdateReward(ctx, reward)
+}
+
+func (a *PostgreSQLAdapter) GetReward(ctx context.Context, rewardID domain.RewardID) (*domain.Reward, error) {
+	return a.reward.GetReward(ctx, rewardID)
+}
+
+func (a *PostgreSQLAdapter) ListRewards(ctx context.Context, includeUnavailable bool, now time.Time) ([]domain.Reward, error) {
+	return a.reward.ListRewards(ctx, includeUnavailable, now)
+}
+
+func (a *PostgreSQLAdapter) ReserveRewardStock(ctx context.Context, rewardID domain.RewardID) (bool, error) {
+	return a.reward.ReserveRewardStock(ctx, rewardID)
+}
+
+func (a *PostgreSQLAdapter) CreateRewardOrder(ctx context.Context, order domain.RewardOrder) error {
+	return a.reward.CreateRewardOrder(ctx, order)
+}
+
+func (a *PostgreSQLAdapter) GetRewardOrder(ctx context.Context, orderID domain.RewardOrderID) (*domain.RewardOrder, error) {
+	return a.reward.GetRewardOrder(ctx, orderID)
+}
+
+func (a *PostgreSQLAdapter) CountRewardOrdersByUser(ctx context.Context, userID domain.UserID, rewardID domain.RewardID) (int, error) {
+	return a.reward.CountRewardOrdersByUser(ctx, userID, rewardID)
+}
+
+func (a *PostgreSQLAdapter) ListRewardOrdersByUserID(ctx context.Context, userID domain.UserID) ([]domain.RewardOrder, error) {
+	return a.reward.ListRewardOrdersByUserID(ctx, userID)
+}
+
+func (a *PostgreSQLAdapter) ListRewardOrdersByStatus(ctx context.Context, status domain.RewardOrderStatus, limit int) ([]domain.RewardOrder, error) {
+	return a.reward.ListRewardOrdersByStatus(ctx, status, limit)
+}
+
+func (a *PostgreSQLAdapter) FulfillRewardOrder(ctx context.Context, order domain.RewardOrder) (bool, error) {
+	return a.reward.FulfillRewardOrder(ctx, order)
+}
+
+// Методы для работы со значками
+func (a *PostgreSQLAdapter) AwardBadge(ctx context.Context, badge domain.UserBadge) (bool, error) {
+	return a.badge.AwardBadge(ctx, badge)
+}
+
+func (a *PostgreSQLAdapter) GetUserBadges(ctx context.Context, userID domain.UserID) ([]domain.UserBadge, error) {
+	return a.badge.GetUserBadges(ctx, userID)
+}
+
+// Методы для работы с сезонами
+func (a *PostgreSQLAdapter) CreateSeason(ctx context.Context, season domain.Season) error {
+	return a.season.CreateSeason(ctx, season)
+}
+
+func (a *PostgreSQLAdapter) HasOverlappingSeason(ctx context.Context, startsAt, endsAt time.Time) (bool, error) {
+	return a.season.HasOverlappingSeason(ctx, startsAt, endsAt)
+}
+
+func (a *PostgreSQLAdapter) GetSeason(ctx context.Context, seasonID domain.SeasonID) (*domain.Season, error) {
+	return a.season.GetSeason(ctx, seasonID)
+}
+
+func (a *PostgreSQLAdapter) ListSeasons(ctx context.Context) ([]domain.Season, error) {
+	return a.season.ListSeasons(ctx)
+}
+
+func (a *PostgreSQLAdapter) ListSeasonsToFinalize(ctx context.Context, now time.Time) ([]domain.Season, error) {
+	return a.season.ListSeasonsToFinalize(ctx, now)
+}
+
+func (a *PostgreSQLAdapter) FinalizeSeason(ctx context.Context, season domain.Season, finalizedAt time.Time) (bool, error) {
+	return a.season.FinalizeSeason(ctx, season, finalizedAt)
+}
+
+func (a *PostgreSQLAdapter) GetSeasonStandings(ctx context.Context, seasonID domain.SeasonID, afterRank, limit int) ([]domain.SeasonStanding, error) {
+	return a.season.GetSeasonStandings(ctx, seasonID, afterRank, limit)
+}
+
+func (a *PostgreSQLAdapter) CountSeasonStandings(ctx context.Context, seasonID domain.SeasonID) (int, error) {
+	return a.season.CountSeasonStandings(ctx, seasonID)
+}
+
+// Методы для работы с refresh токенами
+func (a *PostgreSQLAdapter) CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	return a.refreshToken.CreateRefreshToken(ctx, token)
+}
+
+func (a *PostgreSQLAdapter) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	return a.refreshToken.GetRefreshTokenByHash(ctx, tokenHash)
+}
+
+func (a *PostgreSQLAdapter) MarkRefreshTokenUsed(ctx context.Context, tokenID domain.RefreshTokenID) (bool, error) {
+	return a.refreshToken.MarkRefreshTokenUsed(ctx, tokenID)
+}
+
+func (a *PostgreSQLAdapter) RevokeRefreshTokenFamily(ctx context.Context, familyID domain.RefreshTokenID) error {
+	return a.refreshToken.RevokeRefreshTokenFamily(ctx, familyID)
+}
+
+// Методы для работы с кодами входа
+func (a *PostgreSQLAdapter) CreateLoginCode(ctx context.Context, code domain.LoginCode) error {
+	return a.loginCode.CreateLoginCode(ctx, code)
+}
+
+func (a *PostgreSQLAdapter) GetLatestLoginCode(ctx context.Context, userID domain.UserID) (*domain.LoginCode, error) {
+	return a.loginCode.GetLatestLoginCode(ctx, userID)
+}
+
+func (a *PostgreSQLAdapter) ClaimLoginCodeAttempt(ctx context.Context, codeID domain.LoginCodeID, now time.Time) (bool, error) {
+	return a.loginCode.ClaimLoginCodeAttempt(ctx, codeID, now)
+}
+
+func (a *PostgreSQLAdapter) ConsumeLoginCode(ctx context.Context, codeID domain.LoginCodeID) (bool, error) {
+	return a.loginCode.ConsumeLoginCode(ctx, codeID)
+}
+
+func (a *PostgreSQLAdapter) RecordLoginCodeRequest(ctx context.Context, email, ip string, since time.Time) (int, int, error) {
+	return a.loginCode.RecordLoginCodeRequest(ctx, email, ip, since)
+}
+
+func (a *PostgreSQLAdapter) DeleteLoginCodeRequests(ctx context.Context, before time.Time) (int64, error) {
+	return a.loginCode.DeleteLoginCodeRequests(ctx, before)
+}
+
 // Методы для работы с транзакциями
 func (a *PostgreSQLAdapter) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	return a.transaction.WithTransaction(ctx, fn)
 }
+
+// toLeaderboardEntries преобразует записи таблицы лидеров адаптера в тип use case слоя
+func toLeaderboardEntries(entries []leaderboardEntry) []usecases.LeaderboardEntry {
+	result := make([]usecases.LeaderboardEntry, len(entries))
+	for i := range entries {
+		result[i] = usecases.LeaderboardEntry{
+			Rank:      entries[i].Rank,
+			UserID:    entries[i].UserID,
+			Username:  entries[i].Username,
+			Balance:   entries[i].Balance,
+			CreatedAt: entries[i].CreatedAt,
+		}
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLBadgeAdapter адаптер для работы со значками пользователей в PostgreSQL
+type PostgreSQLBadgeAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLBadgeAdapter создает новый адаптер значков
+func NewPostgreSQLBadgeAdapter(db *sqlx.DB) *PostgreSQLBadgeAdapter {
+	return &PostgreSQLBadgeAdapter{db: db}
+}
+
+// AwardBadge сохраняет выдачу значка пользователю.
+// Возвращает false, если пользователь уже получил этот значок.
+func (a *PostgreSQLBadgeAdapter) AwardBadge(ctx context.Context, badge domain.UserBadge) (bool, error) {
+	query := `
+		INSERT INTO user_badges (user_id, badge_key, awarded_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, badge_key) DO NOTHING
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query, badge.UserID.Value(), badge.BadgeKey, badge.AwardedAt)
+	if err != nil {
+		return false, err
+	}
+
+	awarded, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return awarded > 0, nil
+}
+
+// GetUserBadges получает значки пользователя в порядке получения
+func (a *PostgreSQLBadgeAdapter) GetUserBadges(ctx context.Context, userID domain.UserID) ([]domain.UserBadge, error) {
+	var rows []struct {
+		BadgeKey  string    `db:"badge_key"`
+		AwardedAt time.Time `db:"awarded_at"`
+	}
+
+	query := `
+		SELECT badge_key, awarded_at
+		FROM user_badges
+		WHERE user_id = $1
+		ORDER BY awarded_at, badge_key
+	`
+
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, userID.Value()); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса: %w", err)
+	}
+
+	result := make([]domain.UserBadge, len(rows))
+	for i, row := range rows {
+		result[i] = domain.UserBadge{
+			UserID:    userID,
+			BadgeKey:  row.BadgeKey,
+			AwardedAt: row.AwardedAt,
+		}
+	}
+
+	return result, nil
+}
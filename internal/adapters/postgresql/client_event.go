@@ -0,0 +1,67 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLClientEventAdapter адаптер для работы с отпечатками клиентов в PostgreSQL
+type PostgreSQLClientEventAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLClientEventAdapter создает новый адаптер отпечатков клиентов
+func NewPostgreSQLClientEventAdapter(db *sqlx.DB) *PostgreSQLClientEventAdapter {
+	return &PostgreSQLClientEventAdapter{db: db}
+}
+
+// RecordClientEvent сохраняет отпечаток клиента
+func (a *PostgreSQLClientEventAdapter) RecordClientEvent(ctx context.Context, event domain.ClientEvent) error {
+	query := `
+		INSERT INTO client_events (user_id, kind, ip, user_agent_hash, device_id, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		event.UserID.Value(), string(event.Kind), event.Fingerprint.IP,
+		event.Fingerprint.UserAgentHash, event.Fingerprint.DeviceID, event.CreatedAt)
+	return err
+}
+
+// CountUsersSharingFingerprint подсчитывает других пользователей, у которых начиная с since
+// встречался тот же IP адрес или тот же идентификатор устройства
+func (a *PostgreSQLClientEventAdapter) CountUsersSharingFingerprint(ctx context.Context, fingerprint domain.ClientFingerprint, excludeUserID domain.UserID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT user_id)
+		FROM client_events
+		WHERE user_id <> $3 AND created_at >= $4
+			AND (ip = $1 OR (device_id IS NOT NULL AND device_id = NULLIF($2, '')))
+	`
+
+	var count int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, query,
+		fingerprint.IP, fingerprint.DeviceID, excludeUserID.Value(), since)
+	return count, err
+}
+
+// UserSharesFingerprint проверяет, встречался ли у пользователя начиная с since
+// тот же IP адрес или тот же идентификатор устройства
+func (a *PostgreSQLClientEventAdapter) UserSharesFingerprint(ctx context.Context, userID domain.UserID, fingerprint domain.ClientFingerprint, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM client_events
+			WHERE user_id = $3 AND created_at >= $4
+				AND (ip = $1 OR (device_id IS NOT NULL AND device_id = NULLIF($2, '')))
+		)
+	`
+
+	var exists bool
+	err := getQuerier(ctx, a.db).GetContext(ctx, &exists, query,
+		fingerprint.IP, fingerprint.DeviceID, userID.Value(), since)
+	return exists, err
+}
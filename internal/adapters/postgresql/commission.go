@@ -0,0 +1,116 @@
+package postgresql
+
+import (
+	"context"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLCommissionAdapter адаптер для работы с реферальными комиссиями в PostgreSQL
+type PostgreSQLCommissionAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLCommissionAdapter создает новый адаптер реферальных комиссий
+func NewPostgreSQLCommissionAdapter(db *sqlx.DB) *PostgreSQLCommissionAdapter {
+	return &PostgreSQLCommissionAdapter{db: db}
+}
+
+// GetReferralChain получает цепочку рефереров пользователя не глубже depth уровней:
+// первым идет прямой реферер, затем его реферер и так далее.
+// В цепочку входят только завершенные (vested) реферальные связи: ожидающие, задержанные
+// на проверку и отмененные связи комиссий не приносят. Цепочка обрывается, если реферер
+// уже встречался в ней, поэтому циклы в referrals не приводят к зацикливанию.
+func (a *PostgreSQLCommissionAdapter) GetReferralChain(ctx context.Context, userID domain.UserID, depth int) ([]domain.UserID, error) {
+	var rows []string
+
+	query := `
+		WITH RECURSIVE chain (user_id, level, path) AS (
+			SELECT r.referrer_id, 1, ARRAY[r.referred_user_id, r.referrer_id]
+			FROM referrals r
+			WHERE r.referred_user_id = $1 AND r.status = 'vested'
+			UNION ALL
+			SELECT r.referrer_id, c.level + 1, c.path || r.referrer_id
+			FROM chain c
+			JOIN referrals r ON r.referred_user_id = c.user_id
+			WHERE c.level < $2 AND r.status = 'vested' AND NOT r.referrer_id = ANY(c.path)
+		)
+		SELECT user_id
+		FROM chain
+		WHERE level <= $2
+		ORDER BY level ASC
+	`
+
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, userID.Value(), depth); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserID, 0, len(rows))
+	for _, row := range rows {
+		referrerID, err := domain.UserIDFromString(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, referrerID)
+	}
+	return result, nil
+}
+
+// CreateReferralCommission сохраняет комиссию.
+// Возвращает false, если комиссия с этой записи журнала этому рефереру уже начислена.
+func (a *PostgreSQLCommissionAdapter) CreateReferralCommission(ctx context.Context, commission domain.ReferralCommission) (bool, error) {
+	query := `
+		INSERT INTO referral_commissions (id, beneficiary_id, earner_id, level, source_entry_id, base_points, rate_bps, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (source_entry_id, beneficiary_id) DO NOTHING
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		commission.ID.Value(), commission.BeneficiaryID.Value(), commission.EarnerID.Value(),
+		commission.Level, commission.SourceEntryID.Value(), commission.BasePoints,
+		commission.Rate, commission.Amount, commission.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return created > 0, nil
+}
+
+// GetCommissionSummary получает сводку комиссий пользователя по уровням цепочки
+func (a *PostgreSQLCommissionAdapter) GetCommissionSummary(ctx context.Context, beneficiaryID domain.UserID) ([]domain.CommissionLevelSummary, error) {
+	var rows []struct {
+		Level       int `db:"level"`
+		Referrals   int `db:"referrals"`
+		Commissions int `db:"commissions"`
+		Points      int `db:"points"`
+	}
+
+	query := `
+		SELECT level, COUNT(DISTINCT earner_id) AS referrals, COUNT(*) AS commissions, SUM(amount) AS points
+		FROM referral_commissions
+		WHERE beneficiary_id = $1
+		GROUP BY level
+		ORDER BY level ASC
+	`
+
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, beneficiaryID.Value()); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.CommissionLevelSummary, len(rows))
+	for i, row := range rows {
+		result[i] = domain.CommissionLevelSummary{
+			Level:       row.Level,
+			Referrals:   row.Referrals,
+			Commissions: row.Commissions,
+			Points:      row.Points,
+		}
+	}
+	return result, nil
+}
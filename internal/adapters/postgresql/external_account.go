@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLExternalAccountAdapter адаптер для работы с внешними аккаунтами и событиями партнеров в PostgreSQL
+type PostgreSQLExternalAccountAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLExternalAccountAdapter создает новый адаптер внешних аккаунтов
+func NewPostgreSQLExternalAccountAdapter(db *sqlx.DB) *PostgreSQLExternalAccountAdapter {
+	return &PostgreSQLExternalAccountAdapter{db: db}
+}
+
+// CreateExternalAccount привязывает внешний аккаунт к пользователю.
+// Возвращает domain.ErrExternalAccountLinked, если аккаунт уже привязан
+// или у пользователя уже есть аккаунт в этом сервисе.
+func (a *PostgreSQLExternalAccountAdapter) CreateExternalAccount(ctx context.Context, account domain.ExternalAccount) error {
+	query := `
+		INSERT INTO external_accounts (provider, external_id, user_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		account.Provider.String(), account.ExternalID, account.UserID.Value(), account.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if inserted == 0 {
+		return domain.ErrExternalAccountLinked
+	}
+	return nil
+}
+
+// GetExternalAccount получает привязку внешнего аккаунта
+func (a *PostgreSQLExternalAccountAdapter) GetExternalAccount(ctx context.Context, provider domain.ExternalProvider, externalID string) (*domain.ExternalAccount, error) {
+	var account struct {
+		Provider   string    `db:"provider"`
+		ExternalID string    `db:"external_id"`
+		UserID     string    `db:"user_id"`
+		CreatedAt  time.Time `db:"created_at"`
+	}
+
+	query := `
+		SELECT provider, external_id, user_id, created_at
+		FROM external_accounts
+		WHERE provider = $1 AND external_id = $2
+	`
+
+	err := getQuerier(ctx, a.db).GetContext(ctx, &account, query, provider.String(), externalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	userID, err := domain.UserIDFromString(account.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ExternalAccount{
+		Provider:   domain.ExternalProvider(account.Provider),
+		ExternalID: account.ExternalID,
+		UserID:     userID,
+		CreatedAt:  account.CreatedAt,
+	}, nil
+}
+
+// RecordWebhookEvent сохраняет идентификатор события партнера.
+// Возвращает false, если событие уже было обработано.
+func (a *PostgreSQLExternalAccountAdapter) RecordWebhookEvent(ctx context.Context, partner domain.ExternalProvider, eventID string) (bool, error) {
+	query := `
+		INSERT INTO webhook_events (partner, event_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query, partner.String(), eventID)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return inserted > 0, nil
+}
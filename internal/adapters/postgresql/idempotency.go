@@ -0,0 +1,118 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLIdempotencyAdapter хранилище ключей идемпотентности в PostgreSQL
+type PostgreSQLIdempotencyAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLIdempotencyAdapter создает новое хранилище ключей идемпотентности
+func NewPostgreSQLIdempotencyAdapter(db *sqlx.DB) *PostgreSQLIdempotencyAdapter {
+	return &PostgreSQLIdempotencyAdapter{db: db}
+}
+
+// Reserve резервирует ключ идемпотентности.
+// Если ключ уже занят, возвращает существующую запись, иначе nil.
+func (a *PostgreSQLIdempotencyAdapter) Reserve(ctx context.Context, record domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	deleteQuery := `
+		DELETE FROM idempotency_keys
+		WHERE user_scope = $1 AND idempotency_key = $2 AND route = $3 AND expires_at <= $4
+	`
+	if _, err := a.db.ExecContext(ctx, deleteQuery, record.Scope, record.Key, record.Route, record.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO idempotency_keys (user_scope, idempotency_key, route, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_scope, idempotency_key, route) DO NOTHING
+	`
+	result, err := a.db.ExecContext(ctx, insertQuery,
+		record.Scope, record.Key, record.Route, record.RequestHash, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if inserted > 0 {
+		return nil, nil
+	}
+
+	var existing struct {
+		RequestHash  string        `db:"request_hash"`
+		StatusCode   sql.NullInt64 `db:"status_code"`
+		ResponseBody []byte        `db:"response_body"`
+		CreatedAt    time.Time     `db:"created_at"`
+		ExpiresAt    time.Time     `db:"expires_at"`
+	}
+
+	selectQuery := `
+		SELECT request_hash, status_code, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_scope = $1 AND idempotency_key = $2 AND route = $3
+	`
+	err = a.db.GetContext(ctx, &existing, selectQuery, record.Scope, record.Key, record.Route)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// запись успели удалить между вставкой и чтением, пробуем еще раз
+			return a.Reserve(ctx, record)
+		}
+		return nil, err
+	}
+
+	return &domain.IdempotencyRecord{
+		Scope:        record.Scope,
+		Key:          record.Key,
+		Route:        record.Route,
+		RequestHash:  existing.RequestHash,
+		StatusCode:   int(existing.StatusCode.Int64),
+		ResponseBody: existing.ResponseBody,
+		CreatedAt:    existing.CreatedAt,
+		ExpiresAt:    existing.ExpiresAt,
+	}, nil
+}
+
+// Complete сохраняет ответ на запрос с ключом идемпотентности
+func (a *PostgreSQLIdempotencyAdapter) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $4, response_body = $5
+		WHERE user_scope = $1 AND idempotency_key = $2 AND route = $3
+	`
+
+	_, err := a.db.ExecContext(ctx, query,
+		record.Scope, record.Key, record.Route, record.StatusCode, record.ResponseBody)
+	return err
+}
+
+// Release освобождает ключ идемпотентности, чтобы запрос можно было повторить
+func (a *PostgreSQLIdempotencyAdapter) Release(ctx context.Context, record domain.IdempotencyRecord) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE user_scope = $1 AND idempotency_key = $2 AND route = $3
+	`
+
+	_, err := a.db.ExecContext(ctx, query, record.Scope, record.Key, record.Route)
+	return err
+}
+
+// DeleteExpired удаляет записи с истекшим сроком хранения
+func (a *PostgreSQLIdempotencyAdapter) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := a.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,119 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/adapters/postgresql"
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+)
+
+// grantTestPoints начисляет пользователю поинты через журнал
+func grantTestPoints(t *testing.T, adapter *postgresql.PostgreSQLAdapter, userID domain.UserID, points int) {
+	t.Helper()
+
+	entry, err := domain.NewPointsGrant(userID, points, "тестовое начисление")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adapter.AddLedgerEntry(context.Background(), entry); err != nil {
+		t.Fatalf("начисление поинтов: %v", err)
+	}
+}
+
+func TestLeaderboardRanksFollowCursorPosition(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+	ctx := context.Background()
+
+	// Одинаковые балансы проверяют порядок по created_at и id внутри одного баланса
+	for _, points := range []int{7_000_000, 7_000_000, 7_000_000, 6_000_000, 5_000_000} {
+		user := createTestUser(t, adapter)
+		grantTestPoints(t, adapter, user.ID, points)
+	}
+
+	top, err := adapter.GetLeaderboard(ctx, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range top {
+		position, err := adapter.GetLeaderboardPosition(ctx, mustUserID(t, entry.UserID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if position.Rank != entry.Rank {
+			t.Fatalf("%s: место %d в позиции, %d в странице", entry.UserID, position.Rank, entry.Rank)
+		}
+
+		cursor := position.Cursor()
+		next, err := adapter.GetLeaderboard(ctx, 1, &cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(next) == 1 && next[0].Rank != position.Rank+1 {
+			t.Fatalf("после места %d получено место %d", position.Rank, next[0].Rank)
+		}
+
+		previous, err := adapter.GetLeaderboardBefore(ctx, cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, entry := range previous {
+			if want := position.Rank - len(previous) + i; entry.Rank != want {
+				t.Fatalf("перед местом %d получено место %d, ожидалось %d", position.Rank, entry.Rank, want)
+			}
+		}
+	}
+}
+
+func TestPeriodLeaderboardRanksFollowCursorPosition(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+	ctx := context.Background()
+	since := time.Now().Add(-time.Second)
+
+	for _, points := range []int{30, 20, 20, 10} {
+		user := createTestUser(t, adapter)
+		grantTestPoints(t, adapter, user.ID, points)
+	}
+
+	all, err := adapter.GetPeriodLeaderboard(ctx, since, time.Time{}, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) < 4 {
+		t.Fatalf("в таблице за период %d участников, ожидалось не меньше 4", len(all))
+	}
+
+	var after *usecases.LeaderboardCursor
+	for page := 0; ; page++ {
+		entries, err := adapter.GetPeriodLeaderboard(ctx, since, time.Time{}, 2, after)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+		for i, entry := range entries {
+			want := all[2*page+i]
+			if entry.UserID != want.UserID || entry.Rank != want.Rank {
+				t.Fatalf("страница %d: %s на месте %d, ожидался %s на месте %d",
+					page, entry.UserID, entry.Rank, want.UserID, want.Rank)
+			}
+		}
+
+		cursor := entries[len(entries)-1].Cursor()
+		after = &cursor
+	}
+}
+
+func mustUserID(t *testing.T, value string) domain.UserID {
+	t.Helper()
+
+	userID, err := domain.UserIDFromString(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userID
+}
@@ -0,0 +1,329 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLLedgerAdapter адаптер для работы с журналом поинтов в PostgreSQL
+type PostgreSQLLedgerAdapter struct {
+	db     *sqlx.DB
+	expiry domain.PointsExpiryPolicy
+}
+
+// NewPostgreSQLLedgerAdapter создает новый адаптер журнала поинтов.
+// expiry задает срок действия партий поинтов, создаваемых для начислений.
+func NewPostgreSQLLedgerAdapter(db *sqlx.DB, expiry domain.PointsExpiryPolicy) *PostgreSQLLedgerAdapter {
+	return &PostgreSQLLedgerAdapter{db: db, expiry: expiry}
+}
+
+// AddLedgerEntry добавляет запись в журнал и атомарно изменяет баланс пользователя.
+// Для начисления создается партия поинтов со сроком действия согласно политике адаптера.
+// Возвращает баланс пользователя после применения записи.
+func (a *PostgreSQLLedgerAdapter) AddLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, error) {
+	query := `
+		WITH entry AS (
+			INSERT INTO point_entries (id, user_id, amount, source, reference_id, note, created_at)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($8, ''), $6)
+			RETURNING id, user_id, amount
+		), lot AS (
+			INSERT INTO point_lots (entry_id, user_id, amount, remaining, earned_at, expires_at)
+			SELECT id, user_id, amount, amount, $6, $7 FROM entry WHERE amount > 0
+		)
+		UPDATE users
+		SET balance = users.balance + entry.amount, updated_at = $6
+		FROM entry
+		WHERE users.id = entry.user_id
+		RETURNING users.balance
+	`
+
+	var balance int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &balance, query,
+		entry.ID.Value(), entry.UserID.Value(), entry.Amount,
+		entry.Source.String(), entry.ReferenceID, entry.CreatedAt, a.expiry.ExpiresAt(entry.CreatedAt), entry.Note)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Balance{}, domain.ErrUserNotFound
+		}
+		return domain.Balance{}, err
+	}
+
+	return domain.NewBalance(balance), nil
+}
+
+// DebitLedgerEntry добавляет в журнал запись о списании и уменьшает баланс пользователя,
+// только если на нем достаточно поинтов. Списание расходует партии поинтов, начиная
+// с тех, что сгорают раньше. Возвращает баланс после списания и false,
+// если поинтов не хватает: в этом случае ни баланс, ни журнал не меняются.
+// Должен вызываться внутри транзакции.
+func (a *PostgreSQLLedgerAdapter) DebitLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, bool, error) {
+	if entry.Amount >= 0 {
+		return domain.Balance{}, false, fmt.Errorf("%w: сумма списания должна быть отрицательной", domain.ErrInvalidLedgerEntry)
+	}
+
+	query := `
+		WITH debit AS (
+			UPDATE users
+			SET balance = balance + $3, updated_at = $6
+			WHERE id = $2 AND balance + $3 >= 0
+			RETURNING balance
+		), entry AS (
+			INSERT INTO point_entries (id, user_id, amount, source, reference_id, note, created_at)
+			SELECT $1, $2, $3, $4, $5, NULLIF($7, ''), $6 FROM debit
+		)
+		SELECT balance FROM debit
+	`
+
+	var balance int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &balance, query,
+		entry.ID.Value(), entry.UserID.Value(), entry.Amount,
+		entry.Source.String(), entry.ReferenceID, entry.CreatedAt, entry.Note)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Balance{}, false, nil
+		}
+		return domain.Balance{}, false, err
+	}
+
+	if err := a.consumePointLots(ctx, entry.UserID, -entry.Amount); err != nil {
+		return domain.Balance{}, false, err
+	}
+
+	return domain.NewBalance(balance), true, nil
+}
+
+// consumePointLots расходует points поинтов из непотраченных партий пользователя
+// в порядке сгорания. Партии читаются отдельным запросом после списания с баланса,
+// когда строка пользователя уже заблокирована и партии не могут измениться.
+func (a *PostgreSQLLedgerAdapter) consumePointLots(ctx context.Context, userID domain.UserID, points int) error {
+	query := `
+		WITH open AS (
+			SELECT entry_id, remaining,
+				SUM(remaining) OVER (ORDER BY expires_at, earned_at, entry_id) - remaining AS consumed_before
+			FROM point_lots
+			WHERE user_id = $1 AND remaining > 0
+		)
+		UPDATE point_lots l
+		SET remaining = l.remaining - LEAST(open.remaining, $2 - open.consumed_before)
+		FROM open
+		WHERE l.entry_id = open.entry_id AND open.consumed_before < $2
+	`
+
+	if _, err := getQuerier(ctx, a.db).ExecContext(ctx, query, userID.Value(), points); err != nil {
+		return fmt.Errorf("ошибка списания партий поинтов: %w", err)
+	}
+	return nil
+}
+
+// pointLotRow представляет строку партии поинтов из базы данных
+type pointLotRow struct {
+	EntryID   string    `db:"entry_id"`
+	UserID    string    `db:"user_id"`
+	Amount    int       `db:"amount"`
+	Remaining int       `db:"remaining"`
+	EarnedAt  time.Time `db:"earned_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func (r pointLotRow) toDomain() (domain.PointLot, error) {
+	entryID, err := domain.LedgerEntryIDFromString(r.EntryID)
+	if err != nil {
+		return domain.PointLot{}, err
+	}
+	userID, err := domain.UserIDFromString(r.UserID)
+	if err != nil {
+		return domain.PointLot{}, err
+	}
+
+	return domain.PointLot{
+		EntryID:   entryID,
+		UserID:    userID,
+		Amount:    r.Amount,
+		Remaining: r.Remaining,
+		EarnedAt:  r.EarnedAt,
+		ExpiresAt: r.ExpiresAt,
+	}, nil
+}
+
+// AlignBackfilledPointLots пересчитывает срок действия партий, разложенных миграцией по политике
+// по умолчанию, согласно политике адаптера. Нижняя граница срока, заданная миграцией, сохраняется.
+// Возвращает число партий, срок которых изменился.
+func (a *PostgreSQLLedgerAdapter) AlignBackfilledPointLots(ctx context.Context) (int, error) {
+	query := `
+		UPDATE point_lots
+		SET expires_at = GREATEST(earned_at + make_interval(months => $1), min_expires_at)
+		WHERE min_expires_at IS NOT NULL AND remaining > 0
+			AND expires_at <> GREATEST(earned_at + make_interval(months => $1), min_expires_at)
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query, a.expiry.Months)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка выполнения SQL запроса: %w", err)
+	}
+
+	aligned, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(aligned), nil
+}
+
+// ListUsersWithExpiredPoints получает пользователей, у которых есть партии поинтов,
+// сгоревшие к моменту now, но еще не списанные
+func (a *PostgreSQLLedgerAdapter) ListUsersWithExpiredPoints(ctx context.Context, now time.Time, limit int) ([]domain.UserID, error) {
+	query := `
+		SELECT DISTINCT user_id
+		FROM point_lots
+		WHERE expires_at <= $1 AND remaining > 0
+		LIMIT $2
+	`
+
+	var ids []string
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &ids, query, now, limit); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса: %w", err)
+	}
+
+	result := make([]domain.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := domain.UserIDFromString(id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, userID)
+	}
+
+	return result, nil
+}
+
+// ExpirePointLots обнуляет остаток партий поинтов пользователя, сгоревших к моменту now.
+// Возвращает обнуленные партии, Remaining которых равен сгоревшему остатку.
+// Баланс пользователя не меняется: списание сгоревших поинтов записывается в журнал отдельно.
+func (a *PostgreSQLLedgerAdapter) ExpirePointLots(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.PointLot, error) {
+	query := `
+		WITH expired AS (
+			SELECT entry_id, remaining
+			FROM point_lots
+			WHERE user_id = $1 AND expires_at <= $2 AND remaining > 0
+			FOR UPDATE
+		)
+		UPDATE point_lots l
+		SET remaining = 0
+		FROM expired
+		WHERE l.entry_id = expired.entry_id
+		RETURNING l.entry_id, l.user_id, l.amount, expired.remaining, l.earned_at, l.expires_at
+	`
+
+	var rows []pointLotRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, userID.Value(), now); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса: %w", err)
+	}
+
+	result := make([]domain.PointLot, 0, len(rows))
+	for _, row := range rows {
+		lot, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, lot)
+	}
+
+	return result, nil
+}
+
+// SumExpiringPoints получает число непотраченных поинтов пользователя, которые сгорят до until
+func (a *PostgreSQLLedgerAdapter) SumExpiringPoints(ctx context.Context, userID domain.UserID, until time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(remaining), 0)
+		FROM point_lots
+		WHERE user_id = $1 AND expires_at < $2 AND remaining > 0
+	`
+
+	var points int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &points, query, userID.Value(), until)
+	return points, err
+}
+
+// GetPeriodLeaderboard получает страницу таблицы лидеров по поинтам, заработанным начиная с since и до until.
+// Нулевой until означает, что период не ограничен сверху.
+// Учитываются только начисления, в поле Balance записи возвращается сумма за период.
+// Порядок сортировки при равенстве поинтов совпадает с GetLeaderboard.
+// Места считаются по числу участников до позиции курсора в той же выборке поинтов за период.
+func (a *PostgreSQLLedgerAdapter) GetPeriodLeaderboard(ctx context.Context, since, until time.Time, limit int, after *usecases.LeaderboardCursor) ([]leaderboardEntry, error) {
+	query := `
+		WITH scores AS (
+			SELECT user_id, SUM(amount) AS points
+			FROM point_entries
+			WHERE amount > 0 AND created_at >= $1
+				AND ($2::timestamp IS NULL OR created_at < $2)
+				AND NOT (source = 'admin' AND reference_id = 'opening_balance')
+			GROUP BY user_id
+		),
+		ahead AS (
+			SELECT COUNT(*) AS count
+			FROM scores s
+			JOIN users u ON u.id = s.user_id
+			WHERE $3 AND (s.points > $4 OR (s.points = $4 AND (u.created_at, u.id) <= ($5, $6::uuid)))
+		)
+		SELECT u.id::text AS user_id, u.username, s.points AS balance, u.created_at, a.count AS ahead
+		FROM scores s
+		JOIN users u ON u.id = s.user_id
+		CROSS JOIN ahead a
+		WHERE NOT $3
+			OR s.points < $4
+			OR (s.points = $4 AND (u.created_at, u.id) > ($5, $6::uuid))
+		ORDER BY s.points DESC, u.created_at ASC, u.id ASC
+		LIMIT $7
+	`
+
+	cursor := usecases.LeaderboardCursor{UserID: "00000000-0000-0000-0000-000000000000"}
+	if after != nil {
+		cursor = *after
+	}
+
+	var rows []rankedLeaderboardRow
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query,
+		since, nullTimeIfZero(until), after != nil, cursor.Balance, cursor.CreatedAt, cursor.UserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса leaderboard за период: %w", err)
+	}
+
+	result := make([]leaderboardEntry, len(rows))
+	for i := range rows {
+		result[i] = leaderboardEntry{
+			Rank:      rows[i].Ahead + i + 1,
+			UserID:    rows[i].UserID,
+			Username:  rows[i].Username,
+			Balance:   rows[i].Balance,
+			CreatedAt: rows[i].CreatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// CountPeriodLeaderboardUsers получает число пользователей, заработавших поинты начиная с since и до until
+func (a *PostgreSQLLedgerAdapter) CountPeriodLeaderboardUsers(ctx context.Context, since, until time.Time) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT user_id)
+		FROM point_entries
+		WHERE amount > 0 AND created_at >= $1
+			AND ($2::timestamp IS NULL OR created_at < $2)
+			AND NOT (source = 'admin' AND reference_id = 'opening_balance')
+	`
+
+	var count int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, query, since, nullTimeIfZero(until))
+	return count, err
+}
+
+// nullTimeIfZero преобразует нулевое время в NULL
+func nullTimeIfZero(value time.Time) sql.NullTime {
+	return sql.NullTime{Time: value, Valid: !value.IsZero()}
+}
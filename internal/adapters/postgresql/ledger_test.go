@@ -0,0 +1,78 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+
+	"user-rewards-api/internal/adapters/postgresql"
+	"user-rewards-api/internal/domain"
+)
+
+func TestAddLedgerEntryStoresGrantReason(t *testing.T) {
+	adapter, db := newTestAdapter(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, adapter)
+	entry, err := domain.NewPointsGrant(user.ID, 50, "  компенсация за сбой  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adapter.AddLedgerEntry(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	var note string
+	if err := db.Get(&note, `SELECT note FROM point_entries WHERE id = $1`, entry.ID.Value()); err != nil {
+		t.Fatal(err)
+	}
+	if note != "компенсация за сбой" {
+		t.Fatalf("сохранена причина %q", note)
+	}
+}
+
+func TestAlignBackfilledPointLotsFollowsPolicy(t *testing.T) {
+	adapter, db := newTestAdapter(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, adapter)
+	for _, points := range []int{10, 20, 30} {
+		grantTestPoints(t, adapter, user.ID, points)
+	}
+
+	// Партии на 10 и 20 поинтов разложены миграцией по политике по умолчанию, партия на 30 создана приложением
+	if _, err := db.Exec(`
+		UPDATE point_lots
+		SET earned_at = CASE amount WHEN 10 THEN CURRENT_TIMESTAMP - INTERVAL '11 months' ELSE CURRENT_TIMESTAMP - INTERVAL '1 month' END,
+			min_expires_at = CURRENT_TIMESTAMP + INTERVAL '30 days'
+		WHERE user_id = $1 AND amount IN (10, 20)
+	`, user.ID.Value()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`
+		UPDATE point_lots SET expires_at = GREATEST(earned_at + INTERVAL '12 months', min_expires_at)
+		WHERE user_id = $1 AND min_expires_at IS NOT NULL
+	`, user.ID.Value()); err != nil {
+		t.Fatal(err)
+	}
+
+	expiry, err := domain.NewPointsExpiryPolicy(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := postgresql.NewPostgreSQLAdapter(db, expiry).AlignBackfilledPointLots(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countRows(t, db, `
+		SELECT COUNT(*) FROM point_lots
+		WHERE user_id = $1 AND expires_at = GREATEST(earned_at + INTERVAL '6 months', min_expires_at)
+	`, user.ID.Value()); n != 2 {
+		t.Errorf("по действующей политике пересчитано %d партий, ожидалось 2", n)
+	}
+	if n := countRows(t, db, `
+		SELECT COUNT(*) FROM point_lots
+		WHERE user_id = $1 AND min_expires_at IS NULL AND expires_at = earned_at + INTERVAL '12 months'
+	`, user.ID.Value()); n != 1 {
+		t.Errorf("срок партии, созданной приложением, изменился")
+	}
+}
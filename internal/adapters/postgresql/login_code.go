@@ -0,0 +1,173 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLLoginCodeAdapter адаптер для работы с одноразовыми кодами входа в PostgreSQL
+type PostgreSQLLoginCodeAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLLoginCodeAdapter создает новый адаптер кодов входа
+func NewPostgreSQLLoginCodeAdapter(db *sqlx.DB) *PostgreSQLLoginCodeAdapter {
+	return &PostgreSQLLoginCodeAdapter{db: db}
+}
+
+// CreateLoginCode сохраняет новый код входа, погашая ранее выданные коды пользователя
+func (a *PostgreSQLLoginCodeAdapter) CreateLoginCode(ctx context.Context, code domain.LoginCode) error {
+	q := getQuerier(ctx, a.db)
+
+	invalidateQuery := `
+		UPDATE login_codes
+		SET consumed_at = $2
+		WHERE user_id = $1 AND consumed_at IS NULL
+	`
+	if _, err := q.ExecContext(ctx, invalidateQuery, code.UserID.Value(), code.CreatedAt); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO login_codes (id, user_id, code_hash, attempts, max_attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := q.ExecContext(ctx, insertQuery,
+		code.ID.Value(), code.UserID.Value(), code.CodeHash, code.Attempts,
+		code.MaxAttempts, code.ExpiresAt, code.CreatedAt)
+	return err
+}
+
+// GetLatestLoginCode получает последний непогашенный код входа пользователя
+func (a *PostgreSQLLoginCodeAdapter) GetLatestLoginCode(ctx context.Context, userID domain.UserID) (*domain.LoginCode, error) {
+	var code struct {
+		ID          string       `db:"id"`
+		UserID      string       `db:"user_id"`
+		CodeHash    string       `db:"code_hash"`
+		Attempts    int          `db:"attempts"`
+		MaxAttempts int          `db:"max_attempts"`
+		ExpiresAt   time.Time    `db:"expires_at"`
+		ConsumedAt  sql.NullTime `db:"consumed_at"`
+		CreatedAt   time.Time    `db:"created_at"`
+	}
+
+	query := `
+		SELECT id, user_id, code_hash, attempts, max_attempts, expires_at, consumed_at, created_at
+		FROM login_codes
+		WHERE user_id = $1 AND consumed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err := getQuerier(ctx, a.db).GetContext(ctx, &code, query, userID.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	codeID, err := domain.LoginCodeIDFromString(code.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	domainUserID, err := domain.UserIDFromString(code.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginCode{
+		ID:          codeID,
+		UserID:      domainUserID,
+		CodeHash:    code.CodeHash,
+		Attempts:    code.Attempts,
+		MaxAttempts: code.MaxAttempts,
+		ExpiresAt:   code.ExpiresAt,
+		ConsumedAt:  nullTimePtr(code.ConsumedAt),
+		CreatedAt:   code.CreatedAt,
+	}, nil
+}
+
+// ClaimLoginCodeAttempt засчитывает попытку ввода кода до его проверки.
+// Счетчик увеличивается одним условным UPDATE, поэтому параллельные попытки
+// не могут превысить лимит. Возвращает false, если код погашен, истек или исчерпал попытки.
+func (a *PostgreSQLLoginCodeAdapter) ClaimLoginCodeAttempt(ctx context.Context, codeID domain.LoginCodeID, now time.Time) (bool, error) {
+	query := `
+		UPDATE login_codes
+		SET attempts = attempts + 1
+		WHERE id = $1 AND consumed_at IS NULL AND attempts < max_attempts AND expires_at > $2
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query, codeID.Value(), now)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// ConsumeLoginCode погашает код входа, попытка ввода которого уже засчитана.
+// Возвращает false, если код уже был погашен.
+func (a *PostgreSQLLoginCodeAdapter) ConsumeLoginCode(ctx context.Context, codeID domain.LoginCodeID) (bool, error) {
+	query := `
+		UPDATE login_codes
+		SET consumed_at = $2
+		WHERE id = $1 AND consumed_at IS NULL AND attempts <= max_attempts
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query, codeID.Value(), time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// RecordLoginCodeRequest сохраняет запрос кода входа и возвращает число запросов на email
+// и с IP адреса начиная с since, включая сохраненный.
+// Параллельные запросы не видят друг друга, поэтому лимит может быть превышен на их число.
+func (a *PostgreSQLLoginCodeAdapter) RecordLoginCodeRequest(ctx context.Context, email, ip string, since time.Time) (int, int, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO login_code_requests (email, ip, created_at)
+			VALUES ($1, $2, $3)
+		)
+		SELECT
+			(SELECT COUNT(*) FROM login_code_requests WHERE email = $1 AND created_at >= $4) + 1 AS email_requests,
+			(SELECT COUNT(*) FROM login_code_requests WHERE ip = $2 AND created_at >= $4) + 1 AS ip_requests
+	`
+
+	var counts struct {
+		EmailRequests int `db:"email_requests"`
+		IPRequests    int `db:"ip_requests"`
+	}
+	err := getQuerier(ctx, a.db).GetContext(ctx, &counts, query, email, ip, time.Now(), since)
+	if err != nil {
+		return 0, 0, err
+	}
+	return counts.EmailRequests, counts.IPRequests, nil
+}
+
+// DeleteLoginCodeRequests удаляет запросы кодов входа, сделанные раньше before.
+// Возвращает число удаленных записей.
+func (a *PostgreSQLLoginCodeAdapter) DeleteLoginCodeRequests(ctx context.Context, before time.Time) (int64, error) {
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, `DELETE FROM login_code_requests WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,85 @@
+package postgresql_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+func TestClaimLoginCodeAttemptIsAtomic(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, adapter)
+
+	const maxAttempts = 5
+	code, _, err := domain.NewLoginCode(user.ID, time.Hour, maxAttempts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.CreateLoginCode(ctx, code); err != nil {
+		t.Fatal(err)
+	}
+
+	var claimed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4*maxAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ok, err := adapter.ClaimLoginCodeAttempt(ctx, code.ID, time.Now())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ok {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if claimed.Load() != maxAttempts {
+		t.Fatalf("засчитано %d попыток, ожидалось %d", claimed.Load(), maxAttempts)
+	}
+
+	consumed, err := adapter.ConsumeLoginCode(ctx, code.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !consumed {
+		t.Fatal("код с засчитанной последней попыткой не погашен")
+	}
+}
+
+func TestRecordLoginCodeRequestCountsWindow(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, adapter)
+	email := user.Email.String()
+	since := time.Now().Add(-time.Minute)
+
+	for i := 1; i <= 3; i++ {
+		emailRequests, ipRequests, err := adapter.RecordLoginCodeRequest(ctx, email, "a-"+user.ID.String(), since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if emailRequests != i || ipRequests != i {
+			t.Fatalf("запрос %d: email %d, ip %d", i, emailRequests, ipRequests)
+		}
+	}
+
+	emailRequests, ipRequests, err := adapter.RecordLoginCodeRequest(ctx, email, "b-"+user.ID.String(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emailRequests != 4 || ipRequests != 1 {
+		t.Fatalf("запрос с другого IP: email %d, ip %d", emailRequests, ipRequests)
+	}
+}
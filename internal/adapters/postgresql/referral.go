@@ -3,10 +3,13 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
 )
 
 // PostgreSQLReferralAdapter адаптер для работы с рефералами в PostgreSQL
@@ -19,36 +22,159 @@ func NewPostgreSQLReferralAdapter(db *sqlx.DB) *PostgreSQLReferralAdapter {
 	return &PostgreSQLReferralAdapter{db: db}
 }
 
-// CreateReferral создает новую реферальную связь
+// CreateReferral создает новую реферальную связь вместе с найденными признаками мошенничества.
+// Должен вызываться в транзакции, если у связи есть признаки мошенничества.
 func (a *PostgreSQLReferralAdapter) CreateReferral(ctx context.Context, referral domain.Referral) error {
+	q := getQuerier(ctx, a.db)
+
 	query := `
-		INSERT INTO referrals (id, referrer_id, referred_user_id, bonus_points, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO referrals (id, referrer_id, referred_user_id, bonus_points, referrer_bonus_points, status, fraud_score, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err := a.db.ExecContext(ctx, query,
+	_, err := q.ExecContext(ctx, query,
 		referral.ID.Value(), referral.ReferrerID.Value(), referral.ReferredUserID.Value(),
-		referral.BonusPoints, referral.CreatedAt)
-	return err
+		referral.BonusPoints, referral.ReferrerBonusPoints, string(referral.Status),
+		referral.FraudScore, referral.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, signal := range referral.FraudSignals {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO referral_fraud_signals (referral_id, rule, score, reason) VALUES ($1, $2, $3, $4)`,
+			referral.ID.Value(), signal.Rule, signal.Score, signal.Reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// referralRow представляет строку таблицы referrals
+type referralRow struct {
+	ID                  string         `db:"id"`
+	ReferrerID          string         `db:"referrer_id"`
+	ReferredUserID      string         `db:"referred_user_id"`
+	BonusPoints         int            `db:"bonus_points"`
+	ReferrerBonusPoints int            `db:"referrer_bonus_points"`
+	Status              string         `db:"status"`
+	FraudScore          int            `db:"fraud_score"`
+	CreatedAt           time.Time      `db:"created_at"`
+	VestedAt            sql.NullTime   `db:"vested_at"`
+	CancelledAt         sql.NullTime   `db:"cancelled_at"`
+	ReviewedBy          sql.NullString `db:"reviewed_by"`
+	ReviewedAt          sql.NullTime   `db:"reviewed_at"`
+}
+
+func (r referralRow) toDomain() (domain.Referral, error) {
+	referralID, err := domain.ReferralIDFromString(r.ID)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+
+	referrerID, err := domain.UserIDFromString(r.ReferrerID)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+
+	referredID, err := domain.UserIDFromString(r.ReferredUserID)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+
+	var reviewedBy *domain.UserID
+	if r.ReviewedBy.Valid {
+		moderatorID, err := domain.UserIDFromString(r.ReviewedBy.String)
+		if err != nil {
+			return domain.Referral{}, err
+		}
+		reviewedBy = &moderatorID
+	}
+
+	return domain.Referral{
+		ID:                  referralID,
+		ReferrerID:          referrerID,
+		ReferredUserID:      referredID,
+		BonusPoints:         r.BonusPoints,
+		ReferrerBonusPoints: r.ReferrerBonusPoints,
+		Status:              domain.ReferralStatus(r.Status),
+		FraudScore:          r.FraudScore,
+		CreatedAt:           r.CreatedAt,
+		VestedAt:            nullTimePtr(r.VestedAt),
+		CancelledAt:         nullTimePtr(r.CancelledAt),
+		ReviewedBy:          reviewedBy,
+		ReviewedAt:          nullTimePtr(r.ReviewedAt),
+	}, nil
+}
+
+// referralColumns колонки таблицы referrals в порядке полей referralRow
+const referralColumns = `id, referrer_id, referred_user_id, bonus_points, referrer_bonus_points, status, fraud_score,
+	created_at, vested_at, cancelled_at, reviewed_by, reviewed_at`
+
+func referralsToDomain(rows []referralRow) ([]domain.Referral, error) {
+	result := make([]domain.Referral, 0, len(rows))
+	for _, row := range rows {
+		referral, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, referral)
+	}
+	return result, nil
 }
 
 // GetReferralByReferredUserID получает реферальную связь по ID приглашенного пользователя
 func (a *PostgreSQLReferralAdapter) GetReferralByReferredUserID(ctx context.Context, referredUserID domain.UserID) (*domain.Referral, error) {
-	var referral struct {
-		ID             string    `db:"id"`
-		ReferrerID     string    `db:"referrer_id"`
-		ReferredUserID string    `db:"referred_user_id"`
-		BonusPoints    int       `db:"bonus_points"`
-		CreatedAt      time.Time `db:"created_at"`
+	query := `SELECT ` + referralColumns + ` FROM referrals WHERE referred_user_id = $1`
+
+	var row referralRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, referredUserID.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	referral, err := row.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// ListPendingReferrals получает до limit связей в состоянии pending, начиная с самых старых.
+// Если after не nil, список начинается сразу после этой связи.
+func (a *PostgreSQLReferralAdapter) ListPendingReferrals(ctx context.Context, after *domain.Referral, limit int) ([]domain.Referral, error) {
+	var afterCreatedAt time.Time
+	var afterID uuid.UUID
+	if after != nil {
+		afterCreatedAt, afterID = after.CreatedAt, after.ID.Value()
 	}
 
 	query := `
-		SELECT id, referrer_id, referred_user_id, bonus_points, created_at
+		SELECT ` + referralColumns + `
 		FROM referrals
-		WHERE referred_user_id = $1
+		WHERE status = 'pending' AND (created_at, id) > ($1, $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
 	`
 
-	err := a.db.GetContext(ctx, &referral, query, referredUserID.Value())
+	var rows []referralRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, afterCreatedAt, afterID, limit); err != nil {
+		return nil, err
+	}
+	return referralsToDomain(rows)
+}
+
+// GetReferral получает реферальную связь по ID вместе с признаками мошенничества
+func (a *PostgreSQLReferralAdapter) GetReferral(ctx context.Context, referralID domain.ReferralID) (*domain.Referral, error) {
+	query := `SELECT ` + referralColumns + ` FROM referrals WHERE id = $1`
+
+	var row referralRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, referralID.Value())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -56,39 +182,260 @@ func (a *PostgreSQLReferralAdapter) GetReferralByReferredUserID(ctx context.Cont
 		return nil, err
 	}
 
-	referralID, err := domain.ReferralIDFromString(referral.ID)
+	referral, err := row.toDomain()
 	if err != nil {
 		return nil, err
 	}
 
-	referrerID, err := domain.UserIDFromString(referral.ReferrerID)
+	referral.FraudSignals, err = a.getFraudSignals(ctx, referralID)
 	if err != nil {
 		return nil, err
 	}
+	return &referral, nil
+}
+
+// ListReferralsByStatus получает до limit связей с указанным статусом вместе
+// с признаками мошенничества, начиная с самых старых
+func (a *PostgreSQLReferralAdapter) ListReferralsByStatus(ctx context.Context, status domain.ReferralStatus, limit int) ([]domain.Referral, error) {
+	query := `
+		SELECT ` + referralColumns + `
+		FROM referrals
+		WHERE status = $1
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	var rows []referralRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, string(status), limit); err != nil {
+		return nil, err
+	}
 
-	referredID, err := domain.UserIDFromString(referral.ReferredUserID)
+	referrals, err := referralsToDomain(rows)
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.Referral{
-		ID:             referralID,
-		ReferrerID:     referrerID,
-		ReferredUserID: referredID,
-		BonusPoints:    referral.BonusPoints,
-		CreatedAt:      referral.CreatedAt,
-	}, nil
+	for i := range referrals {
+		referrals[i].FraudSignals, err = a.getFraudSignals(ctx, referrals[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return referrals, nil
+}
+
+// getFraudSignals получает признаки мошенничества реферальной связи
+func (a *PostgreSQLReferralAdapter) getFraudSignals(ctx context.Context, referralID domain.ReferralID) ([]domain.FraudSignal, error) {
+	var rows []struct {
+		Rule   string `db:"rule"`
+		Score  int    `db:"score"`
+		Reason string `db:"reason"`
+	}
+
+	query := `
+		SELECT rule, score, reason
+		FROM referral_fraud_signals
+		WHERE referral_id = $1
+		ORDER BY score DESC, rule ASC
+	`
+
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, referralID.Value()); err != nil {
+		return nil, err
+	}
+
+	signals := make([]domain.FraudSignal, len(rows))
+	for i, row := range rows {
+		signals[i] = domain.FraudSignal{Rule: row.Rule, Score: row.Score, Reason: row.Reason}
+	}
+	return signals, nil
+}
+
+// CountReferralsByReferrerSince подсчитывает связи, созданные реферером начиная с since
+func (a *PostgreSQLReferralAdapter) CountReferralsByReferrerSince(ctx context.Context, referrerID domain.UserID, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM referrals WHERE referrer_id = $1 AND created_at >= $2`
+
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, query, referrerID.Value(), since)
+	return count, err
+}
+
+// UpdateReferralStatus сохраняет переход связи из состояния from.
+// Возвращает false, если состояние связи уже изменила параллельная операция.
+func (a *PostgreSQLReferralAdapter) UpdateReferralStatus(ctx context.Context, referral domain.Referral, from domain.ReferralStatus) (bool, error) {
+	var reviewedBy interface{}
+	if referral.ReviewedBy != nil {
+		reviewedBy = referral.ReviewedBy.Value()
+	}
+
+	query := `
+		UPDATE referrals
+		SET status = $3, vested_at = $4, cancelled_at = $5, reviewed_by = $6, reviewed_at = $7
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		referral.ID.Value(), string(from), string(referral.Status),
+		referral.VestedAt, referral.CancelledAt, reviewedBy, referral.ReviewedAt)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
 }
 
-// CountReferralsByReferrerID подсчитывает количество рефералов по ID реферера
+// CountReferralsByReferrerID подсчитывает количество завершенных (vested) рефералов по ID реферера.
+// Ожидающие и задержанные на проверку связи не учитываются, пока по ним не начислены бонусы.
 func (a *PostgreSQLReferralAdapter) CountReferralsByReferrerID(ctx context.Context, referrerID domain.UserID) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM referrals WHERE referrer_id = $1`
+	query := `SELECT COUNT(*) FROM referrals WHERE referrer_id = $1 AND status = 'vested'`
 
-	err := a.db.GetContext(ctx, &count, query, referrerID.Value())
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, query, referrerID.Value())
 	if err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+// ListReferredUsers получает до limit пользователей, приглашенных реферером, начиная с последних.
+// Если after не nil, список начинается сразу после указанной в курсоре связи.
+// Для каждого считаются поинты, зачисленные рефереру в журнал за этого пользователя:
+// бонус за приглашение, поинты за его задания invite_friend и комиссии с его поинтов.
+func (a *PostgreSQLReferralAdapter) ListReferredUsers(ctx context.Context, referrerID domain.UserID, after *usecases.ReferredUsersCursor, limit int) ([]usecases.ReferredUser, error) {
+	query := `
+		SELECT
+			r.id, r.referrer_id, r.referred_user_id, r.bonus_points, r.referrer_bonus_points, r.status, r.fraud_score,
+			r.created_at, r.vested_at, r.cancelled_at, r.reviewed_by, r.reviewed_at,
+			u.username,
+			u.created_at AS joined_at,
+			COALESCE(g.points, 0) AS generated_points
+		FROM referrals r
+		JOIN users u ON u.id = r.referred_user_id
+		LEFT JOIN LATERAL (
+			SELECT SUM(p.amount) AS points
+			FROM point_entries p
+			WHERE p.user_id = r.referrer_id
+				AND (p.source, p.reference_id) IN (
+					SELECT 'referral', r.id::text
+					UNION ALL
+					SELECT 'referral', t.id::text
+					FROM user_tasks t
+					WHERE t.user_id = r.referred_user_id
+					UNION ALL
+					SELECT 'commission', c.id::text
+					FROM referral_commissions c
+					WHERE c.beneficiary_id = r.referrer_id AND c.earner_id = r.referred_user_id
+				)
+		) g ON TRUE
+		WHERE r.referrer_id = $1
+			AND (NOT $2 OR (r.created_at, r.id) < ($3, $4::uuid))
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $5
+	`
+
+	cursor := usecases.ReferredUsersCursor{ReferralID: "00000000-0000-0000-0000-000000000000"}
+	if after != nil {
+		cursor = *after
+	}
+
+	var rows []struct {
+		referralRow
+		Username        string    `db:"username"`
+		JoinedAt        time.Time `db:"joined_at"`
+		GeneratedPoints int       `db:"generated_points"`
+	}
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query,
+		referrerID.Value(), after != nil, cursor.CreatedAt, cursor.ReferralID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса списка рефералов: %w", err)
+	}
+
+	result := make([]usecases.ReferredUser, len(rows))
+	for i, row := range rows {
+		referral, err := row.referralRow.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = usecases.ReferredUser{
+			Referral:        referral,
+			Username:        row.Username,
+			JoinedAt:        row.JoinedAt,
+			GeneratedPoints: row.GeneratedPoints,
+		}
+	}
+	return result, nil
+}
+
+// GetReferralLeaderboard получает страницу рефереров, отсортированных по числу приглашенных
+// начиная с since пользователей. Нулевой since означает таблицу за все время.
+// Учитываются связи в состояниях pending и vested, в поле Balance записи возвращается число рефералов.
+// Порядок сортировки при равенстве совпадает с GetLeaderboard.
+// Места считаются по числу рефереров до позиции курсора в той же выборке.
+func (a *PostgreSQLReferralAdapter) GetReferralLeaderboard(ctx context.Context, since time.Time, limit int, after *usecases.LeaderboardCursor) ([]leaderboardEntry, error) {
+	query := `
+		WITH counts AS (
+			SELECT referrer_id, COUNT(*) AS referrals
+			FROM referrals
+			WHERE status IN ('pending', 'vested')
+				AND ($1::timestamp IS NULL OR created_at >= $1)
+			GROUP BY referrer_id
+		),
+		ahead AS (
+			SELECT COUNT(*) AS count
+			FROM counts c
+			JOIN users u ON u.id = c.referrer_id
+			WHERE $2 AND (c.referrals > $3 OR (c.referrals = $3 AND (u.created_at, u.id) <= ($4, $5::uuid)))
+		)
+		SELECT u.id::text AS user_id, u.username, c.referrals AS balance, u.created_at, a.count AS ahead
+		FROM counts c
+		JOIN users u ON u.id = c.referrer_id
+		CROSS JOIN ahead a
+		WHERE NOT $2
+			OR c.referrals < $3
+			OR (c.referrals = $3 AND (u.created_at, u.id) > ($4, $5::uuid))
+		ORDER BY c.referrals DESC, u.created_at ASC, u.id ASC
+		LIMIT $6
+	`
+
+	cursor := usecases.LeaderboardCursor{UserID: "00000000-0000-0000-0000-000000000000"}
+	if after != nil {
+		cursor = *after
+	}
+
+	var rows []rankedLeaderboardRow
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query,
+		nullTimeIfZero(since), after != nil, cursor.Balance, cursor.CreatedAt, cursor.UserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса таблицы лидеров по рефералам: %w", err)
+	}
+
+	result := make([]leaderboardEntry, len(rows))
+	for i := range rows {
+		result[i] = leaderboardEntry{
+			Rank:      rows[i].Ahead + i + 1,
+			UserID:    rows[i].UserID,
+			Username:  rows[i].Username,
+			Balance:   rows[i].Balance,
+			CreatedAt: rows[i].CreatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// CountReferralLeaderboardUsers получает число рефереров, пригласивших пользователей начиная с since
+func (a *PostgreSQLReferralAdapter) CountReferralLeaderboardUsers(ctx context.Context, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT referrer_id)
+		FROM referrals
+		WHERE status IN ('pending', 'vested')
+			AND ($1::timestamp IS NULL OR created_at >= $1)
+	`
+
+	var count int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, query, nullTimeIfZero(since))
+	return count, err
+}
@@ -0,0 +1,114 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLReferralCodeAdapter адаптер для работы с реферальными кодами в PostgreSQL
+type PostgreSQLReferralCodeAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLReferralCodeAdapter создает новый адаптер реферальных кодов
+func NewPostgreSQLReferralCodeAdapter(db *sqlx.DB) *PostgreSQLReferralCodeAdapter {
+	return &PostgreSQLReferralCodeAdapter{db: db}
+}
+
+// referralCodeRow представляет строку таблицы referral_codes
+type referralCodeRow struct {
+	Code      string    `db:"code"`
+	UserID    string    `db:"user_id"`
+	Kind      string    `db:"kind"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (r referralCodeRow) toDomain() (domain.ReferralCode, error) {
+	userID, err := domain.UserIDFromString(r.UserID)
+	if err != nil {
+		return domain.ReferralCode{}, err
+	}
+
+	return domain.ReferralCode{
+		Code:      r.Code,
+		UserID:    userID,
+		Kind:      domain.ReferralCodeKind(r.Kind),
+		CreatedAt: r.CreatedAt,
+	}, nil
+}
+
+// CreateReferralCode сохраняет реферальный код.
+// Возвращает false, если такой код уже занят другим пользователем.
+func (a *PostgreSQLReferralCodeAdapter) CreateReferralCode(ctx context.Context, code domain.ReferralCode) (bool, error) {
+	query := `
+		INSERT INTO referral_codes (code, user_id, kind, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (code) DO NOTHING
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		code.Code, code.UserID.Value(), string(code.Kind), code.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return created > 0, nil
+}
+
+// GetReferralCode получает реферальный код по значению в том виде, в котором он хранится
+func (a *PostgreSQLReferralCodeAdapter) GetReferralCode(ctx context.Context, code string) (*domain.ReferralCode, error) {
+	query := `
+		SELECT code, user_id, kind, created_at
+		FROM referral_codes
+		WHERE code = $1
+	`
+
+	var row referralCodeRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	referralCode, err := row.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &referralCode, nil
+}
+
+// GetReferralCodesByUserID получает все реферальные коды пользователя
+func (a *PostgreSQLReferralCodeAdapter) GetReferralCodesByUserID(ctx context.Context, userID domain.UserID) ([]domain.ReferralCode, error) {
+	query := `
+		SELECT code, user_id, kind, created_at
+		FROM referral_codes
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+
+	var rows []referralCodeRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, userID.Value()); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ReferralCode, 0, len(rows))
+	for _, row := range rows {
+		code, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, code)
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/adapters/postgresql"
+	"user-rewards-api/internal/domain"
+)
+
+// addTestLedgerEntry добавляет запись журнала и возвращает ее
+func addTestLedgerEntry(t *testing.T, adapter *postgresql.PostgreSQLAdapter, userID domain.UserID, amount int, source domain.LedgerSource, referenceID string) domain.LedgerEntry {
+	t.Helper()
+
+	entry, err := domain.NewLedgerEntry(userID, amount, source, referenceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adapter.AddLedgerEntry(context.Background(), entry); err != nil {
+		t.Fatalf("запись журнала: %v", err)
+	}
+	return entry
+}
+
+func TestListReferredUsersCountsEveryAttributableCredit(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+	ctx := context.Background()
+
+	referrer := createTestUser(t, adapter)
+	referred := createTestUser(t, adapter)
+	catalogTask := createTestCatalogTask(t, adapter, 100)
+
+	policy, err := domain.NewReferralVestingPolicy(0, 30, 0, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	referral, err := domain.NewReferral(referrer.ID, referred.ID, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := referral.Vest(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.CreateReferral(ctx, referral); err != nil {
+		t.Fatal(err)
+	}
+
+	// Бонус за закрепление связи
+	addTestLedgerEntry(t, adapter, referrer.ID, 30, domain.LedgerSourceReferral, referral.ID.String())
+
+	// Задание приглашенного: начисление рефереру за invite_friend и комиссия с поинтов задания
+	task, err := domain.NewUserTask(referred.ID, catalogTask)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.CreateTask(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	source := addTestLedgerEntry(t, adapter, referred.ID, task.Points, domain.LedgerSourceTask, task.ID.String())
+	addTestLedgerEntry(t, adapter, referrer.ID, 100, domain.LedgerSourceReferral, task.ID.String())
+
+	plan, err := domain.NewCommissionPlan([]int{1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commission, ok, err := domain.NewReferralCommission(plan, referrer.ID, 1, source)
+	if err != nil || !ok {
+		t.Fatalf("комиссия не рассчитана: %v", err)
+	}
+	if _, err := adapter.CreateReferralCommission(ctx, commission); err != nil {
+		t.Fatal(err)
+	}
+	commissionEntry, err := commission.LedgerEntry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adapter.AddLedgerEntry(ctx, commissionEntry); err != nil {
+		t.Fatal(err)
+	}
+
+	// Начисление, не связанное с приглашенным, не учитывается
+	grantTestPoints(t, adapter, referrer.ID, 500)
+
+	referredUsers, err := adapter.ListReferredUsers(ctx, referrer.ID, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(referredUsers) != 1 {
+		t.Fatalf("получено %d приглашенных, ожидался 1", len(referredUsers))
+	}
+	if want := 30 + 100 + commission.Amount; referredUsers[0].GeneratedPoints != want {
+		t.Errorf("generated_points %d, ожидалось %d", referredUsers[0].GeneratedPoints, want)
+	}
+}
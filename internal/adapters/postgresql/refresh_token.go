@@ -0,0 +1,129 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLRefreshTokenAdapter адаптер для работы с refresh токенами в PostgreSQL
+type PostgreSQLRefreshTokenAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLRefreshTokenAdapter создает новый адаптер refresh токенов
+func NewPostgreSQLRefreshTokenAdapter(db *sqlx.DB) *PostgreSQLRefreshTokenAdapter {
+	return &PostgreSQLRefreshTokenAdapter{db: db}
+}
+
+// CreateRefreshToken сохраняет новый refresh токен
+func (a *PostgreSQLRefreshTokenAdapter) CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		token.ID.Value(), token.UserID.Value(), token.FamilyID.Value(),
+		token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+// GetRefreshTokenByHash получает refresh токен по хешу
+func (a *PostgreSQLRefreshTokenAdapter) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token struct {
+		ID        string       `db:"id"`
+		UserID    string       `db:"user_id"`
+		FamilyID  string       `db:"family_id"`
+		TokenHash string       `db:"token_hash"`
+		ExpiresAt time.Time    `db:"expires_at"`
+		UsedAt    sql.NullTime `db:"used_at"`
+		RevokedAt sql.NullTime `db:"revoked_at"`
+		CreatedAt time.Time    `db:"created_at"`
+	}
+
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	err := getQuerier(ctx, a.db).GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	tokenID, err := domain.RefreshTokenIDFromString(token.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := domain.UserIDFromString(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	familyID, err := domain.RefreshTokenIDFromString(token.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RefreshToken{
+		ID:        tokenID,
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    nullTimePtr(token.UsedAt),
+		RevokedAt: nullTimePtr(token.RevokedAt),
+		CreatedAt: token.CreatedAt,
+	}, nil
+}
+
+// MarkRefreshTokenUsed отмечает refresh токен как обменянный.
+// Возвращает false, если токен уже был использован или отозван.
+func (a *PostgreSQLRefreshTokenAdapter) MarkRefreshTokenUsed(ctx context.Context, tokenID domain.RefreshTokenID) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query, tokenID.Value(), time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// RevokeRefreshTokenFamily отзывает все токены семейства
+func (a *PostgreSQLRefreshTokenAdapter) RevokeRefreshTokenFamily(ctx context.Context, familyID domain.RefreshTokenID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query, familyID.Value(), time.Now())
+	return err
+}
+
+// nullTimePtr преобразует sql.NullTime в указатель на время
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
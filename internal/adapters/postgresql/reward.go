@@ -0,0 +1,353 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLRewardAdapter адаптер для работы с магазином наград в PostgreSQL
+type PostgreSQLRewardAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLRewardAdapter создает новый адаптер магазина наград
+func NewPostgreSQLRewardAdapter(db *sqlx.DB) *PostgreSQLRewardAdapter {
+	return &PostgreSQLRewardAdapter{db: db}
+}
+
+// rewardRow представляет строку таблицы rewards
+type rewardRow struct {
+	ID             string        `db:"id"`
+	Title          string        `db:"title"`
+	Description    string        `db:"description"`
+	Cost           int           `db:"cost"`
+	Stock          sql.NullInt64 `db:"stock"`
+	PerUserLimit   int           `db:"per_user_limit"`
+	AvailableFrom  sql.NullTime  `db:"available_from"`
+	AvailableUntil sql.NullTime  `db:"available_until"`
+	Active         bool          `db:"active"`
+	CreatedAt      time.Time     `db:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at"`
+}
+
+const rewardColumns = `
+	id, title, description, cost, stock, per_user_limit,
+	available_from, available_until, active, created_at, updated_at
+`
+
+func (r rewardRow) toDomain() (domain.Reward, error) {
+	rewardID, err := domain.RewardIDFromString(r.ID)
+	if err != nil {
+		return domain.Reward{}, err
+	}
+
+	var stock *int
+	if r.Stock.Valid {
+		value := int(r.Stock.Int64)
+		stock = &value
+	}
+
+	return domain.Reward{
+		ID:             rewardID,
+		Title:          r.Title,
+		Description:    r.Description,
+		Cost:           r.Cost,
+		Stock:          stock,
+		PerUserLimit:   r.PerUserLimit,
+		AvailableFrom:  nullTimePtr(r.AvailableFrom),
+		AvailableUntil: nullTimePtr(r.AvailableUntil),
+		Active:         r.Active,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}, nil
+}
+
+// CreateReward сохраняет новый товар
+func (a *PostgreSQLRewardAdapter) CreateReward(ctx context.Context, reward domain.Reward) error {
+	query := `
+		INSERT INTO rewards (id, title, description, cost, stock, per_user_limit,
+			available_from, available_until, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		reward.ID.Value(), reward.Title, reward.Description, reward.Cost, reward.Stock,
+		reward.PerUserLimit, reward.AvailableFrom, reward.AvailableUntil, reward.Active,
+		reward.CreatedAt, reward.UpdatedAt)
+	return err
+}
+
+// UpdateReward сохраняет изменения товара
+func (a *PostgreSQLRewardAdapter) UpdateReward(ctx context.Context, reward domain.Reward) error {
+	query := `
+		UPDATE rewards
+		SET title = $2, description = $3, cost = $4, stock = $5, per_user_limit = $6,
+			available_from = $7, available_until = $8, active = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		reward.ID.Value(), reward.Title, reward.Description, reward.Cost, reward.Stock,
+		reward.PerUserLimit, reward.AvailableFrom, reward.AvailableUntil, reward.Active,
+		reward.UpdatedAt)
+	return err
+}
+
+// GetReward получает товар по ID
+func (a *PostgreSQLRewardAdapter) GetReward(ctx context.Context, id domain.RewardID) (*domain.Reward, error) {
+	query := `SELECT ` + rewardColumns + ` FROM rewards WHERE id = $1`
+
+	var row rewardRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, id.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	reward, err := row.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+// ListRewards получает товары магазина, начиная с самых дешевых. Если includeUnavailable
+// не задан, возвращаются только активные товары, доступные в момент now.
+func (a *PostgreSQLRewardAdapter) ListRewards(ctx context.Context, includeUnavailable bool, now time.Time) ([]domain.Reward, error) {
+	query := `
+		SELECT ` + rewardColumns + `
+		FROM rewards
+		WHERE $1 OR (
+			active
+			AND (available_from IS NULL OR available_from <= $2)
+			AND (available_until IS NULL OR available_until > $2)
+		)
+		ORDER BY cost ASC, created_at ASC
+	`
+
+	var rows []rewardRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, includeUnavailable, now); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Reward, 0, len(rows))
+	for _, row := range rows {
+		reward, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, reward)
+	}
+	return result, nil
+}
+
+// ReserveRewardStock уменьшает остаток товара на единицу. Строка товара блокируется до конца
+// транзакции, поэтому параллельные заказы не продадут больше, чем есть на складе.
+// Возвращает false, если товар закончился. Остаток неограниченного товара не меняется.
+func (a *PostgreSQLRewardAdapter) ReserveRewardStock(ctx context.Context, id domain.RewardID) (bool, error) {
+	query := `
+		UPDATE rewards
+		SET stock = stock - 1
+		WHERE id = $1 AND (stock IS NULL OR stock > 0)
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query, id.Value())
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// rewardOrderRow представляет строку таблицы reward_orders
+type rewardOrderRow struct {
+	ID            string         `db:"id"`
+	UserID        string         `db:"user_id"`
+	RewardID      string         `db:"reward_id"`
+	Title         string         `db:"title"`
+	Cost          int            `db:"cost"`
+	LedgerEntryID string         `db:"ledger_entry_id"`
+	Status        string         `db:"status"`
+	CreatedAt     time.Time      `db:"created_at"`
+	FulfilledAt   sql.NullTime   `db:"fulfilled_at"`
+	FulfilledBy   sql.NullString `db:"fulfilled_by"`
+}
+
+const rewardOrderColumns = `
+	id, user_id, reward_id, title, cost, ledger_entry_id, status,
+	created_at, fulfilled_at, fulfilled_by
+`
+
+func (r rewardOrderRow) toDomain() (domain.RewardOrder, error) {
+	orderID, err := domain.RewardOrderIDFromString(r.ID)
+	if err != nil {
+		return domain.RewardOrder{}, err
+	}
+
+	userID, err := domain.UserIDFromString(r.UserID)
+	if err != nil {
+		return domain.RewardOrder{}, err
+	}
+
+	rewardID, err := domain.RewardIDFromString(r.RewardID)
+	if err != nil {
+		return domain.RewardOrder{}, err
+	}
+
+	entryID, err := domain.LedgerEntryIDFromString(r.LedgerEntryID)
+	if err != nil {
+		return domain.RewardOrder{}, err
+	}
+
+	status, err := domain.NewRewardOrderStatus(r.Status)
+	if err != nil {
+		return domain.RewardOrder{}, err
+	}
+
+	order := domain.RewardOrder{
+		ID:            orderID,
+		UserID:        userID,
+		RewardID:      rewardID,
+		Title:         r.Title,
+		Cost:          r.Cost,
+		LedgerEntryID: entryID,
+		Status:        status,
+		CreatedAt:     r.CreatedAt,
+		FulfilledAt:   nullTimePtr(r.FulfilledAt),
+	}
+
+	if r.FulfilledBy.Valid {
+		fulfilledBy, err := domain.UserIDFromString(r.FulfilledBy.String)
+		if err != nil {
+			return domain.RewardOrder{}, err
+		}
+		order.FulfilledBy = &fulfilledBy
+	}
+
+	return order, nil
+}
+
+func rewardOrdersToDomain(rows []rewardOrderRow) ([]domain.RewardOrder, error) {
+	result := make([]domain.RewardOrder, 0, len(rows))
+	for _, row := range rows {
+		order, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, order)
+	}
+	return result, nil
+}
+
+// CreateRewardOrder сохраняет новый заказ
+func (a *PostgreSQLRewardAdapter) CreateRewardOrder(ctx context.Context, order domain.RewardOrder) error {
+	query := `
+		INSERT INTO reward_orders (id, user_id, reward_id, title, cost, ledger_entry_id, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		order.ID.Value(), order.UserID.Value(), order.RewardID.Value(), order.Title,
+		order.Cost, order.LedgerEntryID.Value(), string(order.Status), order.CreatedAt)
+	return err
+}
+
+// GetRewardOrder получает заказ по ID
+func (a *PostgreSQLRewardAdapter) GetRewardOrder(ctx context.Context, id domain.RewardOrderID) (*domain.RewardOrder, error) {
+	query := `SELECT ` + rewardOrderColumns + ` FROM reward_orders WHERE id = $1`
+
+	var row rewardOrderRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, id.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	order, err := row.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// CountRewardOrdersByUser подсчитывает заказы товара пользователем
+func (a *PostgreSQLRewardAdapter) CountRewardOrdersByUser(ctx context.Context, userID domain.UserID, rewardID domain.RewardID) (int, error) {
+	query := `SELECT COUNT(*) FROM reward_orders WHERE reward_id = $1 AND user_id = $2`
+
+	var count int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, query, rewardID.Value(), userID.Value())
+	return count, err
+}
+
+// ListRewardOrdersByUserID получает заказы пользователя, начиная с последних
+func (a *PostgreSQLRewardAdapter) ListRewardOrdersByUserID(ctx context.Context, userID domain.UserID) ([]domain.RewardOrder, error) {
+	query := `
+		SELECT ` + rewardOrderColumns + `
+		FROM reward_orders
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	var rows []rewardOrderRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, userID.Value()); err != nil {
+		return nil, err
+	}
+	return rewardOrdersToDomain(rows)
+}
+
+// ListRewardOrdersByStatus получает до limit заказов с указанным статусом, начиная с самых старых
+func (a *PostgreSQLRewardAdapter) ListRewardOrdersByStatus(ctx context.Context, status domain.RewardOrderStatus, limit int) ([]domain.RewardOrder, error) {
+	query := `
+		SELECT ` + rewardOrderColumns + `
+		FROM reward_orders
+		WHERE status = $1
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	var rows []rewardOrderRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, string(status), limit); err != nil {
+		return nil, err
+	}
+	return rewardOrdersToDomain(rows)
+}
+
+// FulfillRewardOrder сохраняет выдачу заказа.
+// Возвращает false, если заказ уже выдан параллельной операцией.
+func (a *PostgreSQLRewardAdapter) FulfillRewardOrder(ctx context.Context, order domain.RewardOrder) (bool, error) {
+	var fulfilledBy interface{}
+	if order.FulfilledBy != nil {
+		fulfilledBy = order.FulfilledBy.Value()
+	}
+
+	query := `
+		UPDATE reward_orders
+		SET status = $2, fulfilled_at = $3, fulfilled_by = $4
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		order.ID.Value(), string(order.Status), order.FulfilledAt, fulfilledBy)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
@@ -0,0 +1,243 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLSeasonAdapter адаптер для работы с сезонами в PostgreSQL
+type PostgreSQLSeasonAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLSeasonAdapter создает новый адаптер сезонов
+func NewPostgreSQLSeasonAdapter(db *sqlx.DB) *PostgreSQLSeasonAdapter {
+	return &PostgreSQLSeasonAdapter{db: db}
+}
+
+// seasonRow представляет строку таблицы seasons
+type seasonRow struct {
+	ID          string       `db:"id"`
+	Name        string       `db:"name"`
+	StartsAt    time.Time    `db:"starts_at"`
+	EndsAt      time.Time    `db:"ends_at"`
+	FinalizedAt sql.NullTime `db:"finalized_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+func (r seasonRow) toDomain() (domain.Season, error) {
+	seasonID, err := domain.SeasonIDFromString(r.ID)
+	if err != nil {
+		return domain.Season{}, err
+	}
+
+	var finalizedAt *time.Time
+	if r.FinalizedAt.Valid {
+		t := localTimestamp(r.FinalizedAt.Time)
+		finalizedAt = &t
+	}
+
+	return domain.Season{
+		ID:          seasonID,
+		Name:        r.Name,
+		StartsAt:    localTimestamp(r.StartsAt),
+		EndsAt:      localTimestamp(r.EndsAt),
+		FinalizedAt: finalizedAt,
+		CreatedAt:   localTimestamp(r.CreatedAt),
+	}, nil
+}
+
+// localTimestamp восстанавливает момент времени из колонки TIMESTAMP.
+// Приложение записывает в такие колонки время часового пояса процесса, а lib/pq
+// возвращает записанное время без часового пояса как UTC. Без пересчета границы сезона
+// сдвигались бы на смещение часового пояса процесса при сравнении с time.Now().
+func localTimestamp(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
+
+func seasonsToDomain(rows []seasonRow) ([]domain.Season, error) {
+	result := make([]domain.Season, 0, len(rows))
+	for _, row := range rows {
+		season, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, season)
+	}
+	return result, nil
+}
+
+// CreateSeason сохраняет новый сезон
+func (a *PostgreSQLSeasonAdapter) CreateSeason(ctx context.Context, season domain.Season) error {
+	query := `
+		INSERT INTO seasons (id, name, starts_at, ends_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		season.ID.Value(), season.Name, season.StartsAt, season.EndsAt, season.CreatedAt)
+	return err
+}
+
+// HasOverlappingSeason проверяет, есть ли сезон, пересекающийся с интервалом [startsAt, endsAt)
+func (a *PostgreSQLSeasonAdapter) HasOverlappingSeason(ctx context.Context, startsAt, endsAt time.Time) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM seasons WHERE starts_at < $2 AND ends_at > $1)`
+
+	var exists bool
+	err := getQuerier(ctx, a.db).GetContext(ctx, &exists, query, startsAt, endsAt)
+	return exists, err
+}
+
+// GetSeason получает сезон по ID
+func (a *PostgreSQLSeasonAdapter) GetSeason(ctx context.Context, seasonID domain.SeasonID) (*domain.Season, error) {
+	query := `
+		SELECT id, name, starts_at, ends_at, finalized_at, created_at
+		FROM seasons
+		WHERE id = $1
+	`
+
+	var row seasonRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, seasonID.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	season, err := row.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// ListSeasons получает все сезоны, начиная с самых поздних
+func (a *PostgreSQLSeasonAdapter) ListSeasons(ctx context.Context) ([]domain.Season, error) {
+	query := `
+		SELECT id, name, starts_at, ends_at, finalized_at, created_at
+		FROM seasons
+		ORDER BY starts_at DESC
+	`
+
+	var rows []seasonRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+	return seasonsToDomain(rows)
+}
+
+// ListSeasonsToFinalize получает завершившиеся к моменту now сезоны, итоги которых еще не сохранены
+func (a *PostgreSQLSeasonAdapter) ListSeasonsToFinalize(ctx context.Context, now time.Time) ([]domain.Season, error) {
+	query := `
+		SELECT id, name, starts_at, ends_at, finalized_at, created_at
+		FROM seasons
+		WHERE finalized_at IS NULL AND ends_at <= $1
+		ORDER BY ends_at ASC
+	`
+
+	var rows []seasonRow
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, now); err != nil {
+		return nil, err
+	}
+	return seasonsToDomain(rows)
+}
+
+// FinalizeSeason сохраняет итоговую таблицу сезона и отмечает сезон завершенным.
+// Возвращает false, если итоги сезона уже были сохранены.
+// Должен вызываться в транзакции, чтобы отметка и итоговая таблица сохранялись вместе.
+func (a *PostgreSQLSeasonAdapter) FinalizeSeason(ctx context.Context, season domain.Season, finalizedAt time.Time) (bool, error) {
+	q := getQuerier(ctx, a.db)
+
+	result, err := q.ExecContext(ctx,
+		`UPDATE seasons SET finalized_at = $2 WHERE id = $1 AND finalized_at IS NULL`,
+		season.ID.Value(), finalizedAt)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	query := `
+		INSERT INTO season_standings (season_id, rank, user_id, username, score)
+		SELECT
+			$1,
+			ROW_NUMBER() OVER (ORDER BY s.points DESC, u.created_at ASC, u.id ASC),
+			u.id,
+			u.username,
+			s.points
+		FROM (
+			SELECT user_id, SUM(amount) AS points
+			FROM point_entries
+			WHERE amount > 0 AND created_at >= $2 AND created_at < $3
+				AND NOT (source = 'admin' AND reference_id = 'opening_balance')
+			GROUP BY user_id
+		) s
+		JOIN users u ON u.id = s.user_id
+	`
+
+	if _, err := q.ExecContext(ctx, query, season.ID.Value(), season.StartsAt, season.EndsAt); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetSeasonStandings получает страницу итоговой таблицы сезона, начиная с места afterRank + 1
+func (a *PostgreSQLSeasonAdapter) GetSeasonStandings(ctx context.Context, seasonID domain.SeasonID, afterRank, limit int) ([]domain.SeasonStanding, error) {
+	var rows []struct {
+		Rank     int    `db:"rank"`
+		UserID   string `db:"user_id"`
+		Username string `db:"username"`
+		Score    int    `db:"score"`
+	}
+
+	query := `
+		SELECT rank, user_id, username, score
+		FROM season_standings
+		WHERE season_id = $1 AND rank > $2
+		ORDER BY rank ASC
+		LIMIT $3
+	`
+
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, seasonID.Value(), afterRank, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.SeasonStanding, 0, len(rows))
+	for _, row := range rows {
+		userID, err := domain.UserIDFromString(row.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, domain.SeasonStanding{
+			SeasonID: seasonID,
+			Rank:     row.Rank,
+			UserID:   userID,
+			Username: row.Username,
+			Score:    row.Score,
+		})
+	}
+
+	return result, nil
+}
+
+// CountSeasonStandings получает число участников итоговой таблицы сезона
+func (a *PostgreSQLSeasonAdapter) CountSeasonStandings(ctx context.Context, seasonID domain.SeasonID) (int, error) {
+	var count int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM season_standings WHERE season_id = $1`, seasonID.Value())
+	return count, err
+}
@@ -0,0 +1,35 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+func TestSeasonBoundsRoundTripInProcessTimezone(t *testing.T) {
+	adapter, _ := newTestAdapter(t)
+	ctx := context.Background()
+
+	previous := time.Local
+	time.Local = time.FixedZone("UTC-7", -7*60*60)
+	t.Cleanup(func() { time.Local = previous })
+
+	startsAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	season, err := domain.NewSeason("Весна", startsAt, startsAt.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.CreateSeason(ctx, season); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := adapter.GetSeason(ctx, season.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.StartsAt.Equal(season.StartsAt) || !stored.EndsAt.Equal(season.EndsAt) {
+		t.Fatalf("сезон %v - %v прочитан как %v - %v", season.StartsAt, season.EndsAt, stored.StartsAt, stored.EndsAt)
+	}
+}
@@ -0,0 +1,214 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLSubmissionAdapter адаптер для работы с заявками на выполнение заданий в PostgreSQL
+type PostgreSQLSubmissionAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLSubmissionAdapter создает новый адаптер заявок
+func NewPostgreSQLSubmissionAdapter(db *sqlx.DB) *PostgreSQLSubmissionAdapter {
+	return &PostgreSQLSubmissionAdapter{db: db}
+}
+
+// submissionRow представляет строку таблицы task_submissions
+type submissionRow struct {
+	ID              string         `db:"id"`
+	UserID          string         `db:"user_id"`
+	TaskType        string         `db:"task_type"`
+	ProofType       string         `db:"proof_type"`
+	Proof           string         `db:"proof"`
+	Points          int            `db:"points"`
+	Status          string         `db:"status"`
+	RejectionReason sql.NullString `db:"rejection_reason"`
+	ReviewedBy      sql.NullString `db:"reviewed_by"`
+	ReviewedAt      sql.NullTime   `db:"reviewed_at"`
+	UserTaskID      sql.NullString `db:"user_task_id"`
+	CreatedAt       time.Time      `db:"created_at"`
+}
+
+const submissionColumns = `
+	id, user_id, task_type, proof_type, proof, points, status,
+	rejection_reason, reviewed_by, reviewed_at, user_task_id, created_at
+`
+
+func (r submissionRow) toDomain() (domain.TaskSubmission, error) {
+	submissionID, err := domain.SubmissionIDFromString(r.ID)
+	if err != nil {
+		return domain.TaskSubmission{}, err
+	}
+
+	userID, err := domain.UserIDFromString(r.UserID)
+	if err != nil {
+		return domain.TaskSubmission{}, err
+	}
+
+	taskType, err := domain.NewTaskType(r.TaskType)
+	if err != nil {
+		return domain.TaskSubmission{}, err
+	}
+
+	status, err := domain.NewSubmissionStatus(r.Status)
+	if err != nil {
+		return domain.TaskSubmission{}, err
+	}
+
+	submission := domain.TaskSubmission{
+		ID:              submissionID,
+		UserID:          userID,
+		TaskType:        taskType,
+		ProofType:       domain.ProofType(r.ProofType),
+		Proof:           r.Proof,
+		Points:          r.Points,
+		Status:          status,
+		RejectionReason: r.RejectionReason.String,
+		ReviewedAt:      nullTimePtr(r.ReviewedAt),
+		CreatedAt:       r.CreatedAt,
+	}
+
+	if r.ReviewedBy.Valid {
+		reviewedBy, err := domain.UserIDFromString(r.ReviewedBy.String)
+		if err != nil {
+			return domain.TaskSubmission{}, err
+		}
+		submission.ReviewedBy = &reviewedBy
+	}
+
+	if r.UserTaskID.Valid {
+		userTaskID, err := domain.TaskIDFromString(r.UserTaskID.String)
+		if err != nil {
+			return domain.TaskSubmission{}, err
+		}
+		submission.UserTaskID = &userTaskID
+	}
+
+	return submission, nil
+}
+
+// CreateSubmission сохраняет новую заявку
+func (a *PostgreSQLSubmissionAdapter) CreateSubmission(ctx context.Context, submission domain.TaskSubmission) error {
+	query := `
+		INSERT INTO task_submissions (id, user_id, task_type, proof_type, proof, points, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		submission.ID.Value(), submission.UserID.Value(), submission.TaskType.String(),
+		submission.ProofType.String(), submission.Proof, submission.Points,
+		submission.Status.String(), submission.CreatedAt)
+	return err
+}
+
+// GetSubmission получает заявку по ID
+func (a *PostgreSQLSubmissionAdapter) GetSubmission(ctx context.Context, id domain.SubmissionID) (*domain.TaskSubmission, error) {
+	query := `SELECT ` + submissionColumns + ` FROM task_submissions WHERE id = $1`
+
+	var row submissionRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, id.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	submission, err := row.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// UpdateSubmissionReview сохраняет решение модератора по заявке.
+// Обновляется только заявка в статусе pending, иначе возвращается domain.ErrSubmissionReviewed,
+// так что два модератора не могут одобрить одну заявку дважды.
+func (a *PostgreSQLSubmissionAdapter) UpdateSubmissionReview(ctx context.Context, submission domain.TaskSubmission) error {
+	query := `
+		UPDATE task_submissions
+		SET status = $2, rejection_reason = $3, reviewed_by = $4, reviewed_at = $5, user_task_id = $6
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	var reviewedBy, userTaskID interface{}
+	if submission.ReviewedBy != nil {
+		reviewedBy = submission.ReviewedBy.Value()
+	}
+	if submission.UserTaskID != nil {
+		userTaskID = submission.UserTaskID.Value()
+	}
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		submission.ID.Value(), submission.Status.String(),
+		sql.NullString{String: submission.RejectionReason, Valid: submission.RejectionReason != ""},
+		reviewedBy, submission.ReviewedAt, userTaskID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return domain.ErrSubmissionReviewed
+	}
+	return nil
+}
+
+// ListSubmissionsByStatus получает заявки с указанным статусом, начиная с самых старых
+func (a *PostgreSQLSubmissionAdapter) ListSubmissionsByStatus(ctx context.Context, status domain.SubmissionStatus, limit int) ([]domain.TaskSubmission, error) {
+	query := `
+		SELECT ` + submissionColumns + `
+		FROM task_submissions
+		WHERE status = $1
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	var rows []submissionRow
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, status.String(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return submissionsToDomain(rows)
+}
+
+// ListSubmissionsByUserID получает все заявки пользователя, начиная с самых новых
+func (a *PostgreSQLSubmissionAdapter) ListSubmissionsByUserID(ctx context.Context, userID domain.UserID) ([]domain.TaskSubmission, error) {
+	query := `
+		SELECT ` + submissionColumns + `
+		FROM task_submissions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	var rows []submissionRow
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, userID.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	return submissionsToDomain(rows)
+}
+
+func submissionsToDomain(rows []submissionRow) ([]domain.TaskSubmission, error) {
+	result := make([]domain.TaskSubmission, 0, len(rows))
+	for _, row := range rows {
+		submission, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, submission)
+	}
+	return result, nil
+}
@@ -25,7 +25,7 @@ func (a *PostgreSQLTaskAdapter) CreateTask(ctx context.Context, task domain.User
 		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	_, err := a.db.ExecContext(ctx, query,
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
 		task.ID.Value(), task.UserID.Value(), task.TaskType.String(),
 		task.CompletedAt, task.Points)
 	return err
@@ -48,7 +48,7 @@ func (a *PostgreSQLTaskAdapter) GetTasksByUserID(ctx context.Context, userID dom
 		ORDER BY completed_at DESC
 	`
 
-	err := a.db.SelectContext(ctx, &tasks, query, userID.Value())
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &tasks, query, userID.Value())
 	if err != nil {
 		return nil, err
 	}
@@ -82,50 +82,32 @@ func (a *PostgreSQLTaskAdapter) GetTasksByUserID(ctx context.Context, userID dom
 	return result, nil
 }
 
-// GetTaskByUserAndType получает задание пользователя по типу
-func (a *PostgreSQLTaskAdapter) GetTaskByUserAndType(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (*domain.UserTask, error) {
-	var task struct {
-		ID          string    `db:"id"`
-		UserID      string    `db:"user_id"`
-		TaskType    string    `db:"task_type"`
-		CompletedAt time.Time `db:"completed_at"`
-		Points      int       `db:"points"`
+// GetTaskCompletionStats получает число выполнений задания пользователем и время последнего выполнения.
+// Заявки, ожидающие проверки, учитываются как выполнения, чтобы нельзя было
+// отправить несколько заявок в обход лимита и периода ожидания.
+func (a *PostgreSQLTaskAdapter) GetTaskCompletionStats(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (int, *time.Time, error) {
+	var stats struct {
+		Count         int          `db:"count"`
+		LastCompleted sql.NullTime `db:"last_completed_at"`
 	}
 
 	query := `
-		SELECT id, user_id, task_type, completed_at, points 
-		FROM user_tasks 
-		WHERE user_id = $1 AND task_type = $2
+		SELECT COUNT(*) AS count, MAX(completed_at) AS last_completed_at
+		FROM (
+			SELECT completed_at
+			FROM user_tasks
+			WHERE user_id = $1 AND task_type = $2
+			UNION ALL
+			SELECT created_at AS completed_at
+			FROM task_submissions
+			WHERE user_id = $1 AND task_type = $2 AND status = 'pending'
+		) completions
 	`
 
-	err := a.db.GetContext(ctx, &task, query, userID.Value(), taskType.String())
+	err := getQuerier(ctx, a.db).GetContext(ctx, &stats, query, userID.Value(), taskType.String())
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
-	}
-
-	taskID, err := domain.TaskIDFromString(task.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	domainUserID, err := domain.UserIDFromString(task.UserID)
-	if err != nil {
-		return nil, err
-	}
-
-	taskTypeValue, err := domain.NewTaskType(task.TaskType)
-	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
-	return &domain.UserTask{
-		ID:          taskID,
-		UserID:      domainUserID,
-		TaskType:    taskTypeValue,
-		CompletedAt: task.CompletedAt,
-		Points:      task.Points,
-	}, nil
+	return stats.Count, nullTimePtr(stats.LastCompleted), nil
 }
@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgreSQLTaskCatalogAdapter адаптер для работы с каталогом заданий в PostgreSQL
+type PostgreSQLTaskCatalogAdapter struct {
+	db *sqlx.DB
+}
+
+// NewPostgreSQLTaskCatalogAdapter создает новый адаптер каталога заданий
+func NewPostgreSQLTaskCatalogAdapter(db *sqlx.DB) *PostgreSQLTaskCatalogAdapter {
+	return &PostgreSQLTaskCatalogAdapter{db: db}
+}
+
+// catalogTaskRow представляет строку таблицы tasks
+type catalogTaskRow struct {
+	Key         string        `db:"key"`
+	Title       string        `db:"title"`
+	Description string        `db:"description"`
+	Points      int           `db:"points"`
+	Repeat      string        `db:"repeat_policy"`
+	Interval    sql.NullInt64 `db:"repeat_interval_hours"`
+	ResetMinute int           `db:"reset_minute"`
+	Weekday     int           `db:"reset_weekday"`
+	MaxRepeats  sql.NullInt64 `db:"max_completions"`
+	ProofType   string        `db:"proof_type"`
+	Active      bool          `db:"active"`
+	CreatedAt   time.Time     `db:"created_at"`
+	ArchivedAt  sql.NullTime  `db:"archived_at"`
+}
+
+func (r catalogTaskRow) toDomain() (domain.Task, error) {
+	key, err := domain.NewTaskType(r.Key)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	return domain.Task{
+		Key:         key,
+		Title:       r.Title,
+		Description: r.Description,
+		Points:      r.Points,
+		Repeat: domain.RepeatPolicy{
+			Kind:           domain.RepeatKind(r.Repeat),
+			IntervalHours:  int(r.Interval.Int64),
+			ResetMinute:    r.ResetMinute,
+			ResetWeekday:   time.Weekday(r.Weekday),
+			MaxCompletions: int(r.MaxRepeats.Int64),
+		},
+		ProofType:  domain.ProofType(r.ProofType),
+		Active:     r.Active,
+		CreatedAt:  r.CreatedAt,
+		ArchivedAt: nullTimePtr(r.ArchivedAt),
+	}, nil
+}
+
+// CreateCatalogTask добавляет задание в каталог.
+// Возвращает domain.ErrTaskExists, если задание с таким ключом уже есть.
+func (a *PostgreSQLTaskCatalogAdapter) CreateCatalogTask(ctx context.Context, task domain.Task) error {
+	query := `
+		INSERT INTO tasks (
+			key, title, description, points, active, created_at, archived_at,
+			repeat_policy, repeat_interval_hours, reset_minute, reset_weekday, max_completions,
+			proof_type
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (key) DO NOTHING
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		task.Key.String(), task.Title, task.Description, task.Points,
+		task.Active, task.CreatedAt, task.ArchivedAt,
+		task.Repeat.Kind.String(), nullIfZero(task.Repeat.IntervalHours), task.Repeat.ResetMinute,
+		int(task.Repeat.ResetWeekday), nullIfZero(task.Repeat.MaxCompletions),
+		task.ProofType.String())
+	if err != nil {
+		return err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if inserted == 0 {
+		return domain.ErrTaskExists
+	}
+	return nil
+}
+
+// UpdateCatalogTask сохраняет изменения задания каталога
+func (a *PostgreSQLTaskCatalogAdapter) UpdateCatalogTask(ctx context.Context, task domain.Task) error {
+	query := `
+		UPDATE tasks
+		SET title = $2, description = $3, points = $4, active = $5, archived_at = $6,
+			repeat_policy = $7, repeat_interval_hours = $8, reset_minute = $9,
+			reset_weekday = $10, max_completions = $11, proof_type = $12
+		WHERE key = $1
+	`
+
+	result, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
+		task.Key.String(), task.Title, task.Description, task.Points,
+		task.Active, task.ArchivedAt,
+		task.Repeat.Kind.String(), nullIfZero(task.Repeat.IntervalHours), task.Repeat.ResetMinute,
+		int(task.Repeat.ResetWeekday), nullIfZero(task.Repeat.MaxCompletions),
+		task.ProofType.String())
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// GetCatalogTask получает задание каталога по ключу
+func (a *PostgreSQLTaskCatalogAdapter) GetCatalogTask(ctx context.Context, key domain.TaskType) (*domain.Task, error) {
+	query := `
+		SELECT key, title, description, points, active, created_at, archived_at,
+			repeat_policy, repeat_interval_hours, reset_minute, reset_weekday, max_completions,
+			proof_type
+		FROM tasks
+		WHERE key = $1
+	`
+
+	var row catalogTaskRow
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, key.String())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	task, err := row.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListCatalogTasks получает задания каталога.
+// Если includeInactive равен false, возвращаются только доступные для выполнения задания.
+func (a *PostgreSQLTaskCatalogAdapter) ListCatalogTasks(ctx context.Context, includeInactive bool) ([]domain.Task, error) {
+	query := `
+		SELECT key, title, description, points, active, created_at, archived_at,
+			repeat_policy, repeat_interval_hours, reset_minute, reset_weekday, max_completions,
+			proof_type
+		FROM tasks
+		WHERE $1 OR (active AND archived_at IS NULL)
+		ORDER BY created_at ASC, key ASC
+	`
+
+	var rows []catalogTaskRow
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Task, 0, len(rows))
+	for _, row := range rows {
+		task, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, task)
+	}
+
+	return result, nil
+}
+
+// nullIfZero преобразует нулевое значение в NULL
+func nullIfZero(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
@@ -2,10 +2,42 @@ package postgresql
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// querier общий набор методов *sqlx.DB и *sqlx.Tx, используемый адаптерами
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// txContextKey типизированный ключ для хранения транзакции в контексте
+type txContextKey struct{}
+
+// txState состояние текущей транзакции: сама транзакция и глубина вложенности
+type txState struct {
+	tx    *sqlx.Tx
+	depth int
+}
+
+// txFromContext возвращает транзакцию из контекста, если она есть
+func txFromContext(ctx context.Context) (*txState, bool) {
+	state, ok := ctx.Value(txContextKey{}).(*txState)
+	return state, ok
+}
+
+// getQuerier возвращает транзакцию из контекста или соединение с базой данных
+func getQuerier(ctx context.Context, db *sqlx.DB) querier {
+	if state, ok := txFromContext(ctx); ok {
+		return state.tx
+	}
+	return db
+}
+
 type PostgreSQLTransactionAdapter struct {
 	db *sqlx.DB
 }
@@ -14,8 +46,14 @@ func NewPostgreSQLTransactionAdapter(db *sqlx.DB) *PostgreSQLTransactionAdapter
 	return &PostgreSQLTransactionAdapter{db: db}
 }
 
-// WithTransaction выполняет функцию в транзакции
-func (a *PostgreSQLTransactionAdapter) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+// WithTransaction выполняет функцию в транзакции.
+// Вложенный вызов не открывает новую транзакцию, а создает точку сохранения,
+// так что ошибка во вложенной функции откатывает только ее изменения.
+func (a *PostgreSQLTransactionAdapter) WithTransaction(ctx context.Context, fn func(context.Context) error) (err error) {
+	if state, ok := txFromContext(ctx); ok {
+		return a.withSavepoint(ctx, state, fn)
+	}
+
 	tx, err := a.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -32,8 +70,38 @@ func (a *PostgreSQLTransactionAdapter) WithTransaction(ctx context.Context, fn f
 		}
 	}()
 
-	txCtx := context.WithValue(ctx, "tx", tx)
+	txCtx := context.WithValue(ctx, txContextKey{}, &txState{tx: tx})
 	err = fn(txCtx)
 
 	return err
 }
+
+// withSavepoint выполняет функцию внутри точки сохранения текущей транзакции
+func (a *PostgreSQLTransactionAdapter) withSavepoint(ctx context.Context, parent *txState, fn func(context.Context) error) (err error) {
+	state := &txState{tx: parent.tx, depth: parent.depth + 1}
+	savepoint := fmt.Sprintf("sp_%d", state.depth)
+
+	if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("ошибка создания точки сохранения: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollbackToSavepoint(ctx, state.tx, savepoint)
+			panic(p)
+		} else if err != nil {
+			rollbackToSavepoint(ctx, state.tx, savepoint)
+		} else if _, releaseErr := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); releaseErr != nil {
+			err = fmt.Errorf("ошибка освобождения точки сохранения: %w", releaseErr)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txContextKey{}, state))
+
+	return err
+}
+
+// rollbackToSavepoint откатывает изменения до точки сохранения
+func rollbackToSavepoint(ctx context.Context, tx *sqlx.Tx, savepoint string) {
+	_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+}
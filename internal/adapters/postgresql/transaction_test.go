@@ -0,0 +1,233 @@
+package postgresql_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/adapters/postgresql"
+	"user-rewards-api/internal/database"
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// errInjected ошибка, которую тесты возвращают посреди транзакции
+var errInjected = errors.New("injected failure")
+
+// newTestAdapter подключается к базе из TEST_DATABASE_URL и применяет миграции.
+// Если переменная не задана, тест пропускается.
+func newTestAdapter(t *testing.T) (*postgresql.PostgreSQLAdapter, *sqlx.DB) {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL не задан")
+	}
+
+	db, err := sqlx.Connect("postgres", url)
+	if err != nil {
+		t.Fatalf("подключение к базе: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.RunMigrations(db.DB, "../../../migrations"); err != nil {
+		t.Fatalf("миграции: %v", err)
+	}
+
+	expiry, err := domain.NewPointsExpiryPolicy(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return postgresql.NewPostgreSQLAdapter(db, expiry), db
+}
+
+// createTestUser создает пользователя с уникальными именем и почтой
+func createTestUser(t *testing.T, adapter *postgresql.PostgreSQLAdapter) domain.User {
+	t.Helper()
+
+	suffix := uuid.NewString()[:8]
+	user, err := domain.NewUser("user_"+suffix, suffix+"@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("создание пользователя: %v", err)
+	}
+	return user
+}
+
+// createTestCatalogTask создает активное задание каталога с уникальным ключом
+func createTestCatalogTask(t *testing.T, adapter *postgresql.PostgreSQLAdapter, points int) domain.Task {
+	t.Helper()
+
+	task, err := domain.NewTask("test_"+uuid.NewString()[:8], "Тестовое задание", "", points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adapter.CreateCatalogTask(context.Background(), task); err != nil {
+		t.Fatalf("создание задания каталога: %v", err)
+	}
+	return task
+}
+
+func countRows(t *testing.T, db *sqlx.DB, query string, args ...any) int {
+	t.Helper()
+
+	var count int
+	if err := db.Get(&count, query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return count
+}
+
+// failingChainAdapter возвращает ошибку при получении цепочки рефереров,
+// то есть уже после сохранения задания и начисления поинтов в creditTaskCompletion
+type failingChainAdapter struct {
+	*postgresql.PostgreSQLAdapter
+}
+
+func (a failingChainAdapter) GetReferralChain(ctx context.Context, userID domain.UserID, depth int) ([]domain.UserID, error) {
+	return nil, errInjected
+}
+
+func TestCompleteTaskRollsBackOnFailure(t *testing.T) {
+	adapter, db := newTestAdapter(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, adapter)
+	catalogTask := createTestCatalogTask(t, adapter, 25)
+
+	store := failingChainAdapter{adapter}
+	plan, err := domain.NewCommissionPlan([]int{10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vestingPolicy, err := domain.NewReferralVestingPolicy(0, 0, 1, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uc := usecases.NewCompleteTaskUseCase(
+		store,
+		nil,
+		usecases.NewCommissionPayer(store, plan),
+		usecases.NewReferralVester(store, vestingPolicy, usecases.NewFraudDetector(0)),
+		usecases.NewAchievementEngine(store, nil, time.UTC),
+		time.UTC,
+	)
+
+	_, err = uc.Execute(ctx, user.ID.String(), dto.CompleteTaskInput{TaskType: catalogTask.Key.String()})
+	if !errors.Is(err, errInjected) {
+		t.Fatalf("ожидалась внедренная ошибка, получено %v", err)
+	}
+
+	if n := countRows(t, db, `SELECT COUNT(*) FROM user_tasks WHERE user_id = $1`, user.ID.Value()); n != 0 {
+		t.Errorf("user_tasks: %d строк после отката, ожидалось 0", n)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM point_entries WHERE user_id = $1`, user.ID.Value()); n != 0 {
+		t.Errorf("point_entries: %d строк после отката, ожидалось 0", n)
+	}
+
+	stored, err := adapter.GetUserByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Balance.Value() != 0 {
+		t.Errorf("баланс %d после отката, ожидалось 0", stored.Balance.Value())
+	}
+}
+
+func TestWithTransactionRollsBackOnError(t *testing.T) {
+	adapter, db := newTestAdapter(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, adapter)
+	catalogTask := createTestCatalogTask(t, adapter, 10)
+
+	err := adapter.WithTransaction(ctx, func(ctx context.Context) error {
+		task, err := domain.NewUserTask(user.ID, catalogTask)
+		if err != nil {
+			return err
+		}
+		if err := adapter.CreateTask(ctx, task); err != nil {
+			return err
+		}
+
+		entry, err := domain.NewLedgerEntry(user.ID, task.Points, domain.LedgerSourceTask, task.ID.String())
+		if err != nil {
+			return err
+		}
+		if _, err := adapter.AddLedgerEntry(ctx, entry); err != nil {
+			return err
+		}
+		return errInjected
+	})
+	if !errors.Is(err, errInjected) {
+		t.Fatalf("ожидалась внедренная ошибка, получено %v", err)
+	}
+
+	if n := countRows(t, db, `SELECT COUNT(*) FROM user_tasks WHERE user_id = $1`, user.ID.Value()); n != 0 {
+		t.Errorf("user_tasks: %d строк после отката, ожидалось 0", n)
+	}
+	if n := countRows(t, db, `SELECT balance FROM users WHERE id = $1`, user.ID.Value()); n != 0 {
+		t.Errorf("баланс %d после отката, ожидалось 0", n)
+	}
+}
+
+func TestWithTransactionFailedSavepointKeepsOuterTransaction(t *testing.T) {
+	adapter, db := newTestAdapter(t)
+	ctx := context.Background()
+
+	user := createTestUser(t, adapter)
+
+	err := adapter.WithTransaction(ctx, func(ctx context.Context) error {
+		outer, err := domain.NewLedgerEntry(user.ID, 5, domain.LedgerSourceAdmin, "outer")
+		if err != nil {
+			return err
+		}
+		if _, err := adapter.AddLedgerEntry(ctx, outer); err != nil {
+			return err
+		}
+
+		err = adapter.WithTransaction(ctx, func(ctx context.Context) error {
+			inner, err := domain.NewLedgerEntry(user.ID, 100, domain.LedgerSourceAdmin, "inner")
+			if err != nil {
+				return err
+			}
+			if _, err := adapter.AddLedgerEntry(ctx, inner); err != nil {
+				return err
+			}
+			// Повторная вставка пользователя нарушает первичный ключ и переводит
+			// транзакцию в состояние aborted, пока точка сохранения не откачена
+			return adapter.CreateUser(ctx, user)
+		})
+		if err == nil {
+			return errors.New("ожидалась ошибка вложенной транзакции")
+		}
+
+		// Внешняя транзакция должна остаться рабочей после отката точки сохранения
+		after, err := domain.NewLedgerEntry(user.ID, 7, domain.LedgerSourceAdmin, "after")
+		if err != nil {
+			return err
+		}
+		_, err = adapter.AddLedgerEntry(ctx, after)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("внешняя транзакция: %v", err)
+	}
+
+	if n := countRows(t, db, `SELECT balance FROM users WHERE id = $1`, user.ID.Value()); n != 12 {
+		t.Errorf("баланс %d, ожидалось 12", n)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM point_entries WHERE user_id = $1 AND reference_id = 'inner'`, user.ID.Value()); n != 0 {
+		t.Errorf("запись вложенной транзакции сохранилась")
+	}
+}
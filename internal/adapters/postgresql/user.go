@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -22,13 +24,13 @@ func NewPostgreSQLUserAdapter(db *sqlx.DB) *PostgreSQLUserAdapter {
 // CreateUser создает нового пользователя
 func (a *PostgreSQLUserAdapter) CreateUser(ctx context.Context, user domain.User) error {
 	query := `
-		INSERT INTO users (id, username, email, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, username, email, balance, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := a.db.ExecContext(ctx, query,
+	_, err := getQuerier(ctx, a.db).ExecContext(ctx, query,
 		user.ID.Value(), user.Username.String(), user.Email.String(),
-		user.Balance.Value(), user.CreatedAt, user.UpdatedAt)
+		user.Balance.Value(), user.Role.String(), user.CreatedAt, user.UpdatedAt)
 	return err
 }
 
@@ -39,12 +41,13 @@ func (a *PostgreSQLUserAdapter) GetUserByID(ctx context.Context, userID domain.U
 		Username  string    `db:"username"`
 		Email     string    `db:"email"`
 		Balance   int       `db:"balance"`
+		Role      string    `db:"role"`
 		CreatedAt time.Time `db:"created_at"`
 		UpdatedAt time.Time `db:"updated_at"`
 	}
 
-	query := `SELECT id, username, email, balance, created_at, updated_at FROM users WHERE id = $1`
-	err := a.db.GetContext(ctx, &user, query, userID.Value())
+	query := `SELECT id, username, email, balance, role, created_at, updated_at FROM users WHERE id = $1`
+	err := getQuerier(ctx, a.db).GetContext(ctx, &user, query, userID.Value())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrUserNotFound
@@ -67,11 +70,17 @@ func (a *PostgreSQLUserAdapter) GetUserByID(ctx context.Context, userID domain.U
 		return nil, err
 	}
 
+	role, err := domain.NewRole(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.User{
 		ID:        domainUserID,
 		Username:  username,
 		Email:     email,
 		Balance:   domain.NewBalance(user.Balance),
+		Role:      role,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}, nil
@@ -84,12 +93,13 @@ func (a *PostgreSQLUserAdapter) GetUserByUsername(ctx context.Context, username
 		Username  string    `db:"username"`
 		Email     string    `db:"email"`
 		Balance   int       `db:"balance"`
+		Role      string    `db:"role"`
 		CreatedAt time.Time `db:"created_at"`
 		UpdatedAt time.Time `db:"updated_at"`
 	}
 
-	query := `SELECT id, username, email, balance, created_at, updated_at FROM users WHERE username = $1`
-	err := a.db.GetContext(ctx, &user, query, username)
+	query := `SELECT id, username, email, balance, role, created_at, updated_at FROM users WHERE username = $1`
+	err := getQuerier(ctx, a.db).GetContext(ctx, &user, query, username)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -112,11 +122,17 @@ func (a *PostgreSQLUserAdapter) GetUserByUsername(ctx context.Context, username
 		return nil, err
 	}
 
+	role, err := domain.NewRole(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.User{
 		ID:        domainUserID,
 		Username:  usernameValue,
 		Email:     email,
 		Balance:   domain.NewBalance(user.Balance),
+		Role:      role,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}, nil
@@ -129,12 +145,13 @@ func (a *PostgreSQLUserAdapter) GetUserByEmail(ctx context.Context, email string
 		Username  string    `db:"username"`
 		Email     string    `db:"email"`
 		Balance   int       `db:"balance"`
+		Role      string    `db:"role"`
 		CreatedAt time.Time `db:"created_at"`
 		UpdatedAt time.Time `db:"updated_at"`
 	}
 
-	query := `SELECT id, username, email, balance, created_at, updated_at FROM users WHERE email = $1`
-	err := a.db.GetContext(ctx, &user, query, email)
+	query := `SELECT id, username, email, balance, role, created_at, updated_at FROM users WHERE email = $1`
+	err := getQuerier(ctx, a.db).GetContext(ctx, &user, query, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -157,65 +174,255 @@ func (a *PostgreSQLUserAdapter) GetUserByEmail(ctx context.Context, email string
 		return nil, err
 	}
 
+	role, err := domain.NewRole(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.User{
 		ID:        domainUserID,
 		Username:  username,
 		Email:     emailValue,
 		Balance:   domain.NewBalance(user.Balance),
+		Role:      role,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}, nil
 }
 
-// UpdateUserBalance обновляет баланс пользователя
-func (a *PostgreSQLUserAdapter) UpdateUserBalance(ctx context.Context, userID domain.UserID, balance domain.Balance) error {
-	query := `UPDATE users SET balance = $1, updated_at = $2 WHERE id = $3`
-	_, err := a.db.ExecContext(ctx, query, balance.Value(), time.Now(), userID.Value())
-	return err
+// LockUser блокирует строку пользователя до конца текущей транзакции,
+// чтобы параллельные операции над одним пользователем выполнялись последовательно
+func (a *PostgreSQLUserAdapter) LockUser(ctx context.Context, userID domain.UserID) error {
+	var id string
+	err := getQuerier(ctx, a.db).GetContext(ctx, &id, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListUserIDs получает до limit идентификаторов пользователей в порядке возрастания.
+// Если after не nil, список начинается сразу после указанного пользователя.
+func (a *PostgreSQLUserAdapter) ListUserIDs(ctx context.Context, after *domain.UserID, limit int) ([]domain.UserID, error) {
+	var afterID uuid.UUID
+	if after != nil {
+		afterID = after.Value()
+	}
+
+	query := `
+		SELECT id
+		FROM users
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	var ids []string
+	if err := getQuerier(ctx, a.db).SelectContext(ctx, &ids, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса: %w", err)
+	}
+
+	result := make([]domain.UserID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := domain.UserIDFromString(id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, userID)
+	}
+
+	return result, nil
 }
 
 // leaderboardRow представляет строку результата запроса leaderboard
 type leaderboardRow struct {
-	UserID   string `db:"user_id"`
-	Username string `db:"username"`
-	Balance  int    `db:"balance"`
+	UserID    string    `db:"user_id"`
+	Username  string    `db:"username"`
+	Balance   int       `db:"balance"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// rankedLeaderboardRow строка таблицы лидеров вместе с числом участников перед позицией курсора
+type rankedLeaderboardRow struct {
+	leaderboardRow
+	Ahead int `db:"ahead"`
 }
 
 // leaderboardEntry представляет запись в таблице лидеров (локальный тип для адаптера)
 type leaderboardEntry struct {
-	Rank     int
-	UserID   string
-	Username string
-	Balance  int
+	Rank      int
+	UserID    string
+	Username  string
+	Balance   int
+	CreatedAt time.Time
+}
+
+// GetLeaderboard получает страницу пользователей, отсортированных по балансу.
+// Если after не nil, страница начинается сразу после указанной в курсоре позиции,
+// что позволяет листать таблицу без OFFSET по индексу idx_users_leaderboard.
+// Места считаются по позиции курсора, а не берутся из него.
+func (a *PostgreSQLUserAdapter) GetLeaderboard(ctx context.Context, limit int, after *usecases.LeaderboardCursor) ([]leaderboardEntry, error) {
+	var rows []leaderboardRow
+	var err error
+	startRank := 0
+
+	if after == nil {
+		query := `
+			SELECT id::text AS user_id, username, balance, created_at
+			FROM users
+			ORDER BY balance DESC, created_at ASC, id ASC
+			LIMIT $1
+		`
+		err = getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, limit)
+	} else {
+		query := `
+			SELECT id::text AS user_id, username, balance, created_at
+			FROM users
+			WHERE balance < $1
+				OR (balance = $1 AND (created_at, id) > ($2, $3::uuid))
+			ORDER BY balance DESC, created_at ASC, id ASC
+			LIMIT $4
+		`
+		err = getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, after.Balance, after.CreatedAt, after.UserID, limit)
+		if err == nil && len(rows) > 0 {
+			startRank, err = a.countLeaderboardAhead(ctx, *after, true)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса leaderboard: %w", err)
+	}
+
+	result := make([]leaderboardEntry, len(rows))
+	for i := range rows {
+		result[i] = leaderboardEntry{
+			Rank:      startRank + i + 1,
+			UserID:    rows[i].UserID,
+			Username:  rows[i].Username,
+			Balance:   rows[i].Balance,
+			CreatedAt: rows[i].CreatedAt,
+		}
+	}
+
+	return result, nil
 }
 
-// GetLeaderboard получает топ пользователей по балансу
-func (a *PostgreSQLUserAdapter) GetLeaderboard(ctx context.Context, limit int) ([]leaderboardEntry, error) {
+// GetLeaderboardBefore получает до limit пользователей, стоящих в таблице лидеров непосредственно перед before.
+// Записи возвращаются в порядке возрастания места. Запрос читает индекс idx_users_leaderboard в обратном порядке.
+func (a *PostgreSQLUserAdapter) GetLeaderboardBefore(ctx context.Context, before usecases.LeaderboardCursor, limit int) ([]leaderboardEntry, error) {
 	query := `
-		SELECT 
-			id::text as user_id,
-			username,
-			balance
+		SELECT id::text AS user_id, username, balance, created_at
 		FROM users
-		ORDER BY balance DESC, created_at ASC
-		LIMIT $1
+		WHERE balance > $1
+			OR (balance = $1 AND (created_at, id) < ($2, $3::uuid))
+		ORDER BY balance ASC, created_at DESC, id DESC
+		LIMIT $4
 	`
 
 	var rows []leaderboardRow
-	err := a.db.SelectContext(ctx, &rows, query, limit)
+	err := getQuerier(ctx, a.db).SelectContext(ctx, &rows, query, before.Balance, before.CreatedAt, before.UserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса leaderboard: %w", err)
+	}
+	if len(rows) == 0 {
+		return []leaderboardEntry{}, nil
+	}
+
+	// Первая строка стоит непосредственно перед before, ее место равно числу пользователей перед before
+	lastRank, err := a.countLeaderboardAhead(ctx, before, false)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения SQL запроса leaderboard: %w", err)
 	}
 
 	result := make([]leaderboardEntry, len(rows))
 	for i := range rows {
-		result[i] = leaderboardEntry{
-			Rank:     i + 1,
-			UserID:   rows[i].UserID,
-			Username: rows[i].Username,
-			Balance:  rows[i].Balance,
+		result[len(rows)-1-i] = leaderboardEntry{
+			Rank:      lastRank - i,
+			UserID:    rows[i].UserID,
+			Username:  rows[i].Username,
+			Balance:   rows[i].Balance,
+			CreatedAt: rows[i].CreatedAt,
 		}
 	}
 
 	return result, nil
 }
+
+// countLeaderboardAhead получает число пользователей, стоящих в таблице лидеров перед позицией cursor.
+// Если inclusive, учитывается и пользователь, стоящий на самой позиции, если он еще там.
+// Подсчет разбит на два диапазона индекса idx_users_leaderboard: пользователи с большим балансом
+// и пользователи с тем же балансом, зарегистрированные раньше. Каждый диапазон читается
+// index only scan, поэтому стоимость растет с местом позиции, а не с размером таблицы.
+func (a *PostgreSQLUserAdapter) countLeaderboardAhead(ctx context.Context, cursor usecases.LeaderboardCursor, inclusive bool) (int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE balance > $1)
+			+ (
+				SELECT COUNT(*)
+				FROM users
+				WHERE balance = $1 AND (created_at, id) < ($2, $3::uuid)
+			)
+			+ CASE WHEN $4 THEN (
+				SELECT COUNT(*)
+				FROM users
+				WHERE id = $3::uuid AND balance = $1 AND created_at = $2
+			) ELSE 0 END
+	`
+
+	var count int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, query, cursor.Balance, cursor.CreatedAt, cursor.UserID, inclusive)
+	return count, err
+}
+
+// GetLeaderboardPosition получает место пользователя в таблице лидеров.
+// Место считается с тем же порядком сортировки, что и в GetLeaderboard: как в countLeaderboardAhead,
+// подсчет разбит на два диапазона индекса idx_users_leaderboard, и запрос читает столько записей
+// индекса, сколько пользователей стоит выше. Для первых мест это единицы строк, для последних — вся таблица,
+// поэтому в приложении места считает cache.LeaderboardCache, а этот запрос используется только до прогрева кэша.
+func (a *PostgreSQLUserAdapter) GetLeaderboardPosition(ctx context.Context, userID domain.UserID) (*leaderboardEntry, error) {
+	var row struct {
+		leaderboardRow
+		Rank int `db:"rank"`
+	}
+
+	query := `
+		SELECT
+			u.id::text AS user_id,
+			u.username,
+			u.balance,
+			u.created_at,
+			(SELECT COUNT(*) FROM users o WHERE o.balance > u.balance)
+			+ (
+				SELECT COUNT(*)
+				FROM users o
+				WHERE o.balance = u.balance AND (o.created_at, o.id) < (u.created_at, u.id)
+			) + 1 AS rank
+		FROM users u
+		WHERE u.id = $1
+	`
+
+	err := getQuerier(ctx, a.db).GetContext(ctx, &row, query, userID.Value())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка выполнения SQL запроса позиции в leaderboard: %w", err)
+	}
+
+	return &leaderboardEntry{
+		Rank:      row.Rank,
+		UserID:    row.UserID,
+		Username:  row.Username,
+		Balance:   row.Balance,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+// CountLeaderboardUsers получает число пользователей в таблице лидеров
+func (a *PostgreSQLUserAdapter) CountLeaderboardUsers(ctx context.Context) (int, error) {
+	var count int
+	err := getQuerier(ctx, a.db).GetContext(ctx, &count, `SELECT COUNT(*) FROM users`)
+	return count, err
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// extensions расширения файлов для поддерживаемых типов содержимого
+var extensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// LocalProofStorage хранит файлы подтверждения на локальном диске
+type LocalProofStorage struct {
+	dir string
+}
+
+// NewLocalProofStorage создает хранилище файлов в директории dir
+func NewLocalProofStorage(dir string) (*LocalProofStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ошибка создания директории для файлов подтверждения: %w", err)
+	}
+	return &LocalProofStorage{dir: dir}, nil
+}
+
+// Save сохраняет файл под случайным именем и возвращает это имя в качестве ключа
+func (s *LocalProofStorage) Save(ctx context.Context, contentType string, content io.Reader) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	ext, ok := extensions[contentType]
+	if !ok {
+		return "", fmt.Errorf("неподдерживаемый тип файла %s", contentType)
+	}
+
+	key := uuid.New().String() + ext
+	path := filepath.Join(s.dir, key)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return key, nil
+}
+
+// Open открывает файл по ключу
+func (s *LocalProofStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete удаляет файл по ключу. Отсутствие файла не считается ошибкой.
+func (s *LocalProofStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path возвращает путь к файлу, не позволяя ключу выйти за пределы директории хранилища
+func (s *LocalProofStorage) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key == "." || key == ".." {
+		return "", fmt.Errorf("некорректный ключ файла %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+)
+
+// signaturePrefix префикс подписи в заголовке запроса
+const signaturePrefix = "sha256="
+
+// callbackPayload тело callback от партнерского сервиса
+type callbackPayload struct {
+	EventID           string `json:"event_id"`
+	ExternalAccountID string `json:"external_account_id"`
+	TaskType          string `json:"task_type"`
+}
+
+// HMACTaskVerifier проверяет callback, подписанные HMAC-SHA256 общим секретом партнера.
+// Подписывается строка "<timestamp>.<body>", где timestamp - Unix время в секундах.
+type HMACTaskVerifier struct {
+	secrets   map[domain.ExternalProvider][]byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewHMACTaskVerifier создает проверку подписи с секретами партнеров.
+// tolerance задает допустимое расхождение timestamp запроса с текущим временем.
+func NewHMACTaskVerifier(secrets map[string]string, tolerance time.Duration) (*HMACTaskVerifier, error) {
+	verifier := &HMACTaskVerifier{
+		secrets:   make(map[domain.ExternalProvider][]byte, len(secrets)),
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+
+	for partner, secret := range secrets {
+		provider, err := domain.NewExternalProvider(partner)
+		if err != nil {
+			return nil, err
+		}
+		verifier.secrets[provider] = []byte(secret)
+	}
+
+	return verifier, nil
+}
+
+// Verify проверяет подпись и timestamp callback и разбирает его тело
+func (v *HMACTaskVerifier) Verify(partner domain.ExternalProvider, signature, timestamp string, body []byte) (usecases.TaskCallback, error) {
+	secret, ok := v.secrets[partner]
+	if !ok {
+		return usecases.TaskCallback{}, fmt.Errorf("%w: callback от %s не принимаются", domain.ErrInvalidSignature, partner)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return usecases.TaskCallback{}, fmt.Errorf("%w: некорректный timestamp", domain.ErrInvalidSignature)
+	}
+
+	sentAt := time.Unix(unix, 0)
+	if diff := v.now().Sub(sentAt); diff > v.tolerance || diff < -v.tolerance {
+		return usecases.TaskCallback{}, fmt.Errorf("%w: timestamp вне допустимого интервала", domain.ErrInvalidSignature)
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil || !strings.HasPrefix(signature, signaturePrefix) {
+		return usecases.TaskCallback{}, domain.ErrInvalidSignature
+	}
+
+	if !hmac.Equal(expected, sign(secret, timestamp, body)) {
+		return usecases.TaskCallback{}, domain.ErrInvalidSignature
+	}
+
+	var payload callbackPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return usecases.TaskCallback{}, fmt.Errorf("%w: некорректное тело callback", domain.ErrInvalidTask)
+	}
+	if payload.EventID == "" || len(payload.EventID) > 100 || payload.ExternalAccountID == "" || payload.TaskType == "" {
+		return usecases.TaskCallback{}, fmt.Errorf("%w: callback должен содержать event_id, external_account_id и task_type", domain.ErrInvalidTask)
+	}
+
+	return usecases.TaskCallback{
+		EventID:           payload.EventID,
+		ExternalAccountID: payload.ExternalAccountID,
+		TaskType:          payload.TaskType,
+		OccurredAt:        sentAt,
+	}, nil
+}
+
+// Sign подписывает тело callback так, как это должен делать партнерский сервис.
+// Возвращает значения заголовков подписи и timestamp.
+func Sign(secret string, sentAt time.Time, body []byte) (signature, timestamp string) {
+	timestamp = strconv.FormatInt(sentAt.Unix(), 10)
+	return signaturePrefix + hex.EncodeToString(sign([]byte(secret), timestamp, body)), timestamp
+}
+
+func sign(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
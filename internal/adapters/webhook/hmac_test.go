@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+const testSecret = "partner-secret"
+
+// fakeSigner подписывает callback так же, как партнерский сервис, независимо от кода проверки
+type fakeSigner struct {
+	secret string
+}
+
+func (s fakeSigner) sign(sentAt time.Time, body []byte) (signature, timestamp string) {
+	timestamp = strconv.FormatInt(sentAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil)), timestamp
+}
+
+func newTestVerifier(t *testing.T, now time.Time) *HMACTaskVerifier {
+	t.Helper()
+
+	verifier, err := NewHMACTaskVerifier(map[string]string{"telegram": testSecret}, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier.now = func() time.Time { return now }
+	return verifier
+}
+
+func TestHMACTaskVerifierVerify(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event_id":"evt-1","external_account_id":"42","task_type":"subscribe_telegram"}`)
+	tampered := []byte(`{"event_id":"evt-1","external_account_id":"43","task_type":"subscribe_telegram"}`)
+
+	tests := []struct {
+		name    string
+		signer  fakeSigner
+		sentAt  time.Time
+		body    []byte
+		wantErr error
+	}{
+		{"valid signature", fakeSigner{testSecret}, now, body, nil},
+		{"within tolerance", fakeSigner{testSecret}, now.Add(-4 * time.Minute), body, nil},
+		{"tampered body", fakeSigner{testSecret}, now, tampered, domain.ErrInvalidSignature},
+		{"wrong secret", fakeSigner{"other-secret"}, now, body, domain.ErrInvalidSignature},
+		{"stale timestamp", fakeSigner{testSecret}, now.Add(-6 * time.Minute), body, domain.ErrInvalidSignature},
+		{"future timestamp", fakeSigner{testSecret}, now.Add(6 * time.Minute), body, domain.ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := newTestVerifier(t, now)
+
+			signature, timestamp := tt.signer.sign(tt.sentAt, body)
+			callback, err := verifier.Verify(domain.ExternalProviderTelegram, signature, timestamp, tt.body)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if callback.EventID != "evt-1" || callback.ExternalAccountID != "42" || callback.TaskType != "subscribe_telegram" {
+				t.Errorf("неверно разобран callback: %+v", callback)
+			}
+			if !callback.OccurredAt.Equal(tt.sentAt) {
+				t.Errorf("OccurredAt %v, ожидалось %v", callback.OccurredAt, tt.sentAt)
+			}
+		})
+	}
+}
+
+func TestHMACTaskVerifierUnknownPartner(t *testing.T) {
+	now := time.Now()
+	verifier := newTestVerifier(t, now)
+
+	body := []byte(`{"event_id":"evt-1","external_account_id":"42","task_type":"subscribe_twitter"}`)
+	signature, timestamp := fakeSigner{testSecret}.sign(now, body)
+
+	if _, err := verifier.Verify(domain.ExternalProviderTwitter, signature, timestamp, body); !errors.Is(err, domain.ErrInvalidSignature) {
+		t.Fatalf("ошибка %v, ожидалась %v", err, domain.ErrInvalidSignature)
+	}
+}
+
+func TestSignMatchesPartnerSignature(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"event_id":"evt-1"}`)
+
+	wantSignature, wantTimestamp := fakeSigner{testSecret}.sign(now, body)
+	signature, timestamp := Sign(testSecret, now, body)
+
+	if signature != wantSignature || timestamp != wantTimestamp {
+		t.Errorf("Sign вернул %s/%s, ожидалось %s/%s", signature, timestamp, wantSignature, wantTimestamp)
+	}
+}
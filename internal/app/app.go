@@ -10,24 +10,37 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 
+	"user-rewards-api/internal/adapters/cache"
+	"user-rewards-api/internal/adapters/mailer"
 	"user-rewards-api/internal/adapters/postgresql"
+	"user-rewards-api/internal/adapters/storage"
+	"user-rewards-api/internal/adapters/webhook"
 	"user-rewards-api/internal/config"
 	httpController "user-rewards-api/internal/controllers/http"
 	"user-rewards-api/internal/database"
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/jwks"
 	authMiddleware "user-rewards-api/internal/middleware"
 	"user-rewards-api/internal/usecases"
 )
 
 type App struct {
-	config *config.Config
-	db     *sql.DB
-	router *gin.Engine
-	server *http.Server
+	config           *config.Config
+	db               *sql.DB
+	router           *gin.Engine
+	server           *http.Server
+	idempotencyStore *postgresql.PostgreSQLIdempotencyAdapter
+	leaderboardCache *cache.LeaderboardCache
+	finalizeSeasons  *usecases.FinalizeSeasonsUseCase
+	vestReferrals    *usecases.VestReferralsUseCase
+	expirePoints     *usecases.ExpirePointsUseCase
+	requestLoginCode *usecases.RequestLoginCodeUseCase
 }
 
 // NewApp создает новое приложение
@@ -40,43 +53,199 @@ func NewApp() (*App, error) {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
-	db, err := sql.Open("postgres", cfg.GetDSN())
+	keyFiles := make([]jwks.KeyFile, len(cfg.JWTKeys))
+	for i, key := range cfg.JWTKeys {
+		keyFiles[i] = jwks.KeyFile{ID: key.ID, Path: key.Path}
+	}
+
+	keySet, err := jwks.LoadKeySet(keyFiles, cfg.JWTActiveKID)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
+		return nil, fmt.Errorf("ошибка загрузки ключей подписи JWT: %w", err)
 	}
 
-	if err := db.Ping(); err != nil {
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	pointsExpiry, err := domain.NewPointsExpiryPolicy(cfg.PointsExpiryMonths)
+	if err != nil {
 		db.Close()
-		return nil, fmt.Errorf("ошибка ping базы данных: %w", err)
+		return nil, fmt.Errorf("ошибка конфигурации срока действия поинтов: %w", err)
 	}
 
-	slog.Info("Подключение к базе данных установлено")
+	postgresAdapter := postgresql.NewPostgreSQLAdapter(sqlxDB, pointsExpiry)
+	idempotencyStore := postgresql.NewPostgreSQLIdempotencyAdapter(sqlxDB)
 
-	migrationsPath := "migrations"
-	if err := database.RunMigrations(db, migrationsPath); err != nil {
+	// Места в таблице лидеров считаются только в кэше: в базе данных подсчет места
+	// перебирает всех стоящих выше пользователей, поэтому без прогретого кэша приложение не стартует
+	leaderboardCache := cache.NewLeaderboardCache(postgresAdapter)
+	if _, err := leaderboardCache.Reconcile(context.Background()); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("ошибка выполнения миграций: %w", err)
+		return nil, fmt.Errorf("ошибка прогрева кэша таблицы лидеров: %w", err)
 	}
+	var store usecases.PostgreSQLAdapter = leaderboardCache
 
-	slog.Info("Миграции выполнены успешно")
+	slog.Info("Кэш таблицы лидеров прогрет")
 
-	sqlxDB := sqlx.NewDb(db, "postgres")
+	emailMailer, err := newMailer(cfg)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка инициализации отправки писем: %w", err)
+	}
+
+	proofStorage, err := storage.NewLocalProofStorage(cfg.ProofDir)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка инициализации хранилища файлов: %w", err)
+	}
+
+	taskVerifier, err := webhook.NewHMACTaskVerifier(cfg.WebhookSecrets, cfg.WebhookTolerance)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка инициализации проверки callback: %w", err)
+	}
 
-	postgresAdapter := postgresql.NewPostgreSQLAdapter(sqlxDB)
+	commissionPlan, err := domain.NewCommissionPlan(cfg.ReferralCommissionRates)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка загрузки плана реферальных комиссий: %w", err)
+	}
 
-	createUserUC := usecases.NewCreateUserUseCase(postgresAdapter, cfg.JWTSecret)
-	getUserStatusUC := usecases.NewGetUserStatusUseCase(postgresAdapter)
-	getLeaderboardUC := usecases.NewGetLeaderboardUseCase(postgresAdapter)
-	completeTaskUC := usecases.NewCompleteTaskUseCase(postgresAdapter)
-	processReferralUC := usecases.NewProcessReferralUseCase(postgresAdapter)
+	vestingPolicy, err := domain.NewReferralVestingPolicy(
+		cfg.ReferralBonusPoints,
+		cfg.ReferrerBonusPoints,
+		cfg.ReferralVestingMinTasks,
+		cfg.ReferralVestingMinAccountAge,
+		cfg.ReferralVestingWindow,
+	)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка загрузки условий реферальных бонусов: %w", err)
+	}
+
+	loginCodeThrottle, err := domain.NewLoginCodeThrottle(cfg.LoginCodeRequestsPerEmail, cfg.LoginCodeRequestsPerIP, cfg.LoginCodeRequestWindow)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка загрузки ограничений на выдачу кодов входа: %w", err)
+	}
+
+	tokenIssuer := usecases.NewTokenIssuer(store, keySet, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	commissionPayer := usecases.NewCommissionPayer(store, commissionPlan)
+	fraudDetector := usecases.NewFraudDetector(
+		cfg.FraudHoldScore,
+		usecases.NewSharedFingerprintRule(store, cfg.FraudFingerprintWindow, cfg.FraudFingerprintMaxAccounts),
+		usecases.NewReferralBurstRule(store, cfg.FraudReferralBurstWindow, cfg.FraudReferralBurstMax),
+		usecases.NewDisposableEmailRule(cfg.DisposableEmailDomains),
+	)
+	referralVester := usecases.NewReferralVester(store, vestingPolicy, fraudDetector)
+	achievements := newAchievementEngine(store, cfg)
+
+	createUserUC := usecases.NewCreateUserUseCase(store, tokenIssuer, referralVester, achievements, cfg.StrictSignupReferralCodes)
+	getUserStatusUC := usecases.NewGetUserStatusUseCase(store, achievements, cfg.PointsExpiringWindow)
+	getLeaderboardUC := usecases.NewGetLeaderboardUseCase(store, cfg.LeaderboardMaxLimit, cfg.LeaderboardLocation)
+	getUserRankUC := usecases.NewGetUserRankUseCase(store)
+	completeTaskUC := usecases.NewCompleteTaskUseCase(store, proofStorage, commissionPayer, referralVester, achievements, cfg.TaskLocation)
+	processReferralUC := usecases.NewProcessReferralUseCase(store, referralVester, achievements)
+	setReferralCodeUC := usecases.NewSetReferralCodeUseCase(store)
+	getReferralCommissionsUC := usecases.NewGetReferralCommissionsUseCase(store, commissionPayer)
+	listReferralsForReviewUC := usecases.NewListReferralsForReviewUseCase(store)
+	releaseReferralUC := usecases.NewReleaseReferralUseCase(store, referralVester, achievements)
+	rejectReferralUC := usecases.NewRejectReferralUseCase(store)
+	listReferredUsersUC := usecases.NewListReferredUsersUseCase(store)
+	getReferralLeaderboardUC := usecases.NewGetReferralLeaderboardUseCase(store, cfg.LeaderboardMaxLimit, cfg.LeaderboardLocation)
+	createCatalogTaskUC := usecases.NewCreateCatalogTaskUseCase(store)
+	updateCatalogTaskUC := usecases.NewUpdateCatalogTaskUseCase(store)
+	archiveCatalogTaskUC := usecases.NewArchiveCatalogTaskUseCase(store)
+	listCatalogTasksUC := usecases.NewListCatalogTasksUseCase(store)
+	listSubmissionsUC := usecases.NewListSubmissionsUseCase(store)
+	listUserSubmissionsUC := usecases.NewListUserSubmissionsUseCase(store)
+	approveSubmissionUC := usecases.NewApproveSubmissionUseCase(store, commissionPayer, referralVester, achievements)
+	rejectSubmissionUC := usecases.NewRejectSubmissionUseCase(store)
+	getSubmissionProofUC := usecases.NewGetSubmissionProofUseCase(store, proofStorage)
+	processTaskCallbackUC := usecases.NewProcessTaskCallbackUseCase(store, taskVerifier, completeTaskUC)
+	linkExternalAccountUC := usecases.NewLinkExternalAccountUseCase(store)
+	createSeasonUC := usecases.NewCreateSeasonUseCase(store)
+	listSeasonsUC := usecases.NewListSeasonsUseCase(store)
+	getSeasonLeaderboardUC := usecases.NewGetSeasonLeaderboardUseCase(store, cfg.LeaderboardMaxLimit)
+	finalizeSeasonsUC := usecases.NewFinalizeSeasonsUseCase(store)
+	vestReferralsUC := usecases.NewVestReferralsUseCase(store, referralVester, achievements)
+	grantPointsUC := usecases.NewGrantPointsUseCase(store, pointsExpiry)
+	expirePointsUC := usecases.NewExpirePointsUseCase(store)
+	listUserBadgesUC := usecases.NewListUserBadgesUseCase(store, achievements)
+	createRewardUC := usecases.NewCreateRewardUseCase(store)
+	updateRewardUC := usecases.NewUpdateRewardUseCase(store)
+	listRewardsUC := usecases.NewListRewardsUseCase(store)
+	redeemRewardUC := usecases.NewRedeemRewardUseCase(store)
+	listUserRewardOrdersUC := usecases.NewListUserRewardOrdersUseCase(store)
+	listRewardOrdersUC := usecases.NewListRewardOrdersUseCase(store)
+	fulfillRewardOrderUC := usecases.NewFulfillRewardOrderUseCase(store)
+	issueTokenUC := usecases.NewIssueTokenUseCase(store, tokenIssuer)
+	refreshTokenUC := usecases.NewRefreshTokenUseCase(store, tokenIssuer)
+	logoutUC := usecases.NewLogoutUseCase(store)
+	requestLoginCodeUC := usecases.NewRequestLoginCodeUseCase(store, emailMailer, cfg.LoginCodeTTL, cfg.LoginCodeMaxAttempts, loginCodeThrottle)
+	verifyLoginCodeUC := usecases.NewVerifyLoginCodeUseCase(store, tokenIssuer)
 
 	userController := httpController.NewUserController(
 		createUserUC,
 		getUserStatusUC,
 		getLeaderboardUC,
+		getUserRankUC,
 		completeTaskUC,
 		processReferralUC,
 	)
+	taskController := httpController.NewTaskController(
+		createCatalogTaskUC,
+		updateCatalogTaskUC,
+		archiveCatalogTaskUC,
+		listCatalogTasksUC,
+	)
+	submissionController := httpController.NewSubmissionController(
+		listSubmissionsUC,
+		listUserSubmissionsUC,
+		approveSubmissionUC,
+		rejectSubmissionUC,
+		getSubmissionProofUC,
+	)
+	partnerController := httpController.NewPartnerController(
+		processTaskCallbackUC,
+		linkExternalAccountUC,
+	)
+	referralController := httpController.NewReferralController(
+		setReferralCodeUC,
+		getReferralCommissionsUC,
+		listReferralsForReviewUC,
+		releaseReferralUC,
+		rejectReferralUC,
+		listReferredUsersUC,
+		getReferralLeaderboardUC,
+	)
+	pointsController := httpController.NewPointsController(grantPointsUC)
+	badgeController := httpController.NewBadgeController(listUserBadgesUC)
+	seasonController := httpController.NewSeasonController(
+		createSeasonUC,
+		listSeasonsUC,
+		getSeasonLeaderboardUC,
+	)
+	rewardController := httpController.NewRewardController(
+		createRewardUC,
+		updateRewardUC,
+		listRewardsUC,
+		redeemRewardUC,
+		listUserRewardOrdersUC,
+		listRewardOrdersUC,
+		fulfillRewardOrderUC,
+	)
+	authController := httpController.NewAuthController(
+		issueTokenUC,
+		refreshTokenUC,
+		logoutUC,
+		requestLoginCodeUC,
+		verifyLoginCodeUC,
+	)
+	jwksController := httpController.NewJWKSController(keySet)
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
@@ -84,15 +253,68 @@ func NewApp() (*App, error) {
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
 
-	router.POST("/users", userController.CreateUser)
+	idempotency := authMiddleware.IdempotencyMiddleware(idempotencyStore, cfg.IdempotencyTTL)
+	signupIdempotency := authMiddleware.SignupIdempotencyMiddleware(idempotencyStore, cfg.IdempotencyTTL)
+	ownerOrAdmin := authMiddleware.RequireOwnerOrRole(domain.RoleAdmin)
+
+	router.GET("/.well-known/jwks.json", jwksController.GetJWKS)
+	router.POST("/users", signupIdempotency, userController.CreateUser)
+	router.POST("/auth/refresh", authController.RefreshToken)
+	router.POST("/auth/logout", authController.Logout)
+	router.POST("/auth/email/code", authController.RequestLoginCode)
+	router.POST("/auth/email/verify", authController.VerifyLoginCode)
+	router.POST("/webhooks/tasks/:partner", partnerController.HandleTaskCallback)
 
 	protected := router.Group("")
-	protected.Use(authMiddleware.AuthMiddleware(cfg.JWTSecret))
+	protected.Use(authMiddleware.AuthMiddleware(keySet))
 	{
+		protected.POST("/auth/token", authController.IssueToken)
+		protected.GET("/tasks", taskController.ListTasks)
 		protected.GET("/users/leaderboard", userController.GetLeaderboard)
-		protected.GET("/users/:id/status", userController.GetUserStatus)
-		protected.POST("/users/:id/task/complete", userController.CompleteTask)
-		protected.POST("/users/:id/referrer", userController.ProcessReferral)
+		protected.GET("/users/:id/status", ownerOrAdmin, userController.GetUserStatus)
+		protected.GET("/users/:id/rank", userController.GetUserRank)
+		protected.GET("/users/:id/badges", badgeController.GetUserBadges)
+		protected.POST("/users/:id/task/complete", ownerOrAdmin, idempotency, userController.CompleteTask)
+		protected.POST("/users/:id/referrer", ownerOrAdmin, idempotency, userController.ProcessReferral)
+		protected.POST("/users/:id/referral-code", ownerOrAdmin, referralController.SetReferralCode)
+		protected.GET("/users/:id/commissions", ownerOrAdmin, referralController.GetReferralCommissions)
+		protected.GET("/users/:id/referrals", ownerOrAdmin, referralController.ListReferredUsers)
+		protected.GET("/referrals/leaderboard", referralController.GetReferralLeaderboard)
+		protected.GET("/users/:id/submissions", ownerOrAdmin, submissionController.ListUserSubmissions)
+		protected.POST("/users/:id/external-accounts", ownerOrAdmin, partnerController.LinkExternalAccount)
+		protected.GET("/seasons", seasonController.ListSeasons)
+		protected.GET("/rewards", rewardController.ListRewards)
+		protected.POST("/users/:id/orders", ownerOrAdmin, idempotency, rewardController.RedeemReward)
+		protected.GET("/users/:id/orders", ownerOrAdmin, rewardController.ListUserOrders)
+		protected.GET("/seasons/:id/leaderboard", seasonController.GetSeasonLeaderboard)
+	}
+
+	moderation := protected.Group("/moderation")
+	moderation.Use(authMiddleware.RequireRole(domain.RoleModerator, domain.RoleAdmin))
+	{
+		moderation.GET("/submissions", submissionController.ListSubmissions)
+		moderation.GET("/submissions/:id/proof", submissionController.GetSubmissionProof)
+		moderation.POST("/submissions/:id/approve", submissionController.ApproveSubmission)
+		moderation.POST("/submissions/:id/reject", submissionController.RejectSubmission)
+		moderation.GET("/referrals", referralController.ListReferralsForReview)
+		moderation.POST("/referrals/:id/release", referralController.ReleaseReferral)
+		moderation.POST("/referrals/:id/reject", referralController.RejectReferral)
+	}
+
+	admin := protected.Group("/admin")
+	admin.Use(authMiddleware.RequireRole(domain.RoleAdmin))
+	{
+		admin.GET("/tasks", taskController.ListAllTasks)
+		admin.POST("/tasks", taskController.CreateTask)
+		admin.PATCH("/tasks/:key", taskController.UpdateTask)
+		admin.POST("/tasks/:key/archive", taskController.ArchiveTask)
+		admin.POST("/seasons", seasonController.CreateSeason)
+		admin.GET("/rewards", rewardController.ListAllRewards)
+		admin.POST("/rewards", rewardController.CreateReward)
+		admin.PATCH("/rewards/:id", rewardController.UpdateReward)
+		admin.GET("/orders", rewardController.ListOrders)
+		admin.POST("/orders/:id/fulfill", rewardController.FulfillOrder)
+		admin.POST("/users/:id/points", idempotency, pointsController.GrantPoints)
 	}
 
 	server := &http.Server{
@@ -104,15 +326,73 @@ func NewApp() (*App, error) {
 	}
 
 	return &App{
-		config: cfg,
-		db:     db,
-		router: router,
-		server: server,
+		config:           cfg,
+		db:               db,
+		router:           router,
+		server:           server,
+		idempotencyStore: idempotencyStore,
+		leaderboardCache: leaderboardCache,
+		finalizeSeasons:  finalizeSeasonsUC,
+		vestReferrals:    vestReferralsUC,
+		expirePoints:     expirePointsUC,
+		requestLoginCode: requestLoginCodeUC,
 	}, nil
 }
 
+// openDatabase подключается к базе данных и применяет миграции
+func openDatabase(cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка ping базы данных: %w", err)
+	}
+
+	slog.Info("Подключение к базе данных установлено")
+
+	migrationsPath := "migrations"
+	if err := database.RunMigrations(db, migrationsPath); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка выполнения миграций: %w", err)
+	}
+
+	slog.Info("Миграции выполнены успешно")
+	return db, nil
+}
+
+// newAchievementEngine создает движок значков с набором значков по умолчанию.
+// Неделя для значков за место в таблице лидеров начинается в часовом поясе таблицы лидеров.
+func newAchievementEngine(store usecases.PostgreSQLAdapter, cfg *config.Config) *usecases.AchievementEngine {
+	return usecases.NewAchievementEngine(store, domain.DefaultBadges(), cfg.LeaderboardLocation)
+}
+
+// newMailer создает адаптер отправки писем согласно конфигурации
+func newMailer(cfg *config.Config) (usecases.Mailer, error) {
+	switch cfg.Mailer {
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	case "memory":
+		return mailer.NewMemoryMailer(), nil
+	default:
+		return mailer.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	}
+}
+
 // Run запускает приложение
 func (a *App) Run() error {
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	go a.runIdempotencyCleanup(jobsCtx)
+	go a.runSeasonFinalization(jobsCtx)
+	go a.runReferralVesting(jobsCtx)
+	go a.runPointsExpiry(jobsCtx)
+	go a.runLoginCodeRequestCleanup(jobsCtx)
+	go a.runLeaderboardCacheReconcile(jobsCtx)
+
 	go func() {
 		slog.Info("Сервер запущен", "port", a.config.ServerPort)
 		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -125,6 +405,7 @@ func (a *App) Run() error {
 	<-quit
 
 	slog.Info("Остановка сервера...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -137,6 +418,136 @@ func (a *App) Run() error {
 	return nil
 }
 
+// runIdempotencyCleanup периодически удаляет ключи идемпотентности с истекшим сроком хранения
+func (a *App) runIdempotencyCleanup(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := a.idempotencyStore.DeleteExpired(ctx)
+			if err != nil {
+				slog.Error("Ошибка очистки ключей идемпотентности", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				slog.Info("Удалены устаревшие ключи идемпотентности", "count", deleted)
+			}
+		}
+	}
+}
+
+// runLeaderboardCacheReconcile периодически сверяет кэш таблицы лидеров с базой данных
+func (a *App) runLeaderboardCacheReconcile(ctx context.Context) {
+	ticker := time.NewTicker(a.config.LeaderboardCacheReconcile)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mismatches, err := a.leaderboardCache.Reconcile(ctx)
+			if err != nil {
+				slog.Error("Ошибка сверки кэша таблицы лидеров", "error", err)
+				continue
+			}
+			if mismatches > 0 {
+				slog.Warn("Кэш таблицы лидеров расходился с базой данных", "mismatches", mismatches)
+			}
+		}
+	}
+}
+
+// runSeasonFinalization периодически сохраняет итоговые таблицы завершившихся сезонов
+func (a *App) runSeasonFinalization(ctx context.Context) {
+	ticker := time.NewTicker(a.config.SeasonFinalizeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			finalized, err := a.finalizeSeasons.Execute(ctx)
+			if err != nil {
+				slog.Error("Ошибка сохранения итогов сезонов", "error", err)
+			}
+			if finalized > 0 {
+				slog.Info("Сохранены итоги завершившихся сезонов", "count", finalized)
+			}
+		}
+	}
+}
+
+// runReferralVesting периодически начисляет бонусы по реферальным связям, условия которых
+// выполнились со временем, и отменяет связи с истекшим сроком
+func (a *App) runReferralVesting(ctx context.Context) {
+	ticker := time.NewTicker(a.config.ReferralVestingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			vested, cancelled, err := a.vestReferrals.Execute(ctx)
+			if err != nil {
+				slog.Error("Ошибка проверки реферальных связей", "error", err)
+			}
+			if vested > 0 || cancelled > 0 {
+				slog.Info("Проверены ожидающие реферальные связи", "vested", vested, "cancelled", cancelled)
+			}
+		}
+	}
+}
+
+// runPointsExpiry периодически списывает поинты, срок действия которых истек
+func (a *App) runPointsExpiry(ctx context.Context) {
+	ticker := time.NewTicker(a.config.PointsExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			users, points, err := a.expirePoints.Execute(ctx)
+			if err != nil {
+				slog.Error("Ошибка списания сгоревших поинтов", "error", err)
+			}
+			if users > 0 {
+				slog.Info("Списаны сгоревшие поинты", "users", users, "points", points)
+			}
+		}
+	}
+}
+
+// runLoginCodeRequestCleanup периодически удаляет запросы кодов входа, которые уже не учитываются в лимите
+func (a *App) runLoginCodeRequestCleanup(ctx context.Context) {
+	ticker := time.NewTicker(a.config.LoginCodeRequestWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := a.requestLoginCode.Cleanup(ctx)
+			if err != nil {
+				slog.Error("Ошибка очистки запросов кодов входа", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				slog.Info("Удалены устаревшие запросы кодов входа", "count", deleted)
+			}
+		}
+	}
+}
+
 // Close закрывает ресурсы приложения
 func (a *App) Close() error {
 	if a.db != nil {
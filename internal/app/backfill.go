@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/adapters/postgresql"
+	"user-rewards-api/internal/config"
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BackfillBadges выдает значки всем пользователям, условия которых уже выполнены.
+// Запускается после добавления новых значков, чтобы их получили и существующие пользователи.
+// Возвращает число проверенных пользователей и число выданных значков.
+func BackfillBadges(ctx context.Context) (users, awarded int, err error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return 0, 0, fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+
+	pointsExpiry, err := domain.NewPointsExpiryPolicy(cfg.PointsExpiryMonths)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ошибка конфигурации срока действия поинтов: %w", err)
+	}
+
+	db, err := openDatabase(cfg)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer db.Close()
+
+	store := postgresql.NewPostgreSQLAdapter(sqlx.NewDb(db, "postgres"), pointsExpiry)
+	backfill := usecases.NewBackfillBadgesUseCase(store, newAchievementEngine(store, cfg))
+
+	return backfill.Execute(ctx)
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,8 +19,67 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	DBSSLMode  string
-	JWTSecret  string
 	ServerPort string
+
+	JWTKeys      []JWTKeyFile
+	JWTActiveKID string
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	IdempotencyTTL  time.Duration
+
+	Mailer       string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	LoginCodeTTL              time.Duration
+	LoginCodeMaxAttempts      int
+	LoginCodeRequestsPerEmail int
+	LoginCodeRequestsPerIP    int
+	LoginCodeRequestWindow    time.Duration
+
+	TaskLocation *time.Location
+
+	StrictSignupReferralCodes bool
+	ReferralCommissionRates   []int
+
+	ReferralBonusPoints          int
+	ReferrerBonusPoints          int
+	ReferralVestingMinTasks      int
+	ReferralVestingMinAccountAge time.Duration
+	ReferralVestingWindow        time.Duration
+	ReferralVestingInterval      time.Duration
+
+	FraudHoldScore              int
+	FraudFingerprintWindow      time.Duration
+	FraudFingerprintMaxAccounts int
+	FraudReferralBurstWindow    time.Duration
+	FraudReferralBurstMax       int
+	DisposableEmailDomains      []string
+
+	PointsExpiryMonths   int
+	PointsExpiryInterval time.Duration
+	PointsExpiringWindow time.Duration
+
+	LeaderboardMaxLimit int
+	LeaderboardLocation *time.Location
+
+	LeaderboardCacheReconcile time.Duration
+	SeasonFinalizeInterval    time.Duration
+	ProofDir                  string
+
+	WebhookSecrets   map[string]string
+	WebhookTolerance time.Duration
+}
+
+// JWTKeyFile PEM файл ключа подписи JWT и его идентификатор kid
+type JWTKeyFile struct {
+	ID   string
+	Path string
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения
@@ -31,17 +93,224 @@ func LoadConfig() (*Config, error) {
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "user_rewards"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
-		JWTSecret:  getEnv("JWT_SECRET", "hdgi4u3ti4bot45t549t45t945bt945bt94t94t"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+
+		JWTActiveKID: getEnv("JWT_ACTIVE_KID", ""),
+
+		Mailer:       getEnv("MAILER", "file"),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@localhost"),
+		MailDir:      getEnv("MAIL_DIR", "mail"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		ProofDir: getEnv("PROOF_DIR", "uploads/proofs"),
+	}
+
+	accessTokenTTL, err := getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.AccessTokenTTL = accessTokenTTL
+
+	refreshTokenTTL, err := getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.RefreshTokenTTL = refreshTokenTTL
+
+	idempotencyTTL, err := getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.IdempotencyTTL = idempotencyTTL
+
+	loginCodeTTL, err := getEnvDuration("LOGIN_CODE_TTL", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.LoginCodeTTL = loginCodeTTL
+
+	loginCodeMaxAttempts, err := getEnvInt("LOGIN_CODE_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+	config.LoginCodeMaxAttempts = loginCodeMaxAttempts
+
+	loginCodeRequestsPerEmail, err := getEnvInt("LOGIN_CODE_REQUESTS_PER_EMAIL", 5)
+	if err != nil {
+		return nil, err
+	}
+	config.LoginCodeRequestsPerEmail = loginCodeRequestsPerEmail
+
+	loginCodeRequestsPerIP, err := getEnvInt("LOGIN_CODE_REQUESTS_PER_IP", 20)
+	if err != nil {
+		return nil, err
+	}
+	config.LoginCodeRequestsPerIP = loginCodeRequestsPerIP
+
+	loginCodeRequestWindow, err := getEnvDuration("LOGIN_CODE_REQUEST_WINDOW", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.LoginCodeRequestWindow = loginCodeRequestWindow
+
+	taskLocation, err := getEnvLocation("TASK_TIMEZONE", "UTC")
+	if err != nil {
+		return nil, err
+	}
+	config.TaskLocation = taskLocation
+
+	strictSignupReferralCodes, err := getEnvBool("STRICT_SIGNUP_REFERRAL_CODES", false)
+	if err != nil {
+		return nil, err
+	}
+	config.StrictSignupReferralCodes = strictSignupReferralCodes
+
+	referralCommissionRates, err := parseCommissionRates(getEnv("REFERRAL_COMMISSION_RATES", "10,3"))
+	if err != nil {
+		return nil, err
+	}
+	config.ReferralCommissionRates = referralCommissionRates
+
+	referralBonusPoints, err := getEnvInt("REFERRAL_BONUS_POINTS", 100)
+	if err != nil {
+		return nil, err
+	}
+	config.ReferralBonusPoints = referralBonusPoints
+
+	referrerBonusPoints, err := getEnvInt("REFERRER_BONUS_POINTS", 100)
+	if err != nil {
+		return nil, err
+	}
+	config.ReferrerBonusPoints = referrerBonusPoints
+
+	referralVestingMinTasks, err := getEnvInt("REFERRAL_VESTING_MIN_TASKS", 3)
+	if err != nil {
+		return nil, err
+	}
+	config.ReferralVestingMinTasks = referralVestingMinTasks
+
+	referralVestingMinAccountAge, err := getEnvDuration("REFERRAL_VESTING_MIN_ACCOUNT_AGE", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.ReferralVestingMinAccountAge = referralVestingMinAccountAge
+
+	referralVestingWindow, err := getEnvDuration("REFERRAL_VESTING_WINDOW", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.ReferralVestingWindow = referralVestingWindow
+
+	referralVestingInterval, err := getEnvDuration("REFERRAL_VESTING_INTERVAL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.ReferralVestingInterval = referralVestingInterval
+
+	fraudHoldScore, err := getEnvInt("FRAUD_HOLD_SCORE", 50)
+	if err != nil {
+		return nil, err
+	}
+	config.FraudHoldScore = fraudHoldScore
+
+	fraudFingerprintWindow, err := getEnvDuration("FRAUD_FINGERPRINT_WINDOW", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.FraudFingerprintWindow = fraudFingerprintWindow
+
+	fraudFingerprintMaxAccounts, err := getEnvInt("FRAUD_FINGERPRINT_MAX_ACCOUNTS", 2)
+	if err != nil {
+		return nil, err
+	}
+	config.FraudFingerprintMaxAccounts = fraudFingerprintMaxAccounts
+
+	fraudReferralBurstWindow, err := getEnvDuration("FRAUD_REFERRAL_BURST_WINDOW", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.FraudReferralBurstWindow = fraudReferralBurstWindow
+
+	fraudReferralBurstMax, err := getEnvInt("FRAUD_REFERRAL_BURST_MAX", 5)
+	if err != nil {
+		return nil, err
+	}
+	config.FraudReferralBurstMax = fraudReferralBurstMax
+
+	config.DisposableEmailDomains = parseList(getEnv("DISPOSABLE_EMAIL_DOMAINS", "mailinator.com,10minutemail.com,guerrillamail.com,tempmail.com,yopmail.com,trashmail.com"))
+
+	pointsExpiryMonths, err := getEnvInt("POINTS_EXPIRY_MONTHS", 12)
+	if err != nil {
+		return nil, err
+	}
+	config.PointsExpiryMonths = pointsExpiryMonths
+
+	pointsExpiryInterval, err := getEnvDuration("POINTS_EXPIRY_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.PointsExpiryInterval = pointsExpiryInterval
+
+	pointsExpiringWindow, err := getEnvDuration("POINTS_EXPIRING_WINDOW", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	config.PointsExpiringWindow = pointsExpiringWindow
+
+	leaderboardMaxLimit, err := getEnvInt("LEADERBOARD_MAX_LIMIT", 500)
+	if err != nil {
+		return nil, err
+	}
+	config.LeaderboardMaxLimit = leaderboardMaxLimit
+
+	leaderboardLocation, err := getEnvLocation("LEADERBOARD_TIMEZONE", "UTC")
+	if err != nil {
+		return nil, err
 	}
+	config.LeaderboardLocation = leaderboardLocation
 
-	if config.JWTSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET не установлен")
+	leaderboardCacheReconcile, err := getEnvDuration("LEADERBOARD_CACHE_RECONCILE_INTERVAL", 10*time.Minute)
+	if err != nil {
+		return nil, err
 	}
+	config.LeaderboardCacheReconcile = leaderboardCacheReconcile
 
-	if len(config.JWTSecret) < 32 {
-		return nil, fmt.Errorf("JWT_SECRET должен быть минимум 32 символа")
+	seasonFinalizeInterval, err := getEnvDuration("SEASON_FINALIZE_INTERVAL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	config.SeasonFinalizeInterval = seasonFinalizeInterval
+
+	webhookTolerance, err := getEnvDuration("WEBHOOK_TOLERANCE", 5*time.Minute)
+	if err != nil {
+		return nil, err
 	}
+	config.WebhookTolerance = webhookTolerance
+
+	webhookSecrets, err := parseWebhookSecrets(os.Getenv("WEBHOOK_SECRETS"))
+	if err != nil {
+		return nil, err
+	}
+	config.WebhookSecrets = webhookSecrets
+
+	switch config.Mailer {
+	case "smtp":
+		if config.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST не установлен")
+		}
+	case "file", "memory":
+	default:
+		return nil, fmt.Errorf("MAILER должен быть одним из: smtp, file, memory")
+	}
+
+	jwtKeys, err := parseJWTKeys(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+	config.JWTKeys = jwtKeys
 
 	return config, nil
 }
@@ -68,3 +337,121 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// parseJWTKeys разбирает список ключей вида "kid1=/path/key1.pem,kid2=/path/key2.pem"
+func parseJWTKeys(value string) ([]JWTKeyFile, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("JWT_KEYS не установлен")
+	}
+
+	var keys []JWTKeyFile
+	for _, item := range strings.Split(value, ",") {
+		id, path, ok := strings.Cut(strings.TrimSpace(item), "=")
+		id, path = strings.TrimSpace(id), strings.TrimSpace(path)
+		if !ok || id == "" || path == "" {
+			return nil, fmt.Errorf("JWT_KEYS должен иметь формат kid=path[,kid=path]")
+		}
+		keys = append(keys, JWTKeyFile{ID: id, Path: path})
+	}
+	return keys, nil
+}
+
+// parseWebhookSecrets разбирает секреты партнеров вида "telegram=secret1,twitter=secret2".
+// Пустое значение отключает прием callback.
+func parseWebhookSecrets(value string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return secrets, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		partner, secret, ok := strings.Cut(strings.TrimSpace(item), "=")
+		partner, secret = strings.TrimSpace(partner), strings.TrimSpace(secret)
+		if !ok || partner == "" || secret == "" {
+			return nil, fmt.Errorf("WEBHOOK_SECRETS должен иметь формат partner=secret[,partner=secret]")
+		}
+		secrets[partner] = secret
+	}
+	return secrets, nil
+}
+
+// parseList разбирает список значений через запятую, пропуская пустые
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseCommissionRates разбирает ставки реферальных комиссий в процентах по уровням,
+// например "10,3" или "5,2.5", и возвращает их в базисных пунктах.
+// Значение "none" отключает комиссии.
+func parseCommissionRates(value string) ([]int, error) {
+	if strings.TrimSpace(value) == "none" {
+		return nil, nil
+	}
+
+	var rates []int
+	for _, item := range strings.Split(value, ",") {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("REFERRAL_COMMISSION_RATES должен содержать проценты от 0 до 100 через запятую или none")
+		}
+		rates = append(rates, int(math.Round(percent*100)))
+	}
+	return rates, nil
+}
+
+// getEnvDuration получает длительность из переменной окружения или возвращает значение по умолчанию
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("%s должен быть положительной длительностью, например 24h", key)
+	}
+	return duration, nil
+}
+
+// getEnvInt получает положительное целое число из переменной окружения или возвращает значение по умолчанию
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("%s должен быть положительным целым числом", key)
+	}
+	return number, nil
+}
+
+// getEnvBool получает логическое значение из переменной окружения или возвращает значение по умолчанию
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s должен быть true или false", key)
+	}
+	return result, nil
+}
+
+// getEnvLocation загружает часовой пояс из переменной окружения или возвращает значение по умолчанию
+func getEnvLocation(key, defaultValue string) (*time.Location, error) {
+	location, err := time.LoadLocation(getEnv(key, defaultValue))
+	if err != nil {
+		return nil, fmt.Errorf("%s содержит неизвестный часовой пояс: %w", key, err)
+	}
+	return location, nil
+}
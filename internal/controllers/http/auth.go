@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net/http"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/middleware"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthController struct {
+	issueTokenUC   *usecases.IssueTokenUseCase
+	refreshTokenUC *usecases.RefreshTokenUseCase
+	logoutUC       *usecases.LogoutUseCase
+
+	requestLoginCodeUC *usecases.RequestLoginCodeUseCase
+	verifyLoginCodeUC  *usecases.VerifyLoginCodeUseCase
+}
+
+func NewAuthController(
+	issueTokenUC *usecases.IssueTokenUseCase,
+	refreshTokenUC *usecases.RefreshTokenUseCase,
+	logoutUC *usecases.LogoutUseCase,
+	requestLoginCodeUC *usecases.RequestLoginCodeUseCase,
+	verifyLoginCodeUC *usecases.VerifyLoginCodeUseCase,
+) *AuthController {
+	return &AuthController{
+		issueTokenUC:       issueTokenUC,
+		refreshTokenUC:     refreshTokenUC,
+		logoutUC:           logoutUC,
+		requestLoginCodeUC: requestLoginCodeUC,
+		verifyLoginCodeUC:  verifyLoginCodeUC,
+	}
+}
+
+// IssueToken обменивает access токен на новый access токен без refresh токена
+// POST /auth/token
+func (c *AuthController) IssueToken(ctx *gin.Context) {
+	output, err := c.issueTokenUC.Execute(ctx.Request.Context(), ctx.GetString(middleware.UserIDKey), ctx.GetTime(middleware.ExpiresAtKey))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// RefreshToken обменивает refresh токен на новую пару токенов
+// POST /auth/refresh
+func (c *AuthController) RefreshToken(ctx *gin.Context) {
+	var input dto.RefreshTokenInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidRefreshToken, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.refreshTokenUC.Execute(ctx.Request.Context(), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// Logout завершает сессию, отзывая refresh токен
+// POST /auth/logout
+func (c *AuthController) Logout(ctx *gin.Context) {
+	var input dto.LogoutInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidRefreshToken, http.StatusBadRequest)
+		return
+	}
+
+	if err := c.logoutUC.Execute(ctx.Request.Context(), input); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RequestLoginCode отправляет одноразовый код входа на email
+// POST /auth/email/code
+func (c *AuthController) RequestLoginCode(ctx *gin.Context) {
+	var input dto.RequestLoginCodeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidEmail, http.StatusBadRequest)
+		return
+	}
+	input.Client = clientInfo(ctx)
+
+	if err := c.requestLoginCodeUC.Execute(ctx.Request.Context(), input); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+// VerifyLoginCode обменивает код из письма на пару токенов
+// POST /auth/email/verify
+func (c *AuthController) VerifyLoginCode(ctx *gin.Context) {
+	var input dto.VerifyLoginCodeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidLoginCode, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.verifyLoginCodeUC.Execute(ctx.Request.Context(), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BadgeController struct {
+	listUserBadgesUC *usecases.ListUserBadgesUseCase
+}
+
+func NewBadgeController(listUserBadgesUC *usecases.ListUserBadgesUseCase) *BadgeController {
+	return &BadgeController{
+		listUserBadgesUC: listUserBadgesUC,
+	}
+}
+
+// GetUserBadges получает значки пользователя
+// GET /users/:id/badges
+func (c *BadgeController) GetUserBadges(ctx *gin.Context) {
+	output, err := c.listUserBadgesUC.Execute(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+
+	"user-rewards-api/internal/jwks"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSController struct {
+	keySet *jwks.KeySet
+}
+
+func NewJWKSController(keySet *jwks.KeySet) *JWKSController {
+	return &JWKSController{
+		keySet: keySet,
+	}
+}
+
+// GetJWKS возвращает открытые ключи для проверки подписи токенов
+// GET /.well-known/jwks.json
+func (c *JWKSController) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.keySet.JWKS())
+}
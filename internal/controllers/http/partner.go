@@ -0,0 +1,75 @@
+package http
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCallbackBodySize максимальный размер тела callback партнера
+const maxCallbackBodySize = 64 << 10
+
+type PartnerController struct {
+	processTaskCallbackUC *usecases.ProcessTaskCallbackUseCase
+	linkExternalAccountUC *usecases.LinkExternalAccountUseCase
+}
+
+func NewPartnerController(
+	processTaskCallbackUC *usecases.ProcessTaskCallbackUseCase,
+	linkExternalAccountUC *usecases.LinkExternalAccountUseCase,
+) *PartnerController {
+	return &PartnerController{
+		processTaskCallbackUC: processTaskCallbackUC,
+		linkExternalAccountUC: linkExternalAccountUC,
+	}
+}
+
+// HandleTaskCallback принимает подписанный callback партнерского сервиса о выполнении задания.
+// Подпись передается в заголовке X-Webhook-Signature, время отправки - в X-Webhook-Timestamp.
+// POST /webhooks/tasks/:partner
+func (c *PartnerController) HandleTaskCallback(ctx *gin.Context) {
+	partner := ctx.Param("partner")
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxCallbackBodySize+1))
+	if err != nil || len(body) > maxCallbackBodySize {
+		sendError(ctx, domain.ErrInvalidTask, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.processTaskCallbackUC.Execute(ctx.Request.Context(), partner,
+		ctx.GetHeader("X-Webhook-Signature"), ctx.GetHeader("X-Webhook-Timestamp"), body)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Callback партнера обработан", "partner", partner, "event_id", output.EventID, "status", output.Status, "user_id", output.UserID, "task_type", output.TaskType)
+	ctx.JSON(http.StatusOK, output)
+}
+
+// LinkExternalAccount привязывает аккаунт партнерского сервиса к пользователю
+// POST /users/:id/external-accounts
+func (c *PartnerController) LinkExternalAccount(ctx *gin.Context) {
+	userIDStr := ctx.Param("id")
+
+	var input dto.LinkExternalAccountInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidExternalAccount, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.linkExternalAccountUC.Execute(ctx.Request.Context(), userIDStr, input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Внешний аккаунт привязан", "user_id", userIDStr, "provider", output.Provider)
+	ctx.JSON(http.StatusCreated, output)
+}
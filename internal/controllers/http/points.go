@@ -0,0 +1,44 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/middleware"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PointsController struct {
+	grantPointsUC *usecases.GrantPointsUseCase
+}
+
+func NewPointsController(grantPointsUC *usecases.GrantPointsUseCase) *PointsController {
+	return &PointsController{
+		grantPointsUC: grantPointsUC,
+	}
+}
+
+// GrantPoints начисляет поинты пользователю от имени администратора
+// POST /admin/users/:id/points
+func (c *PointsController) GrantPoints(ctx *gin.Context) {
+	userIDStr := ctx.Param("id")
+
+	var input dto.GrantPointsInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidPointsGrant, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.grantPointsUC.Execute(ctx.Request.Context(), userIDStr, input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Поинты начислены администратором", "user_id", userIDStr, "admin_id", ctx.GetString(middleware.UserIDKey), "points", output.Points, "reason", input.Reason, "new_balance", output.NewBalance)
+	ctx.JSON(http.StatusCreated, output)
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/middleware"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReferralController struct {
+	setReferralCodeUC        *usecases.SetReferralCodeUseCase
+	getReferralCommissionsUC *usecases.GetReferralCommissionsUseCase
+	listReferralsForReviewUC *usecases.ListReferralsForReviewUseCase
+	releaseReferralUC        *usecases.ReleaseReferralUseCase
+	rejectReferralUC         *usecases.RejectReferralUseCase
+	listReferredUsersUC      *usecases.ListReferredUsersUseCase
+	getReferralLeaderboardUC *usecases.GetReferralLeaderboardUseCase
+}
+
+func NewReferralController(
+	setReferralCodeUC *usecases.SetReferralCodeUseCase,
+	getReferralCommissionsUC *usecases.GetReferralCommissionsUseCase,
+	listReferralsForReviewUC *usecases.ListReferralsForReviewUseCase,
+	releaseReferralUC *usecases.ReleaseReferralUseCase,
+	rejectReferralUC *usecases.RejectReferralUseCase,
+	listReferredUsersUC *usecases.ListReferredUsersUseCase,
+	getReferralLeaderboardUC *usecases.GetReferralLeaderboardUseCase,
+) *ReferralController {
+	return &ReferralController{
+		setReferralCodeUC:        setReferralCodeUC,
+		getReferralCommissionsUC: getReferralCommissionsUC,
+		listReferralsForReviewUC: listReferralsForReviewUC,
+		releaseReferralUC:        releaseReferralUC,
+		rejectReferralUC:         rejectReferralUC,
+		listReferredUsersUC:      listReferredUsersUC,
+		getReferralLeaderboardUC: getReferralLeaderboardUC,
+	}
+}
+
+// SetReferralCode сохраняет собственный реферальный код пользователя
+// POST /users/:id/referral-code
+func (c *ReferralController) SetReferralCode(ctx *gin.Context) {
+	userIDStr := ctx.Param("id")
+
+	var input dto.SetReferralCodeInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidReferralCode, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.setReferralCodeUC.Execute(ctx.Request.Context(), userIDStr, input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Выбран собственный реферальный код", "user_id", userIDStr, "code", output.VanityReferralCode)
+	ctx.JSON(http.StatusCreated, output)
+}
+
+// GetReferralCommissions получает доход пользователя от реферальных комиссий по уровням
+// GET /users/:id/commissions
+func (c *ReferralController) GetReferralCommissions(ctx *gin.Context) {
+	output, err := c.getReferralCommissionsUC.Execute(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ListReferredUsers получает страницу пользователей, приглашенных пользователем
+// GET /users/:id/referrals?limit=50&cursor=...
+func (c *ReferralController) ListReferredUsers(ctx *gin.Context) {
+	limit, ok := queryLimit(ctx)
+	if !ok {
+		sendError(ctx, domain.ErrInvalidPagination, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.listReferredUsersUC.Execute(ctx.Request.Context(), ctx.Param("id"), limit, ctx.Query("cursor"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// GetReferralLeaderboard получает страницу таблицы лидеров по числу приглашенных пользователей за период
+// GET /referrals/leaderboard?period=weekly&limit=100&cursor=...
+func (c *ReferralController) GetReferralLeaderboard(ctx *gin.Context) {
+	period, err := usecases.NewLeaderboardPeriod(ctx.Query("period"))
+	if err != nil {
+		sendError(ctx, err, http.StatusBadRequest)
+		return
+	}
+
+	limit, ok := queryLimit(ctx)
+	if !ok {
+		sendError(ctx, domain.ErrInvalidPagination, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.getReferralLeaderboardUC.Execute(ctx.Request.Context(), period, limit, ctx.Query("cursor"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// queryLimit разбирает необязательный параметр limit. Возвращает 0, если параметр не передан,
+// и false, если значение не является положительным числом.
+func queryLimit(ctx *gin.Context) (int, bool) {
+	value := ctx.Query("limit")
+	if value == "" {
+		return 0, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, false
+	}
+	return limit, true
+}
+
+// ListReferralsForReview получает очередь реферальных связей, задержанных проверкой на мошенничество
+// GET /moderation/referrals?status=on_hold&limit=100
+func (c *ReferralController) ListReferralsForReview(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	output, err := c.listReferralsForReviewUC.Execute(ctx.Request.Context(), ctx.Query("status"), limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ReleaseReferral снимает задержку с реферальной связи
+// POST /moderation/referrals/:id/release
+func (c *ReferralController) ReleaseReferral(ctx *gin.Context) {
+	moderatorID := ctx.GetString(middleware.UserIDKey)
+
+	output, err := c.releaseReferralUC.Execute(ctx.Request.Context(), moderatorID, ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Реферальная связь одобрена", "referral_id", output.ReferralID, "referred_user_id", output.ReferredUserID, "moderator_id", moderatorID, "status", output.Status)
+	ctx.JSON(http.StatusOK, output)
+}
+
+// RejectReferral отменяет задержанную реферальную связь
+// POST /moderation/referrals/:id/reject
+func (c *ReferralController) RejectReferral(ctx *gin.Context) {
+	moderatorID := ctx.GetString(middleware.UserIDKey)
+
+	output, err := c.rejectReferralUC.Execute(ctx.Request.Context(), moderatorID, ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Реферальная связь отклонена", "referral_id", output.ReferralID, "referred_user_id", output.ReferredUserID, "moderator_id", moderatorID)
+	ctx.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/middleware"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RewardController struct {
+	createRewardUC         *usecases.CreateRewardUseCase
+	updateRewardUC         *usecases.UpdateRewardUseCase
+	listRewardsUC          *usecases.ListRewardsUseCase
+	redeemRewardUC         *usecases.RedeemRewardUseCase
+	listUserRewardOrdersUC *usecases.ListUserRewardOrdersUseCase
+	listRewardOrdersUC     *usecases.ListRewardOrdersUseCase
+	fulfillRewardOrderUC   *usecases.FulfillRewardOrderUseCase
+}
+
+func NewRewardController(
+	createRewardUC *usecases.CreateRewardUseCase,
+	updateRewardUC *usecases.UpdateRewardUseCase,
+	listRewardsUC *usecases.ListRewardsUseCase,
+	redeemRewardUC *usecases.RedeemRewardUseCase,
+	listUserRewardOrdersUC *usecases.ListUserRewardOrdersUseCase,
+	listRewardOrdersUC *usecases.ListRewardOrdersUseCase,
+	fulfillRewardOrderUC *usecases.FulfillRewardOrderUseCase,
+) *RewardController {
+	return &RewardController{
+		createRewardUC:         createRewardUC,
+		updateRewardUC:         updateRewardUC,
+		listRewardsUC:          listRewardsUC,
+		redeemRewardUC:         redeemRewardUC,
+		listUserRewardOrdersUC: listUserRewardOrdersUC,
+		listRewardOrdersUC:     listRewardOrdersUC,
+		fulfillRewardOrderUC:   fulfillRewardOrderUC,
+	}
+}
+
+// ListRewards получает товары, доступные в магазине наград
+// GET /rewards
+func (c *RewardController) ListRewards(ctx *gin.Context) {
+	output, err := c.listRewardsUC.Execute(ctx.Request.Context(), false)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ListAllRewards получает все товары магазина, включая неактивные и недоступные сейчас
+// GET /admin/rewards
+func (c *RewardController) ListAllRewards(ctx *gin.Context) {
+	output, err := c.listRewardsUC.Execute(ctx.Request.Context(), true)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// CreateReward добавляет товар в магазин наград
+// POST /admin/rewards
+func (c *RewardController) CreateReward(ctx *gin.Context) {
+	var input dto.CreateRewardInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidReward, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.createRewardUC.Execute(ctx.Request.Context(), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Товар добавлен в магазин наград", "reward_id", output.ID, "cost", output.Cost)
+	ctx.JSON(http.StatusCreated, output)
+}
+
+// UpdateReward изменяет товар магазина наград
+// PATCH /admin/rewards/:id
+func (c *RewardController) UpdateReward(ctx *gin.Context) {
+	var input dto.UpdateRewardInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidReward, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.updateRewardUC.Execute(ctx.Request.Context(), ctx.Param("id"), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Товар магазина наград изменен", "reward_id", output.ID, "cost", output.Cost, "active", output.Active)
+	ctx.JSON(http.StatusOK, output)
+}
+
+// RedeemReward заказывает товар за поинты
+// POST /users/:id/orders
+func (c *RewardController) RedeemReward(ctx *gin.Context) {
+	userIDStr := ctx.Param("id")
+
+	var input dto.RedeemRewardInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrRewardNotFound, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.redeemRewardUC.Execute(ctx.Request.Context(), userIDStr, input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Товар заказан", "user_id", userIDStr, "order_id", output.Order.ID, "reward_id", output.Order.RewardID, "cost", output.Order.Cost, "new_balance", output.NewBalance)
+	ctx.JSON(http.StatusCreated, output)
+}
+
+// ListUserOrders получает историю заказов пользователя
+// GET /users/:id/orders
+func (c *RewardController) ListUserOrders(ctx *gin.Context) {
+	output, err := c.listUserRewardOrdersUC.Execute(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ListOrders получает очередь заказов на выдачу
+// GET /admin/orders?status=pending&limit=100
+func (c *RewardController) ListOrders(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	output, err := c.listRewardOrdersUC.Execute(ctx.Request.Context(), ctx.Query("status"), limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// FulfillOrder отмечает заказ выданным
+// POST /admin/orders/:id/fulfill
+func (c *RewardController) FulfillOrder(ctx *gin.Context) {
+	adminID := ctx.GetString(middleware.UserIDKey)
+
+	output, err := c.fulfillRewardOrderUC.Execute(ctx.Request.Context(), adminID, ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Заказ выдан", "order_id", output.ID, "user_id", output.UserID, "admin_id", adminID)
+	ctx.JSON(http.StatusOK, output)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeasonController struct {
+	createSeasonUC         *usecases.CreateSeasonUseCase
+	listSeasonsUC          *usecases.ListSeasonsUseCase
+	getSeasonLeaderboardUC *usecases.GetSeasonLeaderboardUseCase
+}
+
+func NewSeasonController(
+	createSeasonUC *usecases.CreateSeasonUseCase,
+	listSeasonsUC *usecases.ListSeasonsUseCase,
+	getSeasonLeaderboardUC *usecases.GetSeasonLeaderboardUseCase,
+) *SeasonController {
+	return &SeasonController{
+		createSeasonUC:         createSeasonUC,
+		listSeasonsUC:          listSeasonsUC,
+		getSeasonLeaderboardUC: getSeasonLeaderboardUC,
+	}
+}
+
+// ListSeasons получает все сезоны
+// GET /seasons
+func (c *SeasonController) ListSeasons(ctx *gin.Context) {
+	output, err := c.listSeasonsUC.Execute(ctx.Request.Context())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// GetSeasonLeaderboard получает страницу таблицы лидеров сезона
+// GET /seasons/:id/leaderboard?limit=100&cursor=...
+func (c *SeasonController) GetSeasonLeaderboard(ctx *gin.Context) {
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			sendError(ctx, domain.ErrInvalidPagination, http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := c.getSeasonLeaderboardUC.Execute(ctx.Request.Context(), ctx.Param("id"), limit, ctx.Query("cursor"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// CreateSeason создает сезон
+// POST /admin/seasons
+func (c *SeasonController) CreateSeason(ctx *gin.Context) {
+	var input dto.CreateSeasonInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidSeason, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.createSeasonUC.Execute(ctx.Request.Context(), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Сезон создан", "season_id", output.ID, "name", output.Name, "starts_at", output.StartsAt, "ends_at", output.EndsAt)
+	ctx.JSON(http.StatusCreated, output)
+}
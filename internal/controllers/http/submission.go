@@ -0,0 +1,120 @@
+package http
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/middleware"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubmissionController struct {
+	listSubmissionsUC     *usecases.ListSubmissionsUseCase
+	listUserSubmissionsUC *usecases.ListUserSubmissionsUseCase
+	approveSubmissionUC   *usecases.ApproveSubmissionUseCase
+	rejectSubmissionUC    *usecases.RejectSubmissionUseCase
+	getSubmissionProofUC  *usecases.GetSubmissionProofUseCase
+}
+
+func NewSubmissionController(
+	listSubmissionsUC *usecases.ListSubmissionsUseCase,
+	listUserSubmissionsUC *usecases.ListUserSubmissionsUseCase,
+	approveSubmissionUC *usecases.ApproveSubmissionUseCase,
+	rejectSubmissionUC *usecases.RejectSubmissionUseCase,
+	getSubmissionProofUC *usecases.GetSubmissionProofUseCase,
+) *SubmissionController {
+	return &SubmissionController{
+		listSubmissionsUC:     listSubmissionsUC,
+		listUserSubmissionsUC: listUserSubmissionsUC,
+		approveSubmissionUC:   approveSubmissionUC,
+		rejectSubmissionUC:    rejectSubmissionUC,
+		getSubmissionProofUC:  getSubmissionProofUC,
+	}
+}
+
+// ListSubmissions получает очередь заявок на проверку
+// GET /moderation/submissions?status=pending&limit=100
+func (c *SubmissionController) ListSubmissions(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	output, err := c.listSubmissionsUC.Execute(ctx.Request.Context(), ctx.Query("status"), limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ListUserSubmissions получает заявки пользователя
+// GET /users/:id/submissions
+func (c *SubmissionController) ListUserSubmissions(ctx *gin.Context) {
+	output, err := c.listUserSubmissionsUC.Execute(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ApproveSubmission одобряет заявку и начисляет поинты
+// POST /moderation/submissions/:id/approve
+func (c *SubmissionController) ApproveSubmission(ctx *gin.Context) {
+	moderatorID := ctx.GetString(middleware.UserIDKey)
+
+	output, err := c.approveSubmissionUC.Execute(ctx.Request.Context(), moderatorID, ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Заявка одобрена", "submission_id", output.ID, "user_id", output.UserID, "moderator_id", moderatorID, "points", output.Points)
+	ctx.JSON(http.StatusOK, output)
+}
+
+// RejectSubmission отклоняет заявку
+// POST /moderation/submissions/:id/reject
+func (c *SubmissionController) RejectSubmission(ctx *gin.Context) {
+	moderatorID := ctx.GetString(middleware.UserIDKey)
+
+	var input dto.RejectSubmissionInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidProof, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.rejectSubmissionUC.Execute(ctx.Request.Context(), moderatorID, ctx.Param("id"), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Заявка отклонена", "submission_id", output.ID, "user_id", output.UserID, "moderator_id", moderatorID)
+	ctx.JSON(http.StatusOK, output)
+}
+
+// GetSubmissionProof отдает загруженный скриншот заявки
+// GET /moderation/submissions/:id/proof
+func (c *SubmissionController) GetSubmissionProof(ctx *gin.Context) {
+	output, err := c.getSubmissionProofUC.Execute(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	defer output.Content.Close()
+
+	content, err := io.ReadAll(output.Content)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Data(http.StatusOK, output.ContentType, content)
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskController struct {
+	createCatalogTaskUC  *usecases.CreateCatalogTaskUseCase
+	updateCatalogTaskUC  *usecases.UpdateCatalogTaskUseCase
+	archiveCatalogTaskUC *usecases.ArchiveCatalogTaskUseCase
+	listCatalogTasksUC   *usecases.ListCatalogTasksUseCase
+}
+
+func NewTaskController(
+	createCatalogTaskUC *usecases.CreateCatalogTaskUseCase,
+	updateCatalogTaskUC *usecases.UpdateCatalogTaskUseCase,
+	archiveCatalogTaskUC *usecases.ArchiveCatalogTaskUseCase,
+	listCatalogTasksUC *usecases.ListCatalogTasksUseCase,
+) *TaskController {
+	return &TaskController{
+		createCatalogTaskUC:  createCatalogTaskUC,
+		updateCatalogTaskUC:  updateCatalogTaskUC,
+		archiveCatalogTaskUC: archiveCatalogTaskUC,
+		listCatalogTasksUC:   listCatalogTasksUC,
+	}
+}
+
+// ListTasks получает задания, доступные для выполнения
+// GET /tasks
+func (c *TaskController) ListTasks(ctx *gin.Context) {
+	output, err := c.listCatalogTasksUC.Execute(ctx.Request.Context(), false)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ListAllTasks получает все задания каталога, включая неактивные и архивные
+// GET /admin/tasks
+func (c *TaskController) ListAllTasks(ctx *gin.Context) {
+	output, err := c.listCatalogTasksUC.Execute(ctx.Request.Context(), true)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// CreateTask добавляет задание в каталог
+// POST /admin/tasks
+func (c *TaskController) CreateTask(ctx *gin.Context) {
+	var input dto.CreateCatalogTaskInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidTask, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.createCatalogTaskUC.Execute(ctx.Request.Context(), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Задание добавлено в каталог", "task_key", output.Key, "points", output.Points)
+	ctx.JSON(http.StatusCreated, output)
+}
+
+// UpdateTask изменяет задание каталога
+// PATCH /admin/tasks/:key
+func (c *TaskController) UpdateTask(ctx *gin.Context) {
+	key := ctx.Param("key")
+
+	var input dto.UpdateCatalogTaskInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		sendError(ctx, domain.ErrInvalidTask, http.StatusBadRequest)
+		return
+	}
+
+	output, err := c.updateCatalogTaskUC.Execute(ctx.Request.Context(), key, input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Задание каталога изменено", "task_key", output.Key, "points", output.Points, "active", output.Active)
+	ctx.JSON(http.StatusOK, output)
+}
+
+// ArchiveTask выводит задание из каталога
+// POST /admin/tasks/:key/archive
+func (c *TaskController) ArchiveTask(ctx *gin.Context) {
+	key := ctx.Param("key")
+
+	output, err := c.archiveCatalogTaskUC.Execute(ctx.Request.Context(), key)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	slog.Info("Задание перенесено в архив", "task_key", output.Key)
+	ctx.JSON(http.StatusOK, output)
+}
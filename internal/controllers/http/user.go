@@ -1,8 +1,13 @@
 package http
 
 import (
+	"errors"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"user-rewards-api/internal/domain"
 	"user-rewards-api/internal/dto"
@@ -15,6 +20,7 @@ type UserController struct {
 	createUserUC      *usecases.CreateUserUseCase
 	getUserStatusUC   *usecases.GetUserStatusUseCase
 	getLeaderboardUC  *usecases.GetLeaderboardUseCase
+	getUserRankUC     *usecases.GetUserRankUseCase
 	completeTaskUC    *usecases.CompleteTaskUseCase
 	processReferralUC *usecases.ProcessReferralUseCase
 }
@@ -23,6 +29,7 @@ func NewUserController(
 	createUserUC *usecases.CreateUserUseCase,
 	getUserStatusUC *usecases.GetUserStatusUseCase,
 	getLeaderboardUC *usecases.GetLeaderboardUseCase,
+	getUserRankUC *usecases.GetUserRankUseCase,
 	completeTaskUC *usecases.CompleteTaskUseCase,
 	processReferralUC *usecases.ProcessReferralUseCase,
 ) *UserController {
@@ -30,6 +37,7 @@ func NewUserController(
 		createUserUC:      createUserUC,
 		getUserStatusUC:   getUserStatusUC,
 		getLeaderboardUC:  getLeaderboardUC,
+		getUserRankUC:     getUserRankUC,
 		completeTaskUC:    completeTaskUC,
 		processReferralUC: processReferralUC,
 	}
@@ -43,6 +51,7 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 		sendError(ctx, domain.ErrInvalidUsername)
 		return
 	}
+	input.Client = clientInfo(ctx)
 
 	output, err := c.createUserUC.Execute(ctx.Request.Context(), input)
 	if err != nil {
@@ -50,7 +59,11 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	slog.Info("Пользователь создан", "user_id", output.UserID, "username", output.Username)
+	if output.ReferralWarning != "" {
+		slog.Warn("Реферальный код при регистрации проигнорирован", "user_id", output.UserID, "referral_code", input.ReferralCode)
+	}
+
+	slog.Info("Пользователь создан", "user_id", output.UserID, "username", output.Username, "referrer_id", output.ReferrerID)
 	ctx.JSON(http.StatusCreated, output)
 }
 
@@ -68,12 +81,47 @@ func (c *UserController) GetUserStatus(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, output)
 }
 
-// GetLeaderboard получает таблицу лидеров
-// GET /users/leaderboard
+// GetLeaderboard получает страницу таблицы лидеров за период
+// GET /users/leaderboard?period=weekly&limit=100&cursor=...
 func (c *UserController) GetLeaderboard(ctx *gin.Context) {
-	limit := 100
+	period, err := usecases.NewLeaderboardPeriod(ctx.Query("period"))
+	if err != nil {
+		sendError(ctx, err, http.StatusBadRequest)
+		return
+	}
 
-	output, err := c.getLeaderboardUC.Execute(ctx.Request.Context(), limit)
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			sendError(ctx, domain.ErrInvalidPagination, http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := c.getLeaderboardUC.Execute(ctx.Request.Context(), period, limit, ctx.Query("cursor"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, output)
+}
+
+// GetUserRank получает место пользователя в таблице лидеров и его соседей
+// GET /users/:id/rank?neighbours=5
+func (c *UserController) GetUserRank(ctx *gin.Context) {
+	neighbours := -1
+	if value := ctx.Query("neighbours"); value != "" {
+		var err error
+		neighbours, err = strconv.Atoi(value)
+		if err != nil || neighbours < 0 {
+			sendError(ctx, domain.ErrInvalidPagination, http.StatusBadRequest)
+			return
+		}
+	}
+
+	output, err := c.getUserRankUC.Execute(ctx.Request.Context(), ctx.Param("id"), neighbours)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -88,7 +136,29 @@ func (c *UserController) CompleteTask(ctx *gin.Context) {
 	userIDStr := ctx.Param("id")
 
 	var input dto.CompleteTaskInput
-	if err := ctx.ShouldBindJSON(&input); err != nil {
+	if strings.HasPrefix(ctx.GetHeader("Content-Type"), "multipart/form-data") {
+		input.TaskType = ctx.PostForm("task_type")
+		input.Proof = ctx.PostForm("proof")
+		if input.TaskType == "" {
+			sendError(ctx, domain.ErrInvalidTaskType)
+			return
+		}
+
+		if fileHeader, err := ctx.FormFile("screenshot"); err == nil {
+			file, err := fileHeader.Open()
+			if err != nil {
+				sendError(ctx, domain.ErrInvalidProof, http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+
+			input.Screenshot = &dto.ProofUpload{
+				Filename: fileHeader.Filename,
+				Size:     fileHeader.Size,
+				Content:  file,
+			}
+		}
+	} else if err := ctx.ShouldBindJSON(&input); err != nil {
 		sendError(ctx, domain.ErrInvalidTaskType)
 		return
 	}
@@ -99,6 +169,12 @@ func (c *UserController) CompleteTask(ctx *gin.Context) {
 		return
 	}
 
+	if output.SubmissionID != "" {
+		slog.Info("Заявка на выполнение задания отправлена", "user_id", userIDStr, "task_type", input.TaskType, "submission_id", output.SubmissionID)
+		ctx.JSON(http.StatusAccepted, output)
+		return
+	}
+
 	slog.Info("Задание выполнено", "user_id", userIDStr, "task_type", input.TaskType, "points", output.Points, "new_balance", output.NewBalance)
 	ctx.JSON(http.StatusOK, output)
 }
@@ -113,6 +189,7 @@ func (c *UserController) ProcessReferral(ctx *gin.Context) {
 		sendError(ctx, domain.ErrReferrerNotFound)
 		return
 	}
+	input.Client = clientInfo(ctx)
 
 	output, err := c.processReferralUC.Execute(ctx.Request.Context(), userIDStr, input)
 	if err != nil {
@@ -120,7 +197,7 @@ func (c *UserController) ProcessReferral(ctx *gin.Context) {
 		return
 	}
 
-	slog.Info("Реферальный код использован", "user_id", userIDStr, "referrer_id", input.ReferrerID, "bonus_points", output.BonusPoints, "new_balance", output.NewBalance)
+	slog.Info("Реферальный код использован", "user_id", userIDStr, "referrer_id", output.ReferrerID, "status", output.Status, "bonus_points", output.BonusPoints, "new_balance", output.NewBalance)
 	ctx.JSON(http.StatusOK, output)
 }
 
@@ -131,20 +208,61 @@ func handleError(ctx *gin.Context, err error) {
 	}
 
 	errStr := err.Error()
+
+	var cooldownErr *domain.TaskCooldownError
 	switch {
-	case err == domain.ErrUserNotFound:
+	case errors.As(err, &cooldownErr):
+		retryAfter := int(math.Ceil(time.Until(cooldownErr.NextAvailableAt).Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+		sendError(ctx, err, http.StatusTooManyRequests)
+	case errors.Is(err, domain.ErrTooManyLoginCodeRequests):
+		sendError(ctx, err, http.StatusTooManyRequests)
+	case errors.Is(err, domain.ErrUserNotFound):
 		sendError(ctx, err, http.StatusNotFound)
-	case err == domain.ErrUserExists:
+	case errors.Is(err, domain.ErrUserExists):
+		sendError(ctx, err, http.StatusConflict)
+	case errors.Is(err, domain.ErrTaskLimitReached):
 		sendError(ctx, err, http.StatusConflict)
-	case err == domain.ErrTaskAlreadyExists:
+	case errors.Is(err, domain.ErrTaskNotFound):
+		sendError(ctx, err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrTaskExists) || errors.Is(err, domain.ErrTaskArchived):
 		sendError(ctx, err, http.StatusConflict)
-	case err == domain.ErrReferralExists:
+	case errors.Is(err, domain.ErrSubmissionNotFound):
+		sendError(ctx, err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrSubmissionReviewed):
+		sendError(ctx, err, http.StatusConflict)
+	case errors.Is(err, domain.ErrExternalVerificationOnly):
+		sendError(ctx, err, http.StatusForbidden)
+	case errors.Is(err, domain.ErrExternalAccountNotFound):
+		sendError(ctx, err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrExternalAccountLinked):
+		sendError(ctx, err, http.StatusConflict)
+	case errors.Is(err, domain.ErrSeasonNotFound):
+		sendError(ctx, err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrSeasonOverlaps):
 		sendError(ctx, err, http.StatusConflict)
-	case err == domain.ErrSelfReferral:
+	case errors.Is(err, domain.ErrReferralCodeTaken) || errors.Is(err, domain.ErrVanityCodeExists):
+		sendError(ctx, err, http.StatusConflict)
+	case errors.Is(err, domain.ErrRewardNotFound) || errors.Is(err, domain.ErrOrderNotFound):
+		sendError(ctx, err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrInsufficientBalance) || errors.Is(err, domain.ErrRewardUnavailable) || errors.Is(err, domain.ErrRewardOutOfStock) || errors.Is(err, domain.ErrRewardLimitReached) || errors.Is(err, domain.ErrOrderFulfilled):
+		sendError(ctx, err, http.StatusConflict)
+	case errors.Is(err, domain.ErrReferralNotFound):
+		sendError(ctx, err, http.StatusNotFound)
+	case errors.Is(err, domain.ErrReferralNotOnHold):
+		sendError(ctx, err, http.StatusConflict)
+	case errors.Is(err, domain.ErrReferralExists):
+		sendError(ctx, err, http.StatusConflict)
+	case errors.Is(err, domain.ErrSelfReferral):
 		sendError(ctx, err, http.StatusBadRequest)
-	case err == domain.ErrReferrerNotFound:
+	case errors.Is(err, domain.ErrReferrerNotFound):
 		sendError(ctx, err, http.StatusNotFound)
-	case err == domain.ErrInvalidUsername || err == domain.ErrInvalidEmail || err == domain.ErrInvalidTaskType:
+	case errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) || errors.Is(err, domain.ErrInvalidLoginCode) || errors.Is(err, domain.ErrInvalidSignature):
+		sendError(ctx, err, http.StatusUnauthorized)
+	case errors.Is(err, domain.ErrInvalidUsername) || errors.Is(err, domain.ErrInvalidEmail) || errors.Is(err, domain.ErrInvalidTaskType) || errors.Is(err, domain.ErrInvalidTask) || errors.Is(err, domain.ErrInvalidProof) || errors.Is(err, domain.ErrInvalidExternalAccount) || errors.Is(err, domain.ErrInvalidPagination) || errors.Is(err, domain.ErrInvalidLeaderboardPeriod) || errors.Is(err, domain.ErrInvalidSeason) || errors.Is(err, domain.ErrInvalidReferralCode) || errors.Is(err, domain.ErrInvalidReferralStatus) || errors.Is(err, domain.ErrInvalidReward) || errors.Is(err, domain.ErrInvalidOrderStatus) || errors.Is(err, domain.ErrInvalidPointsGrant):
 		sendError(ctx, err, http.StatusBadRequest)
 	default:
 		slog.Error("Внутренняя ошибка", "error", err, "error_string", errStr, "path", ctx.Request.URL.Path)
@@ -164,3 +282,15 @@ func sendError(ctx *gin.Context, err error, statusCode ...int) {
 	}
 	ctx.JSON(code, response)
 }
+
+// deviceIDHeader заголовок с необязательным идентификатором устройства клиента
+const deviceIDHeader = "X-Device-ID"
+
+// clientInfo собирает данные клиента для отпечатка, по которому проверяются реферальные связи
+func clientInfo(ctx *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		DeviceID:  ctx.GetHeader(deviceIDHeader),
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+// BadgeCriterion показатель пользователя, по которому выдается значок
+type BadgeCriterion string
+
+const (
+	// BadgeCriterionCompletedTasks число выполненных заданий не меньше порога
+	BadgeCriterionCompletedTasks BadgeCriterion = "completed_tasks"
+	// BadgeCriterionReferrals число завершенных (vested) приглашений не меньше порога
+	BadgeCriterionReferrals BadgeCriterion = "referrals"
+	// BadgeCriterionBalance баланс не меньше порога
+	BadgeCriterionBalance BadgeCriterion = "balance"
+	// BadgeCriterionWeeklyRank место в таблице лидеров текущей недели не ниже порога
+	BadgeCriterionWeeklyRank BadgeCriterion = "weekly_rank"
+)
+
+// Badge определение значка: значок выдается пользователю, показатель Criterion
+// которого достиг порога Threshold. Полученный значок не отзывается.
+type Badge struct {
+	Key         string
+	Title       string
+	Description string
+	Criterion   BadgeCriterion
+	Threshold   int
+}
+
+// DefaultBadges возвращает набор значков, выдаваемых по умолчанию
+func DefaultBadges() []Badge {
+	return []Badge{
+		{Key: "first_task", Title: "Первое задание", Description: "Выполнено первое задание", Criterion: BadgeCriterionCompletedTasks, Threshold: 1},
+		{Key: "tasks_25", Title: "Исполнитель", Description: "Выполнено 25 заданий", Criterion: BadgeCriterionCompletedTasks, Threshold: 25},
+		{Key: "first_referral", Title: "Первый друг", Description: "Приглашен первый пользователь", Criterion: BadgeCriterionReferrals, Threshold: 1},
+		{Key: "referrals_5", Title: "Амбассадор", Description: "Приглашено 5 пользователей", Criterion: BadgeCriterionReferrals, Threshold: 5},
+		{Key: "balance_1000", Title: "Копилка", Description: "На балансе 1000 поинтов", Criterion: BadgeCriterionBalance, Threshold: 1000},
+		{Key: "weekly_top_10", Title: "Лидер недели", Description: "Место в первой десятке таблицы лидеров за неделю", Criterion: BadgeCriterionWeeklyRank, Threshold: 10},
+	}
+}
+
+// AchievementStats показатели пользователя, по которым проверяются условия значков.
+// WeeklyRank равен нулю, если пользователь не попал в проверенную часть таблицы лидеров недели.
+type AchievementStats struct {
+	CompletedTasks int
+	Referrals      int
+	Balance        int
+	WeeklyRank     int
+}
+
+// IsEarned проверяет, выполнены ли условия значка
+func (b Badge) IsEarned(stats AchievementStats) bool {
+	switch b.Criterion {
+	case BadgeCriterionCompletedTasks:
+		return stats.CompletedTasks >= b.Threshold
+	case BadgeCriterionReferrals:
+		return stats.Referrals >= b.Threshold
+	case BadgeCriterionBalance:
+		return stats.Balance >= b.Threshold
+	case BadgeCriterionWeeklyRank:
+		return stats.WeeklyRank > 0 && stats.WeeklyRank <= b.Threshold
+	default:
+		return false
+	}
+}
+
+// UserBadge значок, полученный пользователем
+type UserBadge struct {
+	UserID    UserID
+	BadgeKey  string
+	AwardedAt time.Time
+}
+
+// NewUserBadge создает запись о выдаче значка пользователю
+func NewUserBadge(userID UserID, badge Badge, now time.Time) UserBadge {
+	return UserBadge{
+		UserID:    userID,
+		BadgeKey:  badge.Key,
+		AwardedAt: now,
+	}
+}
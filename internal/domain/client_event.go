@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// maxDeviceIDLength наибольшая длина идентификатора устройства в символах, остаток отбрасывается
+const maxDeviceIDLength = 100
+
+// ClientEventKind действие пользователя, при котором сохраняется отпечаток клиента
+type ClientEventKind string
+
+const (
+	ClientEventSignup   ClientEventKind = "signup"
+	ClientEventReferral ClientEventKind = "referral"
+)
+
+// ClientFingerprint отпечаток клиента: IP адрес, хеш User-Agent и необязательный
+// идентификатор устройства. Сам User-Agent не хранится.
+type ClientFingerprint struct {
+	IP            string
+	UserAgentHash string
+	DeviceID      string
+}
+
+// NewClientFingerprint создает отпечаток клиента из данных запроса
+func NewClientFingerprint(ip, userAgent, deviceID string) ClientFingerprint {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(userAgent)))
+
+	// Обрезка по символам, а не по байтам: половина многобайтового символа не сохранится в базу данных
+	deviceID = strings.ToValidUTF8(strings.TrimSpace(deviceID), "")
+	if runes := []rune(deviceID); len(runes) > maxDeviceIDLength {
+		deviceID = string(runes[:maxDeviceIDLength])
+	}
+
+	return ClientFingerprint{
+		IP:            strings.TrimSpace(ip),
+		UserAgentHash: hex.EncodeToString(hash[:]),
+		DeviceID:      deviceID,
+	}
+}
+
+// ClientEvent отпечаток клиента, сохраненный при регистрации или указании реферера
+type ClientEvent struct {
+	UserID      UserID
+	Kind        ClientEventKind
+	Fingerprint ClientFingerprint
+	CreatedAt   time.Time
+}
+
+// NewClientEvent создает событие клиента
+func NewClientEvent(userID UserID, kind ClientEventKind, fingerprint ClientFingerprint) ClientEvent {
+	return ClientEvent{
+		UserID:      userID,
+		Kind:        kind,
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNewClientFingerprintTruncatesDeviceIDByRune(t *testing.T) {
+	deviceID := strings.Repeat("a", maxDeviceIDLength-1) + "устройство"
+
+	fingerprint := NewClientFingerprint("127.0.0.1", "test", deviceID)
+
+	if !utf8.ValidString(fingerprint.DeviceID) {
+		t.Fatalf("идентификатор устройства %q не является корректной UTF-8 строкой", fingerprint.DeviceID)
+	}
+	if want := strings.Repeat("a", maxDeviceIDLength-1) + "у"; fingerprint.DeviceID != want {
+		t.Errorf("идентификатор устройства %q, ожидалось %q", fingerprint.DeviceID, want)
+	}
+}
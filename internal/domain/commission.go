@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCommissionLevels наибольшая глубина реферальной цепочки, с которой начисляются комиссии
+const MaxCommissionLevels = 10
+
+// commissionRateScale число базисных пунктов в 100%
+const commissionRateScale = 10000
+
+// CommissionPlan ставки комиссий по уровням реферальной цепочки в базисных пунктах:
+// первая ставка относится к прямому рефереру, вторая - к его рефереру и так далее.
+// Пустой план отключает комиссии.
+type CommissionPlan struct {
+	rates []int
+}
+
+// NewCommissionPlan создает план комиссий с валидацией ставок
+func NewCommissionPlan(rates []int) (CommissionPlan, error) {
+	if len(rates) > MaxCommissionLevels {
+		return CommissionPlan{}, fmt.Errorf("%w: не более %d уровней", ErrInvalidCommissionPlan, MaxCommissionLevels)
+	}
+	for i, rate := range rates {
+		if rate <= 0 || rate > commissionRateScale {
+			return CommissionPlan{}, fmt.Errorf("%w: ставка уровня %d должна быть больше 0%% и не больше 100%%", ErrInvalidCommissionPlan, i+1)
+		}
+	}
+
+	return CommissionPlan{rates: append([]int(nil), rates...)}, nil
+}
+
+// Depth возвращает число уровней, с которых начисляются комиссии
+func (p CommissionPlan) Depth() int {
+	return len(p.rates)
+}
+
+// Rate возвращает ставку уровня level в базисных пунктах или 0, если уровень вне плана
+func (p CommissionPlan) Rate(level int) int {
+	if level < 1 || level > len(p.rates) {
+		return 0
+	}
+	return p.rates[level-1]
+}
+
+// ReferralCommissionID представляет идентификатор комиссии
+type ReferralCommissionID struct {
+	value uuid.UUID
+}
+
+// NewReferralCommissionID создает новый ReferralCommissionID
+func NewReferralCommissionID() (ReferralCommissionID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return ReferralCommissionID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return ReferralCommissionID{value: id}, nil
+}
+
+// String возвращает строковое представление ReferralCommissionID
+func (id ReferralCommissionID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id ReferralCommissionID) Value() uuid.UUID {
+	return id.value
+}
+
+// ReferralCommission комиссия, начисленная рефереру уровня Level с поинтов,
+// которые заработал приглашенный им по цепочке пользователь
+type ReferralCommission struct {
+	ID            ReferralCommissionID
+	BeneficiaryID UserID
+	EarnerID      UserID
+	Level         int
+	SourceEntryID LedgerEntryID
+	BasePoints    int
+	Rate          int
+	Amount        int
+	CreatedAt     time.Time
+}
+
+// NewReferralCommission рассчитывает комиссию уровня level с записи журнала source.
+// Возвращает false, если для уровня нет ставки или комиссия округляется до нуля.
+func NewReferralCommission(plan CommissionPlan, beneficiaryID UserID, level int, source LedgerEntry) (ReferralCommission, bool, error) {
+	rate := plan.Rate(level)
+	if rate == 0 || !source.EarnsCommission() {
+		return ReferralCommission{}, false, nil
+	}
+
+	amount := source.Amount * rate / commissionRateScale
+	if amount == 0 {
+		return ReferralCommission{}, false, nil
+	}
+
+	commissionID, err := NewReferralCommissionID()
+	if err != nil {
+		return ReferralCommission{}, false, err
+	}
+
+	return ReferralCommission{
+		ID:            commissionID,
+		BeneficiaryID: beneficiaryID,
+		EarnerID:      source.UserID,
+		Level:         level,
+		SourceEntryID: source.ID,
+		BasePoints:    source.Amount,
+		Rate:          rate,
+		Amount:        amount,
+		CreatedAt:     time.Now(),
+	}, true, nil
+}
+
+// LedgerEntry возвращает запись журнала, которой комиссия начисляется рефереру
+func (c ReferralCommission) LedgerEntry() (LedgerEntry, error) {
+	return NewLedgerEntry(c.BeneficiaryID, c.Amount, LedgerSourceCommission, c.ID.String())
+}
+
+// CommissionLevelSummary сводка комиссий пользователя по одному уровню цепочки
+type CommissionLevelSummary struct {
+	Level       int
+	Referrals   int
+	Commissions int
+	Points      int
+}
@@ -1,18 +1,93 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Доменные ошибки
 var (
-	ErrUserNotFound      = errors.New("пользователь не найден")
-	ErrUserExists        = errors.New("пользователь уже существует")
-	ErrInvalidUsername   = errors.New("некорректный username")
-	ErrInvalidEmail      = errors.New("некорректный email")
-	ErrTaskNotFound      = errors.New("задание не найдено")
-	ErrTaskAlreadyExists = errors.New("задание уже выполнено")
-	ErrInvalidTaskType   = errors.New("неизвестный тип задания")
-	ErrReferralExists    = errors.New("реферальный код уже использован")
-	ErrSelfReferral      = errors.New("нельзя использовать свой собственный реферальный код")
-	ErrReferrerNotFound  = errors.New("реферер не найден")
+	ErrUserNotFound       = errors.New("пользователь не найден")
+	ErrUserExists         = errors.New("пользователь уже существует")
+	ErrInvalidUsername    = errors.New("некорректный username")
+	ErrInvalidEmail       = errors.New("некорректный email")
+	ErrTaskNotFound       = errors.New("задание не найдено")
+	ErrTaskLimitReached   = errors.New("задание уже выполнено максимальное число раз")
+	ErrTaskOnCooldown     = errors.New("задание уже выполнено")
+	ErrInvalidTask        = errors.New("некорректное задание")
+	ErrTaskExists         = errors.New("задание с таким ключом уже существует")
+	ErrTaskArchived       = errors.New("задание находится в архиве")
+	ErrInvalidTaskType    = errors.New("неизвестный тип задания")
+	ErrInvalidProof       = errors.New("некорректное подтверждение выполнения задания")
+	ErrSubmissionNotFound = errors.New("заявка на выполнение задания не найдена")
+	ErrSubmissionReviewed = errors.New("заявка уже рассмотрена")
+	ErrReferralExists     = errors.New("реферальный код уже использован")
+	ErrSelfReferral       = errors.New("нельзя использовать свой собственный реферальный код")
+	ErrReferrerNotFound   = errors.New("реферер не найден")
+	ErrInvalidLedgerEntry = errors.New("некорректная запись журнала поинтов")
+	ErrInvalidRole        = errors.New("неизвестная роль")
+	ErrForbidden          = errors.New("недостаточно прав для выполнения операции")
+	ErrInvalidPagination  = errors.New("некорректные параметры пагинации")
+
+	ErrInvalidReferralCode = errors.New("некорректный реферальный код")
+	ErrReferralCodeTaken   = errors.New("реферальный код уже занят")
+	ErrVanityCodeExists    = errors.New("собственный реферальный код уже выбран")
+
+	ErrInvalidCommissionPlan = errors.New("некорректные ставки реферальных комиссий")
+	ErrInvalidVestingPolicy  = errors.New("некорректные условия начисления реферальных бонусов")
+	ErrReferralNotPending    = errors.New("реферальная связь уже завершена")
+	ErrReferralNotOnHold     = errors.New("реферальная связь не ожидает проверки")
+	ErrReferralNotFound      = errors.New("реферальная связь не найдена")
+	ErrInvalidReferralStatus = errors.New("некорректный статус реферальной связи")
+
+	ErrInsufficientBalance = errors.New("недостаточно поинтов на балансе")
+	ErrInvalidReward       = errors.New("некорректный товар магазина наград")
+	ErrRewardNotFound      = errors.New("товар не найден")
+	ErrRewardUnavailable   = errors.New("товар недоступен")
+	ErrRewardOutOfStock    = errors.New("товар закончился")
+	ErrRewardLimitReached  = errors.New("товар уже получен максимальное число раз")
+	ErrOrderNotFound       = errors.New("заказ не найден")
+	ErrOrderFulfilled      = errors.New("заказ уже выдан")
+	ErrInvalidOrderStatus  = errors.New("некорректный статус заказа")
+
+	ErrInvalidPointsGrant  = errors.New("некорректное начисление поинтов")
+	ErrInvalidPointsExpiry = errors.New("некорректный срок действия поинтов")
+
+	ErrInvalidLeaderboardPeriod = errors.New("неизвестный период таблицы лидеров")
+	ErrSeasonNotFound           = errors.New("сезон не найден")
+	ErrInvalidSeason            = errors.New("некорректный сезон")
+	ErrSeasonOverlaps           = errors.New("сезон пересекается с другим сезоном")
+
+	ErrInvalidRefreshToken = errors.New("невалидный refresh токен")
+	ErrRefreshTokenReused  = errors.New("refresh токен уже использован, все сессии отозваны")
+	ErrInvalidLoginCode    = errors.New("неверный или просроченный код входа")
+
+	ErrTooManyLoginCodeRequests = errors.New("слишком много запросов кода входа, попробуйте позже")
+	ErrInvalidLoginCodeThrottle = errors.New("некорректные ограничения на выдачу кодов входа")
+
+	ErrInvalidSignature         = errors.New("невалидная подпись запроса")
+	ErrExternalAccountNotFound  = errors.New("внешний аккаунт не привязан к пользователю")
+	ErrExternalAccountLinked    = errors.New("внешний аккаунт уже привязан")
+	ErrInvalidExternalAccount   = errors.New("некорректный внешний аккаунт")
+	ErrExternalVerificationOnly = errors.New("задание подтверждается только партнерским сервисом")
+
+	ErrInvalidIdempotencyKey    = errors.New("некорректный ключ идемпотентности")
+	ErrIdempotencyKeyReused     = errors.New("ключ идемпотентности уже использован с другим запросом")
+	ErrIdempotencyKeyInProgress = errors.New("запрос с этим ключом идемпотентности еще выполняется")
+	ErrIdempotencyUserCreated   = errors.New("пользователь уже создан запросом с этим ключом идемпотентности")
 )
 
+// InsufficientBalanceError ошибка списания поинтов сверх баланса
+type InsufficientBalanceError struct {
+	Balance  int
+	Required int
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("%s: на балансе %d, требуется %d", ErrInsufficientBalance.Error(), e.Balance, e.Required)
+}
+
+// Is позволяет сравнивать ошибку с ErrInsufficientBalance через errors.Is
+func (e *InsufficientBalanceError) Is(target error) bool {
+	return target == ErrInsufficientBalance
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ExternalProvider партнерский сервис, в котором у пользователя есть аккаунт
+type ExternalProvider string
+
+const (
+	ExternalProviderTelegram ExternalProvider = "telegram"
+	ExternalProviderTwitter  ExternalProvider = "twitter"
+)
+
+// NewExternalProvider создает новый ExternalProvider с валидацией
+func NewExternalProvider(value string) (ExternalProvider, error) {
+	provider := ExternalProvider(value)
+	if !provider.IsValid() {
+		return "", fmt.Errorf("%w: неизвестный сервис %s", ErrInvalidExternalAccount, value)
+	}
+	return provider, nil
+}
+
+// IsValid проверяет валидность сервиса
+func (p ExternalProvider) IsValid() bool {
+	return p == ExternalProviderTelegram || p == ExternalProviderTwitter
+}
+
+// String возвращает строковое представление ExternalProvider
+func (p ExternalProvider) String() string {
+	return string(p)
+}
+
+var externalIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.@-]{1,100}$`)
+
+// ExternalAccount связь аккаунта в партнерском сервисе с пользователем
+type ExternalAccount struct {
+	Provider   ExternalProvider
+	ExternalID string
+	UserID     UserID
+	CreatedAt  time.Time
+}
+
+// NewExternalAccount создает новую связь внешнего аккаунта с пользователем
+func NewExternalAccount(provider ExternalProvider, externalID string, userID UserID) (ExternalAccount, error) {
+	externalID = strings.TrimSpace(externalID)
+	if !externalIDPattern.MatchString(externalID) {
+		return ExternalAccount{}, fmt.Errorf("%w: некорректный идентификатор аккаунта", ErrInvalidExternalAccount)
+	}
+
+	return ExternalAccount{
+		Provider:   provider,
+		ExternalID: externalID,
+		UserID:     userID,
+		CreatedAt:  time.Now(),
+	}, nil
+}
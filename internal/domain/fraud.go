@@ -0,0 +1,21 @@
+package domain
+
+// FraudSignal признак мошенничества, найденный одним правилом проверки.
+// Score - вклад правила в общую оценку связи.
+type FraudSignal struct {
+	Rule   string
+	Score  int
+	Reason string
+}
+
+// FraudAssessment результат проверки реферальной связи всеми правилами
+type FraudAssessment struct {
+	Score   int
+	Signals []FraudSignal
+}
+
+// Add добавляет признак к оценке
+func (a *FraudAssessment) Add(signal FraudSignal) {
+	a.Score += signal.Score
+	a.Signals = append(a.Signals, signal)
+}
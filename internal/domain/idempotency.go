@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IdempotencyRecord представляет сохраненный результат запроса с ключом идемпотентности.
+// Пока запрос выполняется, StatusCode равен нулю.
+type IdempotencyRecord struct {
+	Scope        string
+	Key          string
+	Route        string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// NewIdempotencyRecord создает новую запись о запросе, который начинает выполняться
+func NewIdempotencyRecord(scope, key, route, requestHash string, ttl time.Duration) (IdempotencyRecord, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return IdempotencyRecord{}, ErrInvalidIdempotencyKey
+	}
+	if len(key) > 255 {
+		return IdempotencyRecord{}, fmt.Errorf("%w: максимальная длина 255 символов", ErrInvalidIdempotencyKey)
+	}
+
+	now := time.Now()
+	return IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		Route:       route,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}, nil
+}
+
+// IsCompleted проверяет, сохранен ли уже ответ на запрос
+func (r IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
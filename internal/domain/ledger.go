@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerSource источник начисления или списания поинтов
+type LedgerSource string
+
+const (
+	LedgerSourceTask       LedgerSource = "task"
+	LedgerSourceReferral   LedgerSource = "referral"
+	LedgerSourceAdmin      LedgerSource = "admin"
+	LedgerSourceRedemption LedgerSource = "redemption"
+	LedgerSourceCommission LedgerSource = "commission"
+	LedgerSourceExpiration LedgerSource = "expiration"
+)
+
+// NewLedgerSource создает новый LedgerSource с валидацией
+func NewLedgerSource(value string) (LedgerSource, error) {
+	source := LedgerSource(value)
+	if !source.IsValid() {
+		return "", fmt.Errorf("%w: неизвестный источник %s", ErrInvalidLedgerEntry, value)
+	}
+	return source, nil
+}
+
+// IsValid проверяет валидность источника
+func (s LedgerSource) IsValid() bool {
+	return s == LedgerSourceTask ||
+		s == LedgerSourceReferral ||
+		s == LedgerSourceAdmin ||
+		s == LedgerSourceRedemption ||
+		s == LedgerSourceCommission ||
+		s == LedgerSourceExpiration
+}
+
+// String возвращает строковое представление LedgerSource
+func (s LedgerSource) String() string {
+	return string(s)
+}
+
+// LedgerEntryID представляет идентификатор записи журнала поинтов
+type LedgerEntryID struct {
+	value uuid.UUID
+}
+
+// NewLedgerEntryID создает новый LedgerEntryID
+func NewLedgerEntryID() (LedgerEntryID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return LedgerEntryID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return LedgerEntryID{value: id}, nil
+}
+
+// LedgerEntryIDFromString создает LedgerEntryID из строки
+func LedgerEntryIDFromString(s string) (LedgerEntryID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return LedgerEntryID{}, fmt.Errorf("некорректный формат LedgerEntryID: %w", err)
+	}
+	return LedgerEntryID{value: id}, nil
+}
+
+// String возвращает строковое представление LedgerEntryID
+func (id LedgerEntryID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id LedgerEntryID) Value() uuid.UUID {
+	return id.value
+}
+
+// LedgerEntry представляет запись в журнале поинтов.
+// Положительная сумма означает начисление, отрицательная - списание.
+// Note хранит необязательное пояснение, например причину начисления администратором.
+type LedgerEntry struct {
+	ID          LedgerEntryID
+	UserID      UserID
+	Amount      int
+	Source      LedgerSource
+	ReferenceID string
+	Note        string
+	CreatedAt   time.Time
+}
+
+// NewLedgerEntry создает новую запись журнала поинтов
+func NewLedgerEntry(userID UserID, amount int, source LedgerSource, referenceID string) (LedgerEntry, error) {
+	if amount == 0 {
+		return LedgerEntry{}, fmt.Errorf("%w: сумма не может быть нулевой", ErrInvalidLedgerEntry)
+	}
+	if !source.IsValid() {
+		return LedgerEntry{}, fmt.Errorf("%w: неизвестный источник %s", ErrInvalidLedgerEntry, source)
+	}
+	if referenceID == "" {
+		return LedgerEntry{}, fmt.Errorf("%w: не указан идентификатор основания", ErrInvalidLedgerEntry)
+	}
+
+	entryID, err := NewLedgerEntryID()
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+
+	return LedgerEntry{
+		ID:          entryID,
+		UserID:      userID,
+		Amount:      amount,
+		Source:      source,
+		ReferenceID: referenceID,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// NewPointsGrant создает запись журнала о начислении поинтов администратором.
+// Каждое начисление получает собственное основание, чтобы несколько начислений
+// одному пользователю не конфликтовали между собой. Причина начисления сохраняется в записи.
+func NewPointsGrant(userID UserID, points int, reason string) (LedgerEntry, error) {
+	if points <= 0 {
+		return LedgerEntry{}, fmt.Errorf("%w: число поинтов должно быть положительным", ErrInvalidPointsGrant)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > 255 {
+		return LedgerEntry{}, fmt.Errorf("%w: причина начисления должна быть от 1 до 255 байт", ErrInvalidPointsGrant)
+	}
+
+	grantID, err := uuid.NewRandom()
+	if err != nil {
+		return LedgerEntry{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+
+	entry, err := NewLedgerEntry(userID, points, LedgerSourceAdmin, "grant:"+grantID.String())
+	if err != nil {
+		return LedgerEntry{}, err
+	}
+	entry.Note = reason
+	return entry, nil
+}
+
+// EarnsCommission проверяет, начисляются ли с записи журнала комиссии вышестоящим реферерам.
+// Комиссии начисляются только с поинтов, заработанных за задания, чтобы бонусы
+// и сами комиссии не порождали новых начислений.
+func (e LedgerEntry) EarnsCommission() bool {
+	return e.Source == LedgerSourceTask && e.Amount > 0
+}
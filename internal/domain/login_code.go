@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// loginCodeDigits количество цифр в одноразовом коде входа
+const loginCodeDigits = 6
+
+// LoginCodeID представляет идентификатор одноразового кода входа
+type LoginCodeID struct {
+	value uuid.UUID
+}
+
+// NewLoginCodeID создает новый LoginCodeID
+func NewLoginCodeID() (LoginCodeID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return LoginCodeID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return LoginCodeID{value: id}, nil
+}
+
+// LoginCodeIDFromString создает LoginCodeID из строки
+func LoginCodeIDFromString(s string) (LoginCodeID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return LoginCodeID{}, fmt.Errorf("некорректный формат LoginCodeID: %w", err)
+	}
+	return LoginCodeID{value: id}, nil
+}
+
+// String возвращает строковое представление LoginCodeID
+func (id LoginCodeID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id LoginCodeID) Value() uuid.UUID {
+	return id.value
+}
+
+// LoginCode представляет одноразовый код входа, отправленный на email.
+// В базе хранится только хеш кода.
+type LoginCode struct {
+	ID          LoginCodeID
+	UserID      UserID
+	CodeHash    string
+	Attempts    int
+	MaxAttempts int
+	ExpiresAt   time.Time
+	ConsumedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+// NewLoginCode создает одноразовый код входа.
+// Возвращает сам код и его открытое значение, которое отправляется пользователю.
+func NewLoginCode(userID UserID, ttl time.Duration, maxAttempts int) (LoginCode, string, error) {
+	codeID, err := NewLoginCodeID()
+	if err != nil {
+		return LoginCode{}, "", err
+	}
+
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(loginCodeDigits), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return LoginCode{}, "", fmt.Errorf("ошибка генерации кода входа: %w", err)
+	}
+	plain := fmt.Sprintf("%0*d", loginCodeDigits, n.Int64())
+
+	now := time.Now()
+	return LoginCode{
+		ID:          codeID,
+		UserID:      userID,
+		CodeHash:    hashLoginCode(codeID, plain),
+		MaxAttempts: maxAttempts,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	}, plain, nil
+}
+
+// IsUsable проверяет, можно ли еще вводить этот код
+func (c LoginCode) IsUsable(now time.Time) bool {
+	return c.ConsumedAt == nil &&
+		now.Before(c.ExpiresAt) &&
+		c.Attempts < c.MaxAttempts
+}
+
+// Matches проверяет, совпадает ли введенный код с сохраненным
+func (c LoginCode) Matches(plain string) bool {
+	expected := hashLoginCode(c.ID, plain)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(c.CodeHash)) == 1
+}
+
+// hashLoginCode возвращает хеш кода, привязанный к ID кода
+func hashLoginCode(id LoginCodeID, plain string) string {
+	sum := sha256.Sum256([]byte(id.String() + ":" + plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoginCodeThrottle ограничивает выдачу кодов входа: не больше PerEmail запросов на один email
+// и не больше PerIP запросов с одного IP адреса за Window
+type LoginCodeThrottle struct {
+	PerEmail int
+	PerIP    int
+	Window   time.Duration
+}
+
+// NewLoginCodeThrottle создает ограничения на выдачу кодов входа с валидацией
+func NewLoginCodeThrottle(perEmail, perIP int, window time.Duration) (LoginCodeThrottle, error) {
+	if perEmail <= 0 || perIP <= 0 {
+		return LoginCodeThrottle{}, fmt.Errorf("%w: число запросов должно быть положительным", ErrInvalidLoginCodeThrottle)
+	}
+	if window <= 0 {
+		return LoginCodeThrottle{}, fmt.Errorf("%w: интервал должен быть положительным", ErrInvalidLoginCodeThrottle)
+	}
+	return LoginCodeThrottle{PerEmail: perEmail, PerIP: perIP, Window: window}, nil
+}
+
+// Allows проверяет, можно ли выдать код, если за интервал с учетом текущего запроса
+// на email пришло emailRequests запросов, а с IP адреса - ipRequests
+func (t LoginCodeThrottle) Allows(emailRequests, ipRequests int) bool {
+	return emailRequests <= t.PerEmail && ipRequests <= t.PerIP
+}
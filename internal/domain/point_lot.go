@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// PointsExpiryPolicy срок действия начисленных поинтов.
+// Поинты сгорают через Months календарных месяцев после начисления.
+type PointsExpiryPolicy struct {
+	Months int
+}
+
+// NewPointsExpiryPolicy создает политику срока действия поинтов с валидацией
+func NewPointsExpiryPolicy(months int) (PointsExpiryPolicy, error) {
+	if months <= 0 {
+		return PointsExpiryPolicy{}, fmt.Errorf("%w: срок должен быть не меньше одного месяца", ErrInvalidPointsExpiry)
+	}
+	return PointsExpiryPolicy{Months: months}, nil
+}
+
+// ExpiresAt возвращает момент, когда сгорают поинты, начисленные в earnedAt
+func (p PointsExpiryPolicy) ExpiresAt(earnedAt time.Time) time.Time {
+	return earnedAt.AddDate(0, p.Months, 0)
+}
+
+// PointLot партия поинтов, полученная одним начислением.
+// Списания расходуют партии в порядке сгорания, начиная с самых старых,
+// Remaining - сколько поинтов партии еще не потрачено и не сгорело.
+type PointLot struct {
+	EntryID   LedgerEntryID
+	UserID    UserID
+	Amount    int
+	Remaining int
+	EarnedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// NewPointLot создает партию поинтов для записи журнала о начислении
+func NewPointLot(entry LedgerEntry, policy PointsExpiryPolicy) (PointLot, error) {
+	if entry.Amount <= 0 {
+		return PointLot{}, fmt.Errorf("%w: партия создается только для начисления", ErrInvalidLedgerEntry)
+	}
+
+	return PointLot{
+		EntryID:   entry.ID,
+		UserID:    entry.UserID,
+		Amount:    entry.Amount,
+		Remaining: entry.Amount,
+		EarnedAt:  entry.CreatedAt,
+		ExpiresAt: policy.ExpiresAt(entry.CreatedAt),
+	}, nil
+}
+
+// ExpirationEntry создает запись журнала о сгорании непотраченного остатка партии.
+// Основанием записи служит начисление, которым получена партия.
+func (l PointLot) ExpirationEntry() (LedgerEntry, error) {
+	return NewLedgerEntry(l.UserID, -l.Remaining, LedgerSourceExpiration, l.EntryID.String())
+}
@@ -39,17 +39,41 @@ func (id ReferralID) Value() uuid.UUID {
 	return id.value
 }
 
-// Referral представляет реферальную связь между пользователями
+// ReferralStatus состояние реферальной связи
+type ReferralStatus string
+
+const (
+	// ReferralPending связь создана, бонусы ждут выполнения условий
+	ReferralPending ReferralStatus = "pending"
+	// ReferralOnHold связь похожа на мошенничество и ждет решения модератора
+	ReferralOnHold ReferralStatus = "on_hold"
+	// ReferralVested условия выполнены, бонусы начислены обоим пользователям
+	ReferralVested ReferralStatus = "vested"
+	// ReferralCancelled условия не выполнены в срок, бонусы не начисляются
+	ReferralCancelled ReferralStatus = "cancelled"
+)
+
+// Referral представляет реферальную связь между пользователями.
+// BonusPoints получает приглашенный пользователь, ReferrerBonusPoints - пригласивший,
+// оба бонуса начисляются только после перехода связи в состояние vested.
 type Referral struct {
-	ID             ReferralID
-	ReferrerID     UserID
-	ReferredUserID UserID
-	BonusPoints    int
-	CreatedAt      time.Time
+	ID                  ReferralID
+	ReferrerID          UserID
+	ReferredUserID      UserID
+	BonusPoints         int
+	ReferrerBonusPoints int
+	Status              ReferralStatus
+	FraudScore          int
+	FraudSignals        []FraudSignal
+	CreatedAt           time.Time
+	VestedAt            *time.Time
+	CancelledAt         *time.Time
+	ReviewedBy          *UserID
+	ReviewedAt          *time.Time
 }
 
-// NewReferral создает новую реферальную связь
-func NewReferral(referrerID, referredUserID UserID) (Referral, error) {
+// NewReferral создает новую реферальную связь в состоянии pending с бонусами из политики
+func NewReferral(referrerID, referredUserID UserID, policy ReferralVestingPolicy) (Referral, error) {
 	if referrerID.String() == referredUserID.String() {
 		return Referral{}, ErrSelfReferral
 	}
@@ -60,10 +84,81 @@ func NewReferral(referrerID, referredUserID UserID) (Referral, error) {
 	}
 
 	return Referral{
-		ID:             referralID,
-		ReferrerID:     referrerID,
-		ReferredUserID: referredUserID,
-		BonusPoints:    100,
-		CreatedAt:      time.Now(),
+		ID:                  referralID,
+		ReferrerID:          referrerID,
+		ReferredUserID:      referredUserID,
+		BonusPoints:         policy.BonusPoints,
+		ReferrerBonusPoints: policy.ReferrerBonusPoints,
+		Status:              ReferralPending,
+		CreatedAt:           time.Now(),
 	}, nil
 }
+
+// Vest переводит связь в состояние vested
+func (r *Referral) Vest(now time.Time) error {
+	if r.Status != ReferralPending {
+		return ErrReferralNotPending
+	}
+	r.Status = ReferralVested
+	r.VestedAt = &now
+	return nil
+}
+
+// Cancel переводит связь в состояние cancelled
+func (r *Referral) Cancel(now time.Time) error {
+	if r.Status != ReferralPending {
+		return ErrReferralNotPending
+	}
+	r.Status = ReferralCancelled
+	r.CancelledAt = &now
+	return nil
+}
+
+// RecordFraudAssessment сохраняет в связи результат проверки на мошенничество
+func (r *Referral) RecordFraudAssessment(assessment FraudAssessment) {
+	r.FraudScore = assessment.Score
+	r.FraudSignals = assessment.Signals
+}
+
+// Hold отправляет связь на проверку модератору
+func (r *Referral) Hold() error {
+	if r.Status != ReferralPending {
+		return ErrReferralNotPending
+	}
+	r.Status = ReferralOnHold
+	return nil
+}
+
+// Release возвращает задержанную связь в состояние pending по решению модератора,
+// после чего бонусы начисляются на общих условиях
+func (r *Referral) Release(moderatorID UserID, now time.Time) error {
+	if r.Status != ReferralOnHold {
+		return ErrReferralNotOnHold
+	}
+	r.Status = ReferralPending
+	r.ReviewedBy = &moderatorID
+	r.ReviewedAt = &now
+	return nil
+}
+
+// Reject отменяет задержанную связь по решению модератора
+func (r *Referral) Reject(moderatorID UserID, now time.Time) error {
+	if r.Status != ReferralOnHold {
+		return ErrReferralNotOnHold
+	}
+	r.Status = ReferralCancelled
+	r.CancelledAt = &now
+	r.ReviewedBy = &moderatorID
+	r.ReviewedAt = &now
+	return nil
+}
+
+// NewReferralStatus создает ReferralStatus с валидацией
+func NewReferralStatus(value string) (ReferralStatus, error) {
+	status := ReferralStatus(value)
+	switch status {
+	case ReferralPending, ReferralOnHold, ReferralVested, ReferralCancelled:
+		return status, nil
+	}
+	return "", fmt.Errorf("%w: неизвестный статус %s", ErrInvalidReferralStatus, value)
+}
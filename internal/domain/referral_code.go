@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// crockfordAlphabet алфавит Crockford base32 без букв I, L, O и U,
+// которые легко спутать с цифрами или друг с другом
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Ограничения реферальных кодов
+const (
+	generatedReferralCodeLength = 8
+	minVanityReferralCodeLength = 4
+	maxVanityReferralCodeLength = 16
+)
+
+// reservedReferralCodes слова, которые нельзя выбрать в качестве собственного кода,
+// чтобы код не выдавал себя за официальный
+var reservedReferralCodes = map[string]struct{}{
+	"ADMIN":         {},
+	"ADMINISTRATOR": {},
+	"MODERATOR":     {},
+	"SUPPORT":       {},
+	"HELP":          {},
+	"SYSTEM":        {},
+	"ROOT":          {},
+	"OFFICIAL":      {},
+	"STAFF":         {},
+	"TEAM":          {},
+	"REWARDS":       {},
+	"REFERRAL":      {},
+	"INVITE":        {},
+	"BONUS":         {},
+	"FREE":          {},
+	"PROMO":         {},
+	"NULL":          {},
+	"UNDEFINED":     {},
+	"TEST":          {},
+}
+
+// ReferralCodeKind способ получения реферального кода
+type ReferralCodeKind string
+
+const (
+	// ReferralCodeGenerated код, созданный при регистрации
+	ReferralCodeGenerated ReferralCodeKind = "generated"
+	// ReferralCodeVanity собственный код, выбранный пользователем
+	ReferralCodeVanity ReferralCodeKind = "vanity"
+)
+
+// ReferralCode реферальный код пользователя. У пользователя есть один сгенерированный
+// код и может быть один собственный. Коды хранятся в верхнем регистре, поэтому
+// сравниваются без учета регистра.
+type ReferralCode struct {
+	Code      string
+	UserID    UserID
+	Kind      ReferralCodeKind
+	CreatedAt time.Time
+}
+
+// NewGeneratedReferralCode создает случайный код из 8 символов Crockford base32
+func NewGeneratedReferralCode(userID UserID) (ReferralCode, error) {
+	// 5 байт - это ровно 40 бит, то есть 8 символов по 5 бит
+	var random [5]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return ReferralCode{}, fmt.Errorf("ошибка генерации реферального кода: %w", err)
+	}
+
+	bits := uint64(0)
+	for _, b := range random {
+		bits = bits<<8 | uint64(b)
+	}
+
+	code := make([]byte, generatedReferralCodeLength)
+	for i := generatedReferralCodeLength - 1; i >= 0; i-- {
+		code[i] = crockfordAlphabet[bits&31]
+		bits >>= 5
+	}
+
+	return ReferralCode{
+		Code:      string(code),
+		UserID:    userID,
+		Kind:      ReferralCodeGenerated,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NewVanityReferralCode создает собственный код пользователя с валидацией
+func NewVanityReferralCode(userID UserID, value string) (ReferralCode, error) {
+	code := NormalizeReferralCode(value)
+	if len(code) < minVanityReferralCodeLength || len(code) > maxVanityReferralCodeLength {
+		return ReferralCode{}, fmt.Errorf("%w: длина должна быть от %d до %d символов",
+			ErrInvalidReferralCode, minVanityReferralCodeLength, maxVanityReferralCodeLength)
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return ReferralCode{}, fmt.Errorf("%w: допустимы только латинские буквы и цифры", ErrInvalidReferralCode)
+		}
+	}
+	if _, reserved := reservedReferralCodes[code]; reserved {
+		return ReferralCode{}, fmt.Errorf("%w: код зарезервирован", ErrInvalidReferralCode)
+	}
+
+	return ReferralCode{
+		Code:      code,
+		UserID:    userID,
+		Kind:      ReferralCodeVanity,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NormalizeReferralCode приводит введенный код к виду, в котором он хранится:
+// без пробелов и дефисов, в верхнем регистре
+func NormalizeReferralCode(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	return strings.NewReplacer("-", "", " ", "").Replace(value)
+}
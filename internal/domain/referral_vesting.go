@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// VestingDecision решение по реферальной связи в состоянии pending
+type VestingDecision int
+
+const (
+	// VestingWait условия еще не выполнены, но срок не истек
+	VestingWait VestingDecision = iota
+	// VestingVest условия выполнены, бонусы нужно начислить
+	VestingVest
+	// VestingCancel срок истек, а условия так и не выполнены
+	VestingCancel
+)
+
+// ReferralVestingPolicy условия, при которых реферальные бонусы начисляются.
+// Приглашенный пользователь должен выполнить не менее MinCompletedTasks заданий,
+// а его аккаунт должен существовать не меньше MinAccountAge. Если условия не выполнены
+// за VestingWindow с момента создания связи, связь отменяется.
+type ReferralVestingPolicy struct {
+	BonusPoints         int
+	ReferrerBonusPoints int
+	MinCompletedTasks   int
+	MinAccountAge       time.Duration
+	VestingWindow       time.Duration
+}
+
+// NewReferralVestingPolicy создает политику начисления реферальных бонусов с валидацией
+func NewReferralVestingPolicy(bonusPoints, referrerBonusPoints, minCompletedTasks int, minAccountAge, vestingWindow time.Duration) (ReferralVestingPolicy, error) {
+	if bonusPoints < 0 || referrerBonusPoints < 0 || minCompletedTasks < 0 || minAccountAge < 0 {
+		return ReferralVestingPolicy{}, fmt.Errorf("%w: значения не могут быть отрицательными", ErrInvalidVestingPolicy)
+	}
+	if vestingWindow <= minAccountAge {
+		return ReferralVestingPolicy{}, fmt.Errorf("%w: срок выполнения условий должен быть больше минимального возраста аккаунта", ErrInvalidVestingPolicy)
+	}
+
+	return ReferralVestingPolicy{
+		BonusPoints:         bonusPoints,
+		ReferrerBonusPoints: referrerBonusPoints,
+		MinCompletedTasks:   minCompletedTasks,
+		MinAccountAge:       minAccountAge,
+		VestingWindow:       vestingWindow,
+	}, nil
+}
+
+// Decide принимает решение по связи referral на момент now.
+// referredCreatedAt - время регистрации приглашенного пользователя,
+// completedTasks - число выполненных им заданий.
+func (p ReferralVestingPolicy) Decide(referral Referral, referredCreatedAt time.Time, completedTasks int, now time.Time) VestingDecision {
+	if referral.Status != ReferralPending {
+		return VestingWait
+	}
+
+	if completedTasks >= p.MinCompletedTasks && now.Sub(referredCreatedAt) >= p.MinAccountAge {
+		return VestingVest
+	}
+	if now.Sub(referral.CreatedAt) >= p.VestingWindow {
+		return VestingCancel
+	}
+	return VestingWait
+}
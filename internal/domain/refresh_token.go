@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenID представляет идентификатор refresh токена
+type RefreshTokenID struct {
+	value uuid.UUID
+}
+
+// NewRefreshTokenID создает новый RefreshTokenID
+func NewRefreshTokenID() (RefreshTokenID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return RefreshTokenID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return RefreshTokenID{value: id}, nil
+}
+
+// RefreshTokenIDFromString создает RefreshTokenID из строки
+func RefreshTokenIDFromString(s string) (RefreshTokenID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return RefreshTokenID{}, fmt.Errorf("некорректный формат RefreshTokenID: %w", err)
+	}
+	return RefreshTokenID{value: id}, nil
+}
+
+// String возвращает строковое представление RefreshTokenID
+func (id RefreshTokenID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id RefreshTokenID) Value() uuid.UUID {
+	return id.value
+}
+
+// RefreshToken представляет refresh токен. В базе хранится только хеш токена.
+// Токены, полученные друг из друга при ротации, образуют семейство с общим FamilyID,
+// которое совпадает с ID первого токена.
+type RefreshToken struct {
+	ID        RefreshTokenID
+	UserID    UserID
+	FamilyID  RefreshTokenID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// NewRefreshToken создает refresh токен, начинающий новое семейство.
+// Возвращает сам токен и его открытое значение, которое передается клиенту.
+func NewRefreshToken(userID UserID, ttl time.Duration) (RefreshToken, string, error) {
+	tokenID, err := NewRefreshTokenID()
+	if err != nil {
+		return RefreshToken{}, "", err
+	}
+	return newRefreshToken(tokenID, userID, tokenID, ttl)
+}
+
+// Rotate создает следующий refresh токен того же семейства
+func (t RefreshToken) Rotate(ttl time.Duration) (RefreshToken, string, error) {
+	tokenID, err := NewRefreshTokenID()
+	if err != nil {
+		return RefreshToken{}, "", err
+	}
+	return newRefreshToken(tokenID, t.UserID, t.FamilyID, ttl)
+}
+
+func newRefreshToken(tokenID RefreshTokenID, userID UserID, familyID RefreshTokenID, ttl time.Duration) (RefreshToken, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return RefreshToken{}, "", fmt.Errorf("ошибка генерации refresh токена: %w", err)
+	}
+	plain := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	return RefreshToken{
+		ID:        tokenID,
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashRefreshToken(plain),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, plain, nil
+}
+
+// HashRefreshToken возвращает хеш открытого значения refresh токена
+func HashRefreshToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsUsed проверяет, был ли токен уже обменян на новый
+func (t RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsRevoked проверяет, был ли токен отозван при выходе или вместе с семейством
+func (t RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsExpired проверяет, истек ли срок действия токена
+func (t RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
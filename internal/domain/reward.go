@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RewardID представляет идентификатор товара в магазине наград
+type RewardID struct {
+	value uuid.UUID
+}
+
+// NewRewardID создает новый RewardID
+func NewRewardID() (RewardID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return RewardID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return RewardID{value: id}, nil
+}
+
+// RewardIDFromString создает RewardID из строки
+func RewardIDFromString(s string) (RewardID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return RewardID{}, ErrRewardNotFound
+	}
+	return RewardID{value: id}, nil
+}
+
+// String возвращает строковое представление RewardID
+func (id RewardID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id RewardID) Value() uuid.UUID {
+	return id.value
+}
+
+// Reward товар магазина наград, который можно получить за поинты.
+// Stock - остаток на складе, nil означает неограниченное количество.
+// PerUserLimit - сколько раз один пользователь может получить товар, 0 - без ограничений.
+// AvailableFrom и AvailableUntil задают необязательный интервал [from, until), когда товар доступен.
+type Reward struct {
+	ID             RewardID
+	Title          string
+	Description    string
+	Cost           int
+	Stock          *int
+	PerUserLimit   int
+	AvailableFrom  *time.Time
+	AvailableUntil *time.Time
+	Active         bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewReward создает новый активный товар магазина наград
+func NewReward(title, description string, cost int, stock *int, perUserLimit int, availableFrom, availableUntil *time.Time) (Reward, error) {
+	rewardID, err := NewRewardID()
+	if err != nil {
+		return Reward{}, err
+	}
+
+	now := time.Now()
+	reward := Reward{
+		ID:        rewardID,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := reward.Update(title, description, cost, stock, perUserLimit, availableFrom, availableUntil); err != nil {
+		return Reward{}, err
+	}
+	return reward, nil
+}
+
+// Update изменяет товар с валидацией. Уже оформленные заказы не пересчитываются.
+func (r *Reward) Update(title, description string, cost int, stock *int, perUserLimit int, availableFrom, availableUntil *time.Time) error {
+	title = strings.TrimSpace(title)
+	if title == "" || len(title) > 100 {
+		return fmt.Errorf("%w: название должно быть от 1 до 100 символов", ErrInvalidReward)
+	}
+	if cost <= 0 {
+		return fmt.Errorf("%w: стоимость должна быть положительной", ErrInvalidReward)
+	}
+	if stock != nil && *stock < 0 {
+		return fmt.Errorf("%w: остаток не может быть отрицательным", ErrInvalidReward)
+	}
+	if perUserLimit < 0 {
+		return fmt.Errorf("%w: лимит на пользователя не может быть отрицательным", ErrInvalidReward)
+	}
+	if availableFrom != nil && availableUntil != nil && !availableUntil.After(*availableFrom) {
+		return fmt.Errorf("%w: товар должен быть доступен до момента позже начала продаж", ErrInvalidReward)
+	}
+
+	r.Title = title
+	r.Description = strings.TrimSpace(description)
+	r.Cost = cost
+	r.Stock = stock
+	r.PerUserLimit = perUserLimit
+	r.AvailableFrom = availableFrom
+	r.AvailableUntil = availableUntil
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// CheckAvailable проверяет, можно ли получить товар в момент now.
+// Остаток на складе проверяется при списании, чтобы не продать больше, чем есть.
+func (r Reward) CheckAvailable(now time.Time) error {
+	if !r.Active {
+		return fmt.Errorf("%w: товар снят с продажи", ErrRewardUnavailable)
+	}
+	if r.AvailableFrom != nil && now.Before(*r.AvailableFrom) {
+		return fmt.Errorf("%w: товар доступен с %s", ErrRewardUnavailable, r.AvailableFrom.UTC().Format(time.RFC3339))
+	}
+	if r.AvailableUntil != nil && !now.Before(*r.AvailableUntil) {
+		return fmt.Errorf("%w: срок продажи товара закончился", ErrRewardUnavailable)
+	}
+	if r.Stock != nil && *r.Stock == 0 {
+		return ErrRewardOutOfStock
+	}
+	return nil
+}
+
+// RewardOrderID представляет идентификатор заказа в магазине наград
+type RewardOrderID struct {
+	value uuid.UUID
+}
+
+// NewRewardOrderID создает новый RewardOrderID
+func NewRewardOrderID() (RewardOrderID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return RewardOrderID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return RewardOrderID{value: id}, nil
+}
+
+// RewardOrderIDFromString создает RewardOrderID из строки
+func RewardOrderIDFromString(s string) (RewardOrderID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return RewardOrderID{}, ErrOrderNotFound
+	}
+	return RewardOrderID{value: id}, nil
+}
+
+// String возвращает строковое представление RewardOrderID
+func (id RewardOrderID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id RewardOrderID) Value() uuid.UUID {
+	return id.value
+}
+
+// RewardOrderStatus состояние заказа
+type RewardOrderStatus string
+
+const (
+	// RewardOrderPending поинты списаны, товар ожидает выдачи
+	RewardOrderPending RewardOrderStatus = "pending"
+	// RewardOrderFulfilled товар выдан пользователю
+	RewardOrderFulfilled RewardOrderStatus = "fulfilled"
+)
+
+// NewRewardOrderStatus создает RewardOrderStatus с валидацией
+func NewRewardOrderStatus(value string) (RewardOrderStatus, error) {
+	status := RewardOrderStatus(value)
+	switch status {
+	case RewardOrderPending, RewardOrderFulfilled:
+		return status, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidOrderStatus, value)
+}
+
+// RewardOrder заказ товара за поинты. Title и Cost сохраняются на момент заказа,
+// чтобы история заказов не менялась при изменении товара.
+type RewardOrder struct {
+	ID            RewardOrderID
+	UserID        UserID
+	RewardID      RewardID
+	Title         string
+	Cost          int
+	LedgerEntryID LedgerEntryID
+	Status        RewardOrderStatus
+	CreatedAt     time.Time
+	FulfilledAt   *time.Time
+	FulfilledBy   *UserID
+}
+
+// NewRewardOrder создает заказ товара в состоянии pending
+func NewRewardOrder(userID UserID, reward Reward) (RewardOrder, error) {
+	orderID, err := NewRewardOrderID()
+	if err != nil {
+		return RewardOrder{}, err
+	}
+
+	return RewardOrder{
+		ID:        orderID,
+		UserID:    userID,
+		RewardID:  reward.ID,
+		Title:     reward.Title,
+		Cost:      reward.Cost,
+		Status:    RewardOrderPending,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// LedgerEntry создает запись журнала о списании поинтов за заказ
+func (o RewardOrder) LedgerEntry() (LedgerEntry, error) {
+	return NewLedgerEntry(o.UserID, -o.Cost, LedgerSourceRedemption, o.ID.String())
+}
+
+// Fulfill отмечает заказ выданным
+func (o *RewardOrder) Fulfill(adminID UserID, now time.Time) error {
+	if o.Status != RewardOrderPending {
+		return ErrOrderFulfilled
+	}
+	o.Status = RewardOrderFulfilled
+	o.FulfilledAt = &now
+	o.FulfilledBy = &adminID
+	return nil
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "fmt"
+
+// Role роль пользователя, определяющая его права
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// NewRole создает новую Role с валидацией
+func NewRole(value string) (Role, error) {
+	role := Role(value)
+	if !role.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidRole, value)
+	}
+	return role, nil
+}
+
+// IsValid проверяет валидность роли
+func (r Role) IsValid() bool {
+	return r == RoleUser ||
+		r == RoleModerator ||
+		r == RoleAdmin
+}
+
+// String возвращает строковое представление Role
+func (r Role) String() string {
+	return string(r)
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeasonID представляет идентификатор сезона
+type SeasonID struct {
+	value uuid.UUID
+}
+
+// NewSeasonID создает новый SeasonID
+func NewSeasonID() (SeasonID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return SeasonID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return SeasonID{value: id}, nil
+}
+
+// SeasonIDFromString создает SeasonID из строки
+func SeasonIDFromString(s string) (SeasonID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return SeasonID{}, ErrSeasonNotFound
+	}
+	return SeasonID{value: id}, nil
+}
+
+// String возвращает строковое представление SeasonID
+func (id SeasonID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id SeasonID) Value() uuid.UUID {
+	return id.value
+}
+
+// SeasonStatus состояние сезона относительно текущего времени
+type SeasonStatus string
+
+const (
+	SeasonUpcoming  SeasonStatus = "upcoming"
+	SeasonActive    SeasonStatus = "active"
+	SeasonEnded     SeasonStatus = "ended"
+	SeasonFinalized SeasonStatus = "finalized"
+)
+
+// Season соревнование с ограниченным сроком. Очки сезона - это поинты, заработанные
+// с StartsAt до EndsAt, они не зависят от баланса, который пользователь может потратить.
+type Season struct {
+	ID          SeasonID
+	Name        string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	FinalizedAt *time.Time
+	CreatedAt   time.Time
+}
+
+// NewSeason создает новый сезон
+func NewSeason(name string, startsAt, endsAt time.Time) (Season, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return Season{}, fmt.Errorf("%w: название должно быть от 1 до 100 символов", ErrInvalidSeason)
+	}
+	if !endsAt.After(startsAt) {
+		return Season{}, fmt.Errorf("%w: сезон должен заканчиваться позже, чем начинается", ErrInvalidSeason)
+	}
+
+	seasonID, err := NewSeasonID()
+	if err != nil {
+		return Season{}, err
+	}
+
+	return Season{
+		ID:        seasonID,
+		Name:      name,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Status возвращает состояние сезона на момент now
+func (s Season) Status(now time.Time) SeasonStatus {
+	switch {
+	case s.FinalizedAt != nil:
+		return SeasonFinalized
+	case now.Before(s.StartsAt):
+		return SeasonUpcoming
+	case now.Before(s.EndsAt):
+		return SeasonActive
+	default:
+		return SeasonEnded
+	}
+}
+
+// SeasonStanding итоговое место пользователя в завершенном сезоне
+type SeasonStanding struct {
+	SeasonID SeasonID
+	Rank     int
+	UserID   UserID
+	Username string
+	Score    int
+}
@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProofType тип подтверждения, которое требуется для выполнения задания
+type ProofType string
+
+const (
+	ProofTypeNone       ProofType = "none"
+	ProofTypeURL        ProofType = "url"
+	ProofTypeText       ProofType = "text"
+	ProofTypeScreenshot ProofType = "screenshot"
+	// ProofTypeExternal задание подтверждается только callback от партнерского сервиса
+	ProofTypeExternal ProofType = "external"
+)
+
+// NewProofType создает новый ProofType с валидацией
+func NewProofType(value string) (ProofType, error) {
+	proofType := ProofType(value)
+	if !proofType.IsValid() {
+		return "", fmt.Errorf("%w: неизвестный тип подтверждения %s", ErrInvalidTask, value)
+	}
+	return proofType, nil
+}
+
+// IsValid проверяет валидность типа подтверждения
+func (t ProofType) IsValid() bool {
+	return t == ProofTypeNone ||
+		t == ProofTypeURL ||
+		t == ProofTypeText ||
+		t == ProofTypeScreenshot ||
+		t == ProofTypeExternal
+}
+
+// RequiresReview проверяет, нужна ли проверка модератором
+func (t ProofType) RequiresReview() bool {
+	return t == ProofTypeURL || t == ProofTypeText || t == ProofTypeScreenshot
+}
+
+// String возвращает строковое представление ProofType
+func (t ProofType) String() string {
+	return string(t)
+}
+
+// SubmissionStatus статус заявки на выполнение задания
+type SubmissionStatus string
+
+const (
+	SubmissionPending  SubmissionStatus = "pending"
+	SubmissionApproved SubmissionStatus = "approved"
+	SubmissionRejected SubmissionStatus = "rejected"
+)
+
+// NewSubmissionStatus создает новый SubmissionStatus с валидацией
+func NewSubmissionStatus(value string) (SubmissionStatus, error) {
+	status := SubmissionStatus(value)
+	if status != SubmissionPending && status != SubmissionApproved && status != SubmissionRejected {
+		return "", fmt.Errorf("%w: неизвестный статус заявки %s", ErrInvalidProof, value)
+	}
+	return status, nil
+}
+
+// String возвращает строковое представление SubmissionStatus
+func (s SubmissionStatus) String() string {
+	return string(s)
+}
+
+// SubmissionID представляет идентификатор заявки на выполнение задания
+type SubmissionID struct {
+	value uuid.UUID
+}
+
+// NewSubmissionID создает новый SubmissionID
+func NewSubmissionID() (SubmissionID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return SubmissionID{}, fmt.Errorf("ошибка генерации ID: %w", err)
+	}
+	return SubmissionID{value: id}, nil
+}
+
+// SubmissionIDFromString создает SubmissionID из строки
+func SubmissionIDFromString(s string) (SubmissionID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return SubmissionID{}, ErrSubmissionNotFound
+	}
+	return SubmissionID{value: id}, nil
+}
+
+// String возвращает строковое представление SubmissionID
+func (id SubmissionID) String() string {
+	return id.value.String()
+}
+
+// Value возвращает UUID
+func (id SubmissionID) Value() uuid.UUID {
+	return id.value
+}
+
+// TaskSubmission заявка на выполнение задания, требующего подтверждения.
+// Поинты начисляются только после одобрения модератором.
+type TaskSubmission struct {
+	ID              SubmissionID
+	UserID          UserID
+	TaskType        TaskType
+	ProofType       ProofType
+	Proof           string
+	Points          int
+	Status          SubmissionStatus
+	RejectionReason string
+	ReviewedBy      *UserID
+	ReviewedAt      *time.Time
+	UserTaskID      *TaskID
+	CreatedAt       time.Time
+}
+
+// NewTaskSubmission создает заявку на выполнение задания с подтверждением proof.
+// Для скриншота proof содержит ключ загруженного файла в хранилище.
+func NewTaskSubmission(userID UserID, task Task, proof string) (TaskSubmission, error) {
+	if !task.ProofType.RequiresReview() {
+		return TaskSubmission{}, fmt.Errorf("%w: задание не требует подтверждения", ErrInvalidProof)
+	}
+
+	proof, err := validateProof(task.ProofType, proof)
+	if err != nil {
+		return TaskSubmission{}, err
+	}
+
+	submissionID, err := NewSubmissionID()
+	if err != nil {
+		return TaskSubmission{}, err
+	}
+
+	return TaskSubmission{
+		ID:        submissionID,
+		UserID:    userID,
+		TaskType:  task.Key,
+		ProofType: task.ProofType,
+		Proof:     proof,
+		Points:    task.Points,
+		Status:    SubmissionPending,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Approve одобряет заявку
+func (s *TaskSubmission) Approve(moderatorID UserID, now time.Time) error {
+	if s.Status != SubmissionPending {
+		return ErrSubmissionReviewed
+	}
+	s.Status = SubmissionApproved
+	s.ReviewedBy = &moderatorID
+	s.ReviewedAt = &now
+	return nil
+}
+
+// Reject отклоняет заявку с указанием причины, которую увидит пользователь
+func (s *TaskSubmission) Reject(moderatorID UserID, reason string, now time.Time) error {
+	if s.Status != SubmissionPending {
+		return ErrSubmissionReviewed
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > 1000 {
+		return fmt.Errorf("%w: причина отклонения должна быть от 1 до 1000 символов", ErrInvalidProof)
+	}
+
+	s.Status = SubmissionRejected
+	s.RejectionReason = reason
+	s.ReviewedBy = &moderatorID
+	s.ReviewedAt = &now
+	return nil
+}
+
+// CompletedTask создает выполненное задание по одобренной заявке.
+// Начисляется количество поинтов, действовавшее на момент отправки заявки.
+func (s TaskSubmission) CompletedTask() (UserTask, error) {
+	if s.Status != SubmissionApproved {
+		return UserTask{}, fmt.Errorf("%w: заявка не одобрена", ErrInvalidProof)
+	}
+
+	taskID, err := NewTaskID()
+	if err != nil {
+		return UserTask{}, err
+	}
+
+	return UserTask{
+		ID:          taskID,
+		UserID:      s.UserID,
+		TaskType:    s.TaskType,
+		CompletedAt: time.Now(),
+		Points:      s.Points,
+	}, nil
+}
+
+// validateProof проверяет подтверждение в зависимости от его типа
+func validateProof(proofType ProofType, proof string) (string, error) {
+	proof = strings.TrimSpace(proof)
+	if proof == "" {
+		return "", fmt.Errorf("%w: задание требует подтверждения типа %s", ErrInvalidProof, proofType)
+	}
+
+	switch proofType {
+	case ProofTypeURL:
+		parsed, err := url.ParseRequestURI(proof)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return "", fmt.Errorf("%w: ожидается ссылка http или https", ErrInvalidProof)
+		}
+		if len(proof) > 2048 {
+			return "", fmt.Errorf("%w: максимальная длина ссылки 2048 символов", ErrInvalidProof)
+		}
+	case ProofTypeText:
+		if len(proof) > 4000 {
+			return "", fmt.Errorf("%w: максимальная длина текста 4000 символов", ErrInvalidProof)
+		}
+	}
+
+	return proof, nil
+}
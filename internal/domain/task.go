@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,14 +10,14 @@ import (
 
 type TaskType string
 
-const (
-	TaskTypeSurvey            TaskType = "survey"
-	TaskTypeSubscribeTelegram TaskType = "subscribe_telegram"
-	TaskTypeSubscribeTwitter  TaskType = "subscribe_twitter"
-	TaskTypeInviteFriend      TaskType = "invite_friend"
-)
+// TaskTypeInviteFriend ключ задания, за выполнение которого поинты получает и реферер пользователя.
+// Это не список допустимых заданий: все задания, включая это, заводятся в каталоге
+// и проверяются только по нему.
+const TaskTypeInviteFriend TaskType = "invite_friend"
 
-// NewTaskType создает новый TaskType с валидацией
+// NewTaskType создает новый TaskType с валидацией формата ключа.
+// Наличие задания в каталоге проверяется отдельно, чтобы выполнения
+// заданий, выведенных из каталога, по-прежнему читались из базы.
 func NewTaskType(value string) (TaskType, error) {
 	taskType := TaskType(value)
 	if !taskType.IsValid() {
@@ -25,28 +26,18 @@ func NewTaskType(value string) (TaskType, error) {
 	return taskType, nil
 }
 
-// GetPoints возвращает количество поинтов за выполнение задания
-func (t TaskType) GetPoints() int {
-	switch t {
-	case TaskTypeSurvey:
-		return 10
-	case TaskTypeSubscribeTelegram:
-		return 50
-	case TaskTypeSubscribeTwitter:
-		return 50
-	case TaskTypeInviteFriend:
-		return 100
-	default:
-		return 0
-	}
-}
-
-// IsValid проверяет валидность типа задания
+// IsValid проверяет, что ключ задания состоит из латинских букв в нижнем регистре,
+// цифр и подчеркиваний и не длиннее 50 символов
 func (t TaskType) IsValid() bool {
-	return t == TaskTypeSurvey ||
-		t == TaskTypeSubscribeTelegram ||
-		t == TaskTypeSubscribeTwitter ||
-		t == TaskTypeInviteFriend
+	if len(t) == 0 || len(t) > 50 {
+		return false
+	}
+	for _, r := range t {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
 }
 
 // String возвращает строковое представление TaskType
@@ -96,8 +87,8 @@ type UserTask struct {
 	Points      int
 }
 
-// NewUserTask создает новое задание пользователя
-func NewUserTask(userID UserID, taskType TaskType) (UserTask, error) {
+// NewUserTask создает новое выполнение задания из каталога
+func NewUserTask(userID UserID, task Task) (UserTask, error) {
 	taskID, err := NewTaskID()
 	if err != nil {
 		return UserTask{}, err
@@ -106,8 +97,86 @@ func NewUserTask(userID UserID, taskType TaskType) (UserTask, error) {
 	return UserTask{
 		ID:          taskID,
 		UserID:      userID,
-		TaskType:    taskType,
+		TaskType:    task.Key,
 		CompletedAt: time.Now(),
-		Points:      taskType.GetPoints(),
+		Points:      task.Points,
 	}, nil
 }
+
+// Task представляет задание из каталога
+type Task struct {
+	Key         TaskType
+	Title       string
+	Description string
+	Points      int
+	Repeat      RepeatPolicy
+	ProofType   ProofType
+	Active      bool
+	CreatedAt   time.Time
+	ArchivedAt  *time.Time
+}
+
+// NewTask создает новое активное задание каталога
+func NewTask(key, title, description string, points int) (Task, error) {
+	taskType, err := NewTaskType(key)
+	if err != nil {
+		return Task{}, err
+	}
+
+	task := Task{
+		Key:       taskType,
+		Repeat:    OnceRepeatPolicy(),
+		ProofType: ProofTypeNone,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	if err := task.Update(title, description, points); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+// Update изменяет описание и стоимость задания
+func (t *Task) Update(title, description string, points int) error {
+	title = strings.TrimSpace(title)
+	if title == "" || len(title) > 255 {
+		return fmt.Errorf("%w: название должно быть от 1 до 255 символов", ErrInvalidTask)
+	}
+	if points <= 0 {
+		return fmt.Errorf("%w: количество поинтов должно быть положительным", ErrInvalidTask)
+	}
+
+	t.Title = title
+	t.Description = strings.TrimSpace(description)
+	t.Points = points
+	return nil
+}
+
+// SetActive включает или выключает задание
+func (t *Task) SetActive(active bool) error {
+	if t.IsArchived() && active {
+		return ErrTaskArchived
+	}
+	t.Active = active
+	return nil
+}
+
+// Archive выводит задание из каталога. Выполнения задания при этом сохраняются.
+func (t *Task) Archive(now time.Time) error {
+	if t.IsArchived() {
+		return ErrTaskArchived
+	}
+	t.Active = false
+	t.ArchivedAt = &now
+	return nil
+}
+
+// IsArchived проверяет, выведено ли задание из каталога
+func (t Task) IsArchived() bool {
+	return t.ArchivedAt != nil
+}
+
+// IsAvailable проверяет, можно ли сейчас выполнить задание
+func (t Task) IsAvailable() bool {
+	return t.Active && !t.IsArchived()
+}
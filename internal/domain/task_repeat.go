@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RepeatKind вид политики повторного выполнения задания
+type RepeatKind string
+
+const (
+	RepeatOnce     RepeatKind = "once"
+	RepeatInterval RepeatKind = "interval"
+	RepeatDaily    RepeatKind = "daily"
+	RepeatWeekly   RepeatKind = "weekly"
+)
+
+// IsValid проверяет валидность вида политики
+func (k RepeatKind) IsValid() bool {
+	return k == RepeatOnce ||
+		k == RepeatInterval ||
+		k == RepeatDaily ||
+		k == RepeatWeekly
+}
+
+// String возвращает строковое представление RepeatKind
+func (k RepeatKind) String() string {
+	return string(k)
+}
+
+// RepeatPolicy определяет, когда задание можно выполнить повторно.
+// Для interval задание доступно через IntervalHours часов после выполнения,
+// для daily - после ближайшего сброса в ResetMinute минут от полуночи,
+// для weekly - после ближайшего сброса в день ResetWeekday.
+// MaxCompletions ограничивает общее число выполнений, 0 - без ограничения.
+type RepeatPolicy struct {
+	Kind           RepeatKind
+	IntervalHours  int
+	ResetMinute    int
+	ResetWeekday   time.Weekday
+	MaxCompletions int
+}
+
+// OnceRepeatPolicy политика задания, которое выполняется один раз
+func OnceRepeatPolicy() RepeatPolicy {
+	return RepeatPolicy{Kind: RepeatOnce, MaxCompletions: 1, ResetWeekday: time.Monday}
+}
+
+// NewRepeatPolicy создает политику повторного выполнения с валидацией.
+// resetTime задается в формате HH:MM, resetWeekday - названием дня недели на английском.
+func NewRepeatPolicy(kind string, intervalHours int, resetTime, resetWeekday string, maxCompletions int) (RepeatPolicy, error) {
+	policy := RepeatPolicy{
+		Kind:           RepeatKind(kind),
+		ResetWeekday:   time.Monday,
+		MaxCompletions: maxCompletions,
+	}
+
+	if !policy.Kind.IsValid() {
+		return RepeatPolicy{}, fmt.Errorf("%w: неизвестная политика повторения %s", ErrInvalidTask, kind)
+	}
+	if maxCompletions < 0 {
+		return RepeatPolicy{}, fmt.Errorf("%w: лимит выполнений не может быть отрицательным", ErrInvalidTask)
+	}
+
+	switch policy.Kind {
+	case RepeatOnce:
+		policy.MaxCompletions = 1
+	case RepeatInterval:
+		if intervalHours <= 0 {
+			return RepeatPolicy{}, fmt.Errorf("%w: интервал повторения должен быть положительным", ErrInvalidTask)
+		}
+		policy.IntervalHours = intervalHours
+	case RepeatWeekly:
+		if resetWeekday != "" {
+			weekday, err := parseWeekday(resetWeekday)
+			if err != nil {
+				return RepeatPolicy{}, err
+			}
+			policy.ResetWeekday = weekday
+		}
+		fallthrough
+	case RepeatDaily:
+		if resetTime != "" {
+			minute, err := parseResetTime(resetTime)
+			if err != nil {
+				return RepeatPolicy{}, err
+			}
+			policy.ResetMinute = minute
+		}
+	}
+
+	return policy, nil
+}
+
+// ResetTime возвращает время сброса в формате HH:MM
+func (p RepeatPolicy) ResetTime() string {
+	return fmt.Sprintf("%02d:%02d", p.ResetMinute/60, p.ResetMinute%60)
+}
+
+// NextAvailableAt возвращает момент, начиная с которого задание можно выполнить
+// повторно после выполнения в момент last. Для RepeatOnce возвращает false.
+func (p RepeatPolicy) NextAvailableAt(last time.Time, loc *time.Location) (time.Time, bool) {
+	switch p.Kind {
+	case RepeatInterval:
+		return last.Add(time.Duration(p.IntervalHours) * time.Hour), true
+	case RepeatDaily:
+		local := last.In(loc)
+		next := p.resetAt(local.Year(), local.Month(), local.Day(), loc)
+		if !next.After(last) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, true
+	case RepeatWeekly:
+		local := last.In(loc)
+		daysAhead := (int(p.ResetWeekday) - int(local.Weekday()) + 7) % 7
+		next := p.resetAt(local.Year(), local.Month(), local.Day()+daysAhead, loc)
+		if !next.After(last) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// CheckAvailability проверяет, можно ли выполнить задание в момент now,
+// если оно уже выполнено completions раз, последний раз - в момент last.
+// Возвращает ErrTaskLimitReached или *TaskCooldownError.
+func (p RepeatPolicy) CheckAvailability(completions int, last *time.Time, now time.Time, loc *time.Location) error {
+	if p.MaxCompletions > 0 && completions >= p.MaxCompletions {
+		return ErrTaskLimitReached
+	}
+	if last == nil {
+		return nil
+	}
+
+	next, ok := p.NextAvailableAt(*last, loc)
+	if !ok {
+		return ErrTaskLimitReached
+	}
+	if now.Before(next) {
+		return &TaskCooldownError{NextAvailableAt: next}
+	}
+	return nil
+}
+
+func (p RepeatPolicy) resetAt(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, p.ResetMinute/60, p.ResetMinute%60, 0, 0, loc)
+}
+
+// parseResetTime разбирает время сброса в формате HH:MM и возвращает минуты от полуночи
+func parseResetTime(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%w: время сброса должно иметь формат HH:MM", ErrInvalidTask)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseWeekday разбирает название дня недели
+func parseWeekday(value string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), strings.TrimSpace(value)) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: неизвестный день недели %s", ErrInvalidTask, value)
+}
+
+// TaskCooldownError возвращается, когда задание уже выполнено и снова станет
+// доступно в момент NextAvailableAt
+type TaskCooldownError struct {
+	NextAvailableAt time.Time
+}
+
+func (e *TaskCooldownError) Error() string {
+	return fmt.Sprintf("%s, следующее выполнение доступно с %s",
+		ErrTaskOnCooldown.Error(), e.NextAvailableAt.UTC().Format(time.RFC3339))
+}
+
+// Is позволяет сравнивать ошибку с ErrTaskOnCooldown через errors.Is
+func (e *TaskCooldownError) Is(target error) bool {
+	return target == ErrTaskOnCooldown
+}
@@ -109,6 +109,15 @@ func (b Balance) Add(points int) Balance {
 	return Balance{value: newValue}
 }
 
+// Subtract списывает поинты с баланса. В отличие от Add не обнуляет баланс при нехватке
+// поинтов, а возвращает *InsufficientBalanceError.
+func (b Balance) Subtract(points int) (Balance, error) {
+	if points > b.value {
+		return b, &InsufficientBalanceError{Balance: b.value, Required: points}
+	}
+	return Balance{value: b.value - points}, nil
+}
+
 // Value возвращает значение баланса
 func (b Balance) Value() int {
 	return b.value
@@ -119,6 +128,7 @@ type User struct {
 	Username  Username
 	Email     Email
 	Balance   Balance
+	Role      Role
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -146,6 +156,7 @@ func NewUser(username, email string) (User, error) {
 		Username:  usernameValue,
 		Email:     emailValue,
 		Balance:   NewBalance(0),
+		Role:      RoleUser,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
@@ -0,0 +1,32 @@
+package dto
+
+// TokenOutput выходные данные с access токеном и, если он выпущен, refresh токеном
+type TokenOutput struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// RefreshTokenInput входные данные для обновления токенов
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutInput входные данные для завершения сессии
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RequestLoginCodeInput входные данные для запроса кода входа на email.
+// Client заполняется контроллером из запроса.
+type RequestLoginCodeInput struct {
+	Email  string     `json:"email" binding:"required"`
+	Client ClientInfo `json:"-"`
+}
+
+// VerifyLoginCodeInput входные данные для входа по коду из письма
+type VerifyLoginCodeInput struct {
+	Email string `json:"email" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// BadgeOutput значок, полученный пользователем
+type BadgeOutput struct {
+	Key         string    `json:"key"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	AwardedAt   time.Time `json:"awarded_at"`
+}
+
+// ListUserBadgesOutput выходные данные для списка значков пользователя
+type ListUserBadgesOutput struct {
+	UserID string        `json:"user_id"`
+	Badges []BadgeOutput `json:"badges"`
+}
@@ -1,15 +1,31 @@
 package dto
 
-// CompleteTaskInput входные данные для выполнения задания
+import "io"
+
+// CompleteTaskInput входные данные для выполнения задания.
+// Proof содержит ссылку или текст, если задание требует подтверждения,
+// Screenshot заполняется при загрузке скриншота через multipart/form-data.
 type CompleteTaskInput struct {
 	TaskType string `json:"task_type" binding:"required"`
+	Proof    string `json:"proof"`
+
+	Screenshot *ProofUpload `json:"-"`
 }
 
-// CompleteTaskOutput выходные данные после выполнения задания
-type CompleteTaskOutput struct {
-	TaskID     string `json:"task_id"`
-	TaskType   string `json:"task_type"`
-	Points     int    `json:"points"`
-	NewBalance int    `json:"new_balance"`
+// ProofUpload загруженный файл подтверждения
+type ProofUpload struct {
+	Filename string
+	Size     int64
+	Content  io.Reader
 }
 
+// CompleteTaskOutput выходные данные после выполнения задания.
+// Status равен completed, если поинты начислены, или pending, если создана заявка на проверку.
+type CompleteTaskOutput struct {
+	Status       string `json:"status"`
+	TaskID       string `json:"task_id,omitempty"`
+	SubmissionID string `json:"submission_id,omitempty"`
+	TaskType     string `json:"task_type"`
+	Points       int    `json:"points"`
+	NewBalance   int    `json:"new_balance"`
+}
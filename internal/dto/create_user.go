@@ -1,16 +1,30 @@
 package dto
 
-// CreateUserInput входные данные для создания пользователя
+// CreateUserInput входные данные для создания пользователя.
+// ReferralCode - необязательный реферальный код или UUID пригласившего пользователя.
+// Client заполняется контроллером из запроса.
 type CreateUserInput struct {
-	Username string `json:"username" binding:"required"`
-	Email    string `json:"email" binding:"required"`
+	Username     string     `json:"username" binding:"required"`
+	Email        string     `json:"email" binding:"required"`
+	ReferralCode string     `json:"referral_code"`
+	Client       ClientInfo `json:"-"`
 }
 
-// CreateUserOutput выходные данные после создания пользователя
+// CreateUserOutput выходные данные после создания пользователя.
+// ReferrerID, ReferralStatus и ReferralBonus заполняются, если при регистрации применен
+// реферальный код: бонус начисляется после выполнения условий, пока связь в состоянии pending.
+// ReferralWarning заполняется, если переданный код был проигнорирован.
 type CreateUserOutput struct {
-	UserID      string `json:"user_id"`
-	Username    string `json:"username"`
-	Email       string `json:"email"`
-	AccessToken string `json:"access_token"`
+	UserID          string `json:"user_id"`
+	Username        string `json:"username"`
+	Email           string `json:"email"`
+	Balance         int    `json:"balance"`
+	ReferralCode    string `json:"referral_code"`
+	ReferrerID      string `json:"referrer_id,omitempty"`
+	ReferralStatus  string `json:"referral_status,omitempty"`
+	ReferralBonus   int    `json:"referral_bonus,omitempty"`
+	ReferralWarning string `json:"referral_warning,omitempty"`
+	AccessToken     string `json:"access_token"`
+	RefreshToken    string `json:"refresh_token"`
 }
 
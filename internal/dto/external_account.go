@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// LinkExternalAccountInput входные данные для привязки внешнего аккаунта
+type LinkExternalAccountInput struct {
+	Provider   string `json:"provider" binding:"required"`
+	ExternalID string `json:"external_id" binding:"required"`
+}
+
+// ExternalAccountOutput привязанный внешний аккаунт
+type ExternalAccountOutput struct {
+	Provider   string    `json:"provider"`
+	ExternalID string    `json:"external_id"`
+	UserID     string    `json:"user_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TaskCallbackOutput результат обработки callback партнерского сервиса.
+// Status равен completed или duplicate, если событие уже было обработано.
+type TaskCallbackOutput struct {
+	Status   string `json:"status"`
+	EventID  string `json:"event_id"`
+	UserID   string `json:"user_id,omitempty"`
+	TaskID   string `json:"task_id,omitempty"`
+	TaskType string `json:"task_type"`
+	Points   int    `json:"points,omitempty"`
+}
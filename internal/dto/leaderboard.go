@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // LeaderboardEntry запись в таблице лидеров
 type LeaderboardEntry struct {
 	Rank     int    `json:"rank"`
@@ -8,9 +10,25 @@ type LeaderboardEntry struct {
 	Balance  int    `json:"balance"`
 }
 
-// GetLeaderboardOutput выходные данные для таблицы лидеров
+// GetLeaderboardOutput выходные данные для таблицы лидеров.
+// Для периодов кроме all_time в Balance записей возвращаются поинты, заработанные начиная с Since.
+// Total - общее число участников, NextCursor передается в параметре cursor для получения следующей страницы.
 type GetLeaderboardOutput struct {
-	Users []LeaderboardEntry `json:"users"`
-	Total int                 `json:"total"`
+	Period     string             `json:"period"`
+	Since      *time.Time         `json:"since,omitempty"`
+	Users      []LeaderboardEntry `json:"users"`
+	Total      int                `json:"total"`
+	NextCursor string             `json:"next_cursor,omitempty"`
 }
 
+
+// GetUserRankOutput выходные данные для места пользователя в таблице лидеров.
+// Percentile - доля остальных участников в процентах, которые стоят ниже пользователя.
+// Above содержит пользователей непосредственно выше, Below - непосредственно ниже, по возрастанию места.
+type GetUserRankOutput struct {
+	User       LeaderboardEntry   `json:"user"`
+	Total      int                `json:"total"`
+	Percentile float64            `json:"percentile"`
+	Above      []LeaderboardEntry `json:"above"`
+	Below      []LeaderboardEntry `json:"below"`
+}
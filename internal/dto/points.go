@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// GrantPointsInput входные данные для начисления поинтов администратором
+type GrantPointsInput struct {
+	Points int    `json:"points" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GrantPointsOutput выходные данные для начисления поинтов администратором
+type GrantPointsOutput struct {
+	UserID     string    `json:"user_id"`
+	Points     int       `json:"points"`
+	NewBalance int       `json:"new_balance"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -1,16 +1,130 @@
 package dto
 
-// ProcessReferralInput входные данные для обработки реферального кода
+import "time"
+
+// ProcessReferralInput входные данные для обработки реферального кода.
+// Code - реферальный код пригласившего пользователя. ReferrerID - его UUID,
+// поддерживается для старых клиентов; используется, если Code не заполнен.
+// Client заполняется контроллером из запроса.
 type ProcessReferralInput struct {
-	ReferrerID string `json:"referrer_id" binding:"required"`
+	Code       string     `json:"code"`
+	ReferrerID string     `json:"referrer_id"`
+	Client     ClientInfo `json:"-"`
+}
+
+// ClientInfo данные клиента, из которых строится отпечаток для проверки на мошенничество
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	DeviceID  string
+}
+
+// SetReferralCodeInput входные данные для выбора собственного реферального кода
+type SetReferralCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// ReferralCodesOutput реферальные коды пользователя
+type ReferralCodesOutput struct {
+	ReferralCode       string `json:"referral_code"`
+	VanityReferralCode string `json:"vanity_referral_code,omitempty"`
 }
 
-// ProcessReferralOutput выходные данные после обработки реферального кода
+// ProcessReferralOutput выходные данные после обработки реферального кода.
+// Status: pending, on_hold, vested или cancelled; on_hold - связь ожидает проверки модератором. BonusPoints и ReferrerBonusPoints начисляются
+// приглашенному и пригласившему пользователям только в состоянии vested.
 type ProcessReferralOutput struct {
-	ReferralID     string `json:"referral_id"`
-	ReferrerID     string `json:"referrer_id"`
-	ReferredUserID string `json:"referred_user_id"`
-	BonusPoints    int    `json:"bonus_points"`
-	NewBalance     int    `json:"new_balance"`
+	ReferralID          string `json:"referral_id"`
+	ReferrerID          string `json:"referrer_id"`
+	ReferredUserID      string `json:"referred_user_id"`
+	Status              string `json:"status"`
+	BonusPoints         int    `json:"bonus_points"`
+	ReferrerBonusPoints int    `json:"referrer_bonus_points"`
+	NewBalance          int    `json:"new_balance"`
 }
 
+
+// CommissionLevelOutput доход от комиссий с одного уровня реферальной цепочки.
+// RatePercent - действующая ставка уровня, 0 если уровень больше не входит в план.
+// Referrals - число пользователей уровня, с поинтов которых начислялись комиссии.
+type CommissionLevelOutput struct {
+	Level       int     `json:"level"`
+	RatePercent float64 `json:"rate_percent"`
+	Referrals   int     `json:"referrals"`
+	Commissions int     `json:"commissions"`
+	Points      int     `json:"points"`
+}
+
+// GetReferralCommissionsOutput выходные данные для дохода от реферальных комиссий
+type GetReferralCommissionsOutput struct {
+	UserID      string                  `json:"user_id"`
+	TotalPoints int                     `json:"total_points"`
+	Levels      []CommissionLevelOutput `json:"levels"`
+}
+
+// FraudSignalOutput сработавшее правило проверки на мошенничество
+type FraudSignalOutput struct {
+	Rule   string `json:"rule"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// ReferralReviewOutput реферальная связь для модерации
+type ReferralReviewOutput struct {
+	ReferralID     string              `json:"referral_id"`
+	ReferrerID     string              `json:"referrer_id"`
+	ReferredUserID string              `json:"referred_user_id"`
+	Status         string              `json:"status"`
+	FraudScore     int                 `json:"fraud_score"`
+	Signals        []FraudSignalOutput `json:"signals"`
+	ReviewedBy     string              `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time          `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// ListReferralsForReviewOutput выходные данные для списка реферальных связей на модерации
+type ListReferralsForReviewOutput struct {
+	Referrals []ReferralReviewOutput `json:"referrals"`
+	Total     int                    `json:"total"`
+}
+
+// ReferredUserOutput пользователь, приглашенный реферером.
+// Status - состояние реферальной связи: pending, on_hold, vested или cancelled.
+// PointsGenerated - поинты, полученные реферером благодаря этому пользователю:
+// бонус за приглашение после начисления и комиссии с его поинтов.
+type ReferredUserOutput struct {
+	UserID              string    `json:"user_id"`
+	Username            string    `json:"username"`
+	JoinedAt            time.Time `json:"joined_at"`
+	ReferredAt          time.Time `json:"referred_at"`
+	Status              string    `json:"status"`
+	BonusPoints         int       `json:"bonus_points"`
+	ReferrerBonusPoints int       `json:"referrer_bonus_points"`
+	PointsGenerated     int       `json:"points_generated"`
+}
+
+// ListReferredUsersOutput выходные данные для списка приглашенных пользователей.
+// NextCursor передается в параметре cursor для получения следующей страницы.
+type ListReferredUsersOutput struct {
+	UserID     string               `json:"user_id"`
+	Referrals  []ReferredUserOutput `json:"referrals"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// ReferralLeaderboardEntry запись в таблице лидеров по рефералам
+type ReferralLeaderboardEntry struct {
+	Rank      int    `json:"rank"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	Referrals int    `json:"referrals"`
+}
+
+// GetReferralLeaderboardOutput выходные данные для таблицы лидеров по рефералам.
+// Учитываются приглашения, сделанные начиная с Since, кроме отмененных и задержанных на проверку.
+type GetReferralLeaderboardOutput struct {
+	Period     string                     `json:"period"`
+	Since      *time.Time                 `json:"since,omitempty"`
+	Users      []ReferralLeaderboardEntry `json:"users"`
+	Total      int                        `json:"total"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
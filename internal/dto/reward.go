@@ -0,0 +1,78 @@
+package dto
+
+import "time"
+
+// CreateRewardInput входные данные для добавления товара в магазин наград.
+// Stock не задается для товара без ограничения количества, PerUserLimit 0 - без лимита на пользователя.
+// Товар доступен в интервале [available_from, available_until), границы необязательны.
+type CreateRewardInput struct {
+	Title          string     `json:"title" binding:"required"`
+	Description    string     `json:"description"`
+	Cost           int        `json:"cost" binding:"required"`
+	Stock          *int       `json:"stock"`
+	PerUserLimit   int        `json:"per_user_limit"`
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+}
+
+// UpdateRewardInput входные данные для изменения товара.
+// Незаполненные поля не изменяются.
+type UpdateRewardInput struct {
+	Title          *string    `json:"title"`
+	Description    *string    `json:"description"`
+	Cost           *int       `json:"cost"`
+	Stock          *int       `json:"stock"`
+	PerUserLimit   *int       `json:"per_user_limit"`
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+	Active         *bool      `json:"active"`
+}
+
+// RewardOutput товар магазина наград. Stock равен null для товара без ограничения количества.
+type RewardOutput struct {
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Cost           int        `json:"cost"`
+	Stock          *int       `json:"stock"`
+	PerUserLimit   int        `json:"per_user_limit,omitempty"`
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ListRewardsOutput выходные данные для списка товаров
+type ListRewardsOutput struct {
+	Rewards []RewardOutput `json:"rewards"`
+	Total   int            `json:"total"`
+}
+
+// RedeemRewardInput входные данные для заказа товара за поинты
+type RedeemRewardInput struct {
+	RewardID string `json:"reward_id" binding:"required"`
+}
+
+// RewardOrderOutput заказ товара. Status: pending или fulfilled.
+type RewardOrderOutput struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	RewardID    string     `json:"reward_id"`
+	Title       string     `json:"title"`
+	Cost        int        `json:"cost"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FulfilledAt *time.Time `json:"fulfilled_at,omitempty"`
+}
+
+// RedeemRewardOutput выходные данные после заказа товара
+type RedeemRewardOutput struct {
+	Order      RewardOrderOutput `json:"order"`
+	NewBalance int               `json:"new_balance"`
+}
+
+// ListRewardOrdersOutput выходные данные для списка заказов
+type ListRewardOrdersOutput struct {
+	Orders []RewardOrderOutput `json:"orders"`
+	Total  int                 `json:"total"`
+}
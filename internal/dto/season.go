@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateSeasonInput входные данные для создания сезона.
+// Сезон охватывает интервал [starts_at, ends_at).
+type CreateSeasonInput struct {
+	Name     string    `json:"name" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+}
+
+// SeasonOutput сезон. Status: upcoming, active, ended или finalized.
+type SeasonOutput struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Status      string     `json:"status"`
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+}
+
+// ListSeasonsOutput выходные данные для списка сезонов
+type ListSeasonsOutput struct {
+	Seasons []SeasonOutput `json:"seasons"`
+	Total   int            `json:"total"`
+}
+
+// GetSeasonLeaderboardOutput выходные данные для таблицы лидеров сезона.
+// В Balance записей возвращаются очки сезона. Для завершенного сезона таблица
+// берется из сохраненных итогов и больше не меняется.
+type GetSeasonLeaderboardOutput struct {
+	Season     SeasonOutput       `json:"season"`
+	Users      []LeaderboardEntry `json:"users"`
+	Total      int                `json:"total"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
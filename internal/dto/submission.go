@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+// SubmissionOutput заявка на выполнение задания
+type SubmissionOutput struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	TaskType        string     `json:"task_type"`
+	ProofType       string     `json:"proof_type"`
+	Proof           string     `json:"proof"`
+	Points          int        `json:"points"`
+	Status          string     `json:"status"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// ListSubmissionsOutput выходные данные для списка заявок
+type ListSubmissionsOutput struct {
+	Submissions []SubmissionOutput `json:"submissions"`
+	Total       int                `json:"total"`
+}
+
+// RejectSubmissionInput входные данные для отклонения заявки
+type RejectSubmissionInput struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ProofFileOutput файл подтверждения заявки
+type ProofFileOutput struct {
+	ContentType string
+	Content     io.ReadCloser
+}
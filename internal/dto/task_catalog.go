@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+// CreateCatalogTaskInput входные данные для добавления задания в каталог
+type CreateCatalogTaskInput struct {
+	Key         string `json:"key" binding:"required"`
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Points      int    `json:"points" binding:"required"`
+	ProofType   string `json:"proof_type"`
+
+	Repeat *RepeatPolicyInput `json:"repeat"`
+}
+
+// RepeatPolicyInput политика повторного выполнения задания.
+// Policy: once, interval, daily или weekly.
+type RepeatPolicyInput struct {
+	Policy         string `json:"policy" binding:"required"`
+	IntervalHours  int    `json:"interval_hours"`
+	ResetTime      string `json:"reset_time"`
+	ResetWeekday   string `json:"reset_weekday"`
+	MaxCompletions int    `json:"max_completions"`
+}
+
+// RepeatPolicyOutput политика повторного выполнения задания
+type RepeatPolicyOutput struct {
+	Policy         string `json:"policy"`
+	IntervalHours  int    `json:"interval_hours,omitempty"`
+	ResetTime      string `json:"reset_time,omitempty"`
+	ResetWeekday   string `json:"reset_weekday,omitempty"`
+	MaxCompletions int    `json:"max_completions,omitempty"`
+}
+
+// UpdateCatalogTaskInput входные данные для изменения задания каталога.
+// Незаполненные поля не изменяются.
+type UpdateCatalogTaskInput struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Points      *int    `json:"points"`
+	Active      *bool   `json:"active"`
+	ProofType   *string `json:"proof_type"`
+
+	Repeat *RepeatPolicyInput `json:"repeat"`
+}
+
+// CatalogTaskOutput задание каталога
+type CatalogTaskOutput struct {
+	Key         string             `json:"key"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Points      int                `json:"points"`
+	Repeat      RepeatPolicyOutput `json:"repeat"`
+	ProofType   string             `json:"proof_type"`
+	Active      bool               `json:"active"`
+	CreatedAt   time.Time          `json:"created_at"`
+	ArchivedAt  *time.Time         `json:"archived_at,omitempty"`
+}
+
+// ListCatalogTasksOutput выходные данные для списка заданий каталога
+type ListCatalogTasksOutput struct {
+	Tasks []CatalogTaskOutput `json:"tasks"`
+	Total int                 `json:"total"`
+}
@@ -1,10 +1,21 @@
 package dto
 
-// GetUserStatusOutput выходные данные для получения статуса пользователя
+import "time"
+
+// GetUserStatusOutput выходные данные для получения статуса пользователя.
+// ExpiringPoints - поинты с баланса, которые сгорят до ExpiringBefore.
 type GetUserStatusOutput struct {
 	UserID         string `json:"user_id"`
 	Balance        int    `json:"balance"`
 	CompletedTasks int    `json:"completed_tasks"`
 	ReferralCount  int    `json:"referral_count"`
+
+	ReferralCode       string `json:"referral_code"`
+	VanityReferralCode string `json:"vanity_referral_code,omitempty"`
+
+	ExpiringPoints int       `json:"expiring_points"`
+	ExpiringBefore time.Time `json:"expiring_before"`
+
+	Badges []BadgeOutput `json:"badges"`
 }
 
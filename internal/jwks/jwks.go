@@ -0,0 +1,62 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// JSONWebKey открытый ключ в формате JWK (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+
+	// параметры RSA ключа
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// параметры Ed25519 ключа
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet набор открытых ключей в формате JWKS
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS возвращает открытые части всех ключей набора
+func (s *KeySet) JWKS() JSONWebKeySet {
+	result := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(s.order))}
+	for _, kid := range s.order {
+		key := s.keys[kid]
+		jwk := JSONWebKey{
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			Kid: key.ID,
+		}
+
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encodeBase64URL(public.N.Bytes())
+			jwk.E = encodeBase64URL(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = encodeBase64URL(public)
+		default:
+			continue
+		}
+
+		result.Keys = append(result.Keys, jwk)
+	}
+	return result
+}
+
+func encodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
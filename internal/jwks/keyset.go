@@ -0,0 +1,158 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownKeyID   = errors.New("неизвестный kid в заголовке токена")
+	ErrUnexpectedAlg  = errors.New("алгоритм токена не совпадает с алгоритмом ключа")
+	ErrNoSigningKey   = errors.New("активный ключ не содержит закрытой части")
+	ErrUnsupportedKey = errors.New("неподдерживаемый тип ключа, ожидается RSA или Ed25519")
+)
+
+// KeyFile описывает PEM файл ключа и его идентификатор kid
+type KeyFile struct {
+	ID   string
+	Path string
+}
+
+// Key ключ подписи JWT. Для выведенных из ротации ключей достаточно открытой части.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeySet набор ключей подписи JWT: одним активным ключом токены подписываются,
+// всеми ключами набора токены проверяются
+type KeySet struct {
+	keys      map[string]Key
+	order     []string
+	activeKID string
+}
+
+// LoadKeySet загружает ключи из PEM файлов. Активным становится ключ activeKID,
+// а если он не задан - первый ключ из списка.
+func LoadKeySet(files []KeyFile, activeKID string) (*KeySet, error) {
+	if len(files) == 0 {
+		return nil, errors.New("не задан ни один ключ подписи JWT")
+	}
+
+	set := &KeySet{keys: make(map[string]Key, len(files))}
+	for _, file := range files {
+		if _, exists := set.keys[file.ID]; exists {
+			return nil, fmt.Errorf("ключ %s задан несколько раз", file.ID)
+		}
+
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения ключа %s: %w", file.ID, err)
+		}
+
+		key, err := ParseKey(file.ID, data)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка разбора ключа %s: %w", file.ID, err)
+		}
+
+		set.keys[file.ID] = key
+		set.order = append(set.order, file.ID)
+	}
+
+	if activeKID == "" {
+		activeKID = set.order[0]
+	}
+	active, ok := set.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("активный ключ %s не найден среди загруженных", activeKID)
+	}
+	if active.Private == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoSigningKey, activeKID)
+	}
+	set.activeKID = activeKID
+
+	return set, nil
+}
+
+// ParseKey разбирает закрытый (PKCS#8, PKCS#1) или открытый (PKIX) ключ в формате PEM
+func ParseKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("файл не содержит PEM блока")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("неподдерживаемый тип PEM блока %q", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return Key{ID: id, Method: jwt.SigningMethodRS256, Private: k, Public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return Key{ID: id, Method: jwt.SigningMethodRS256, Public: k}, nil
+	case ed25519.PrivateKey:
+		return Key{ID: id, Method: jwt.SigningMethodEdDSA, Private: k, Public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return Key{ID: id, Method: jwt.SigningMethodEdDSA, Public: k}, nil
+	default:
+		return Key{}, ErrUnsupportedKey
+	}
+}
+
+// Sign подписывает claims активным ключом и добавляет kid в заголовок токена
+func (s *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	key := s.keys[s.activeKID]
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc возвращает открытый ключ для проверки токена по kid из его заголовка
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrUnexpectedAlg
+	}
+	return key.Public, nil
+}
+
+// ValidMethods возвращает алгоритмы подписи, допустимые для ключей набора
+func (s *KeySet) ValidMethods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, kid := range s.order {
+		alg := s.keys[kid].Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
@@ -12,11 +12,19 @@ import (
 )
 
 const (
-	UserIDKey = "user_id"
+	UserIDKey    = "user_id"
+	RoleKey      = "role"
+	ExpiresAtKey = "expires_at"
 )
 
+// TokenKeySet набор ключей для проверки подписи JWT токенов
+type TokenKeySet interface {
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	ValidMethods() []string
+}
+
 // AuthMiddleware middleware для проверки JWT токена
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+func AuthMiddleware(keySet TokenKeySet) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -36,12 +44,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, domain.ErrInvalidUsername
-			}
-			return []byte(jwtSecret), nil
-		})
+		token, err := jwt.Parse(tokenString, keySet.Keyfunc, jwt.WithValidMethods(keySet.ValidMethods()))
 
 		if err != nil {
 			slog.Warn("Ошибка парсинга JWT токена", "error", err, "path", c.Request.URL.Path)
@@ -73,7 +76,23 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		// токены, выданные до появления ролей, не содержат claim role
+		role := domain.RoleUser
+		if roleValue, ok := claims["role"].(string); ok {
+			role, err = domain.NewRole(roleValue)
+			if err != nil {
+				slog.Warn("Некорректная роль в JWT токене", "role", roleValue, "path", c.Request.URL.Path)
+				sendError(c, "некорректная роль в токене")
+				c.Abort()
+				return
+			}
+		}
+
 		c.Set(UserIDKey, userID)
+		c.Set(RoleKey, role)
+		if expiresAt, err := claims.GetExpirationTime(); err == nil && expiresAt != nil {
+			c.Set(ExpiresAtKey, expiresAt.Time)
+		}
 		c.Next()
 	}
 }
@@ -85,3 +104,10 @@ func sendError(c *gin.Context, message string) {
 	}
 	c.JSON(http.StatusUnauthorized, response)
 }
+
+// abortWithError прерывает обработку запроса и отправляет ошибку в формате JSON
+func abortWithError(c *gin.Context, code int, err error) {
+	c.AbortWithStatusJSON(code, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserIDParam имя параметра пути с ID пользователя
+const UserIDParam = "id"
+
+// RequireOwnerOrRole middleware, разрешающий доступ к /users/:id/... только владельцу
+// ресурса или пользователю с одной из переданных ролей. Должен идти после AuthMiddleware.
+func RequireOwnerOrRole(roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(UserIDKey) == c.Param(UserIDParam) || hasRole(c, roles) {
+			c.Next()
+			return
+		}
+
+		denyAccess(c)
+	}
+}
+
+// RequireRole middleware, разрешающий доступ только пользователям с одной из переданных ролей.
+// Должен идти после AuthMiddleware.
+func RequireRole(roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if hasRole(c, roles) {
+			c.Next()
+			return
+		}
+
+		denyAccess(c)
+	}
+}
+
+// CurrentRole возвращает роль текущего пользователя из контекста
+func CurrentRole(c *gin.Context) domain.Role {
+	role, ok := c.Get(RoleKey)
+	if !ok {
+		return ""
+	}
+	value, _ := role.(domain.Role)
+	return value
+}
+
+// hasRole проверяет, есть ли у текущего пользователя одна из ролей
+func hasRole(c *gin.Context, roles []domain.Role) bool {
+	current := CurrentRole(c)
+	for _, role := range roles {
+		if current == role {
+			return true
+		}
+	}
+	return false
+}
+
+// denyAccess прерывает обработку запроса с ошибкой 403
+func denyAccess(c *gin.Context) {
+	slog.Warn("Доступ запрещен",
+		"user_id", c.GetString(UserIDKey),
+		"role", CurrentRole(c),
+		"path", c.Request.URL.Path,
+	)
+	abortWithError(c, http.StatusForbidden, domain.ErrForbidden)
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ownUserID   = "11111111-1111-1111-1111-111111111111"
+	otherUserID = "22222222-2222-2222-2222-222222222222"
+
+	forbiddenBody = `{"error":"недостаточно прав для выполнения операции"}`
+)
+
+// newAuthorizationRouter собирает роутер с теми же проверками прав, что и в приложении.
+// Вместо AuthMiddleware пользователь и роль берутся из заголовков запроса.
+func newAuthorizationRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.Use(func(c *gin.Context) {
+		c.Set(UserIDKey, ownUserID)
+		if role := c.GetHeader("X-Test-Role"); role != "" {
+			c.Set(RoleKey, domain.Role(role))
+		}
+		c.Next()
+	})
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	users := router.Group("/users/:id", RequireOwnerOrRole(domain.RoleAdmin))
+	users.GET("/balance", ok)
+	users.POST("/tasks/complete", ok)
+
+	moderation := router.Group("/moderation", RequireRole(domain.RoleModerator, domain.RoleAdmin))
+	moderation.GET("/submissions", ok)
+
+	admin := router.Group("/admin", RequireRole(domain.RoleAdmin))
+	admin.POST("/users/:id/points", ok)
+
+	return router
+}
+
+func TestAuthorizationRouteMatrix(t *testing.T) {
+	router := newAuthorizationRouter()
+
+	tests := []struct {
+		name   string
+		role   domain.Role
+		method string
+		path   string
+		want   int
+	}{
+		{"user own balance", domain.RoleUser, http.MethodGet, "/users/" + ownUserID + "/balance", http.StatusOK},
+		{"user other balance", domain.RoleUser, http.MethodGet, "/users/" + otherUserID + "/balance", http.StatusForbidden},
+		{"user own task", domain.RoleUser, http.MethodPost, "/users/" + ownUserID + "/tasks/complete", http.StatusOK},
+		{"user other task", domain.RoleUser, http.MethodPost, "/users/" + otherUserID + "/tasks/complete", http.StatusForbidden},
+		{"user moderation", domain.RoleUser, http.MethodGet, "/moderation/submissions", http.StatusForbidden},
+		{"user admin", domain.RoleUser, http.MethodPost, "/admin/users/" + ownUserID + "/points", http.StatusForbidden},
+
+		{"moderator own balance", domain.RoleModerator, http.MethodGet, "/users/" + ownUserID + "/balance", http.StatusOK},
+		{"moderator other balance", domain.RoleModerator, http.MethodGet, "/users/" + otherUserID + "/balance", http.StatusForbidden},
+		{"moderator moderation", domain.RoleModerator, http.MethodGet, "/moderation/submissions", http.StatusOK},
+		{"moderator admin", domain.RoleModerator, http.MethodPost, "/admin/users/" + otherUserID + "/points", http.StatusForbidden},
+
+		{"admin own balance", domain.RoleAdmin, http.MethodGet, "/users/" + ownUserID + "/balance", http.StatusOK},
+		{"admin other balance", domain.RoleAdmin, http.MethodGet, "/users/" + otherUserID + "/balance", http.StatusOK},
+		{"admin other task", domain.RoleAdmin, http.MethodPost, "/users/" + otherUserID + "/tasks/complete", http.StatusOK},
+		{"admin moderation", domain.RoleAdmin, http.MethodGet, "/moderation/submissions", http.StatusOK},
+		{"admin admin", domain.RoleAdmin, http.MethodPost, "/admin/users/" + otherUserID + "/points", http.StatusOK},
+
+		{"no role own balance", "", http.MethodGet, "/users/" + ownUserID + "/balance", http.StatusOK},
+		{"no role other balance", "", http.MethodGet, "/users/" + otherUserID + "/balance", http.StatusForbidden},
+		{"no role admin", "", http.MethodPost, "/admin/users/" + ownUserID + "/points", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.role != "" {
+				req.Header.Set("X-Test-Role", tt.role.String())
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("статус %d, ожидался %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusForbidden && rec.Body.String() != forbiddenBody {
+				t.Errorf("тело ответа %s, ожидалось %s", rec.Body.String(), forbiddenBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotencyStore хранилище ключей идемпотентности
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, record domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, record domain.IdempotencyRecord) error
+	Release(ctx context.Context, record domain.IdempotencyRecord) error
+}
+
+// IdempotencyMiddleware middleware для повторного воспроизведения ответа на запрос
+// с уже использованным заголовком Idempotency-Key
+func IdempotencyMiddleware(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return idempotencyMiddleware(store, ttl, keepResponse)
+}
+
+// SignupIdempotencyMiddleware middleware идемпотентности для регистрации пользователя.
+// Ответ на регистрацию содержит токены, поэтому он не сохраняется: по ключу запоминается
+// только ID созданного пользователя, и повтор получает 409 с этим ID, а не новые токены.
+func SignupIdempotencyMiddleware(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return idempotencyMiddleware(store, ttl, redactSignupResponse)
+}
+
+// responseFilter возвращает ответ, который сохраняется для повторов вместо ответа обработчика
+type responseFilter func(status int, body []byte) (int, []byte)
+
+// keepResponse сохраняет ответ обработчика без изменений
+func keepResponse(status int, body []byte) (int, []byte) {
+	return status, body
+}
+
+// redactSignupResponse заменяет успешный ответ регистрации ошибкой 409 с ID созданного пользователя.
+// Ответы с ошибками токенов не содержат и сохраняются как есть.
+func redactSignupResponse(status int, body []byte) (int, []byte) {
+	if status >= http.StatusMultipleChoices {
+		return status, body
+	}
+
+	var created struct {
+		UserID string `json:"user_id"`
+	}
+	_ = json.Unmarshal(body, &created)
+
+	redacted, _ := json.Marshal(map[string]string{
+		"error":   domain.ErrIdempotencyUserCreated.Error(),
+		"user_id": created.UserID,
+	})
+	return http.StatusConflict, redacted
+}
+
+func idempotencyMiddleware(store IdempotencyStore, ttl time.Duration, filter responseFilter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := c.GetString(UserIDKey)
+		if scope == "" {
+			scope = anonymousScope(c)
+		}
+		route := c.Request.Method + " " + c.Request.URL.Path
+
+		record, err := domain.NewIdempotencyRecord(scope, key, route, hashRequestBody(body), ttl)
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, err)
+			return
+		}
+
+		existing, err := store.Reserve(c.Request.Context(), record)
+		if err != nil {
+			slog.Error("Ошибка резервирования ключа идемпотентности", "error", err, "path", c.Request.URL.Path)
+			abortWithError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		if existing != nil {
+			replayIdempotentResponse(c, record, *existing)
+			return
+		}
+
+		// сохранение ответа не должно зависеть от того, дождался ли его клиент
+		storeCtx := context.WithoutCancel(c.Request.Context())
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		defer func() {
+			if p := recover(); p != nil {
+				releaseIdempotencyKey(storeCtx, store, record)
+				panic(p)
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			releaseIdempotencyKey(storeCtx, store, record)
+			return
+		}
+
+		record.StatusCode, record.ResponseBody = filter(recorder.Status(), recorder.body.Bytes())
+		if err := store.Complete(storeCtx, record); err != nil {
+			slog.Error("Ошибка сохранения ответа по ключу идемпотентности", "error", err, "path", c.Request.URL.Path)
+		}
+	}
+}
+
+// replayIdempotentResponse возвращает сохраненный ответ или ошибку, если ключ использован иначе
+func replayIdempotentResponse(c *gin.Context, record, existing domain.IdempotencyRecord) {
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		slog.Warn("Ключ идемпотентности использован с другим запросом", "path", c.Request.URL.Path)
+		abortWithError(c, http.StatusUnprocessableEntity, domain.ErrIdempotencyKeyReused)
+	case !existing.IsCompleted():
+		abortWithError(c, http.StatusConflict, domain.ErrIdempotencyKeyInProgress)
+	default:
+		c.Header(IdempotencyReplayedHeader, "true")
+		c.Data(existing.StatusCode, "application/json; charset=utf-8", existing.ResponseBody)
+		c.Abort()
+	}
+}
+
+// releaseIdempotencyKey освобождает ключ, чтобы неуспешный запрос можно было повторить
+func releaseIdempotencyKey(ctx context.Context, store IdempotencyStore, record domain.IdempotencyRecord) {
+	if err := store.Release(ctx, record); err != nil {
+		slog.Error("Ошибка освобождения ключа идемпотентности", "error", err, "route", record.Route)
+	}
+}
+
+// anonymousScope возвращает область ключей идемпотентности для запроса без аутентификации.
+// Ключи разных клиентов не должны пересекаться, поэтому область строится по IP адресу
+// и User-Agent. Тело запроса в область не входит: повтор ключа с другим телом
+// сравнивается по хешу тела, как и для аутентифицированных запросов, и отклоняется.
+func anonymousScope(c *gin.Context) string {
+	hash := sha256.New()
+	for _, part := range []string{"anonymous", c.ClientIP(), c.Request.UserAgent()} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashRequestBody вычисляет хеш тела запроса
+func hashRequestBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder копирует тело ответа, чтобы сохранить его по ключу идемпотентности
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryIdempotencyStore хранилище ключей идемпотентности в памяти
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]domain.IdempotencyRecord)}
+}
+
+func recordKey(record domain.IdempotencyRecord) string {
+	return record.Scope + "|" + record.Key + "|" + record.Route
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, record domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[recordKey(record)]; ok {
+		return &existing, nil
+	}
+	s.records[recordKey(record)] = record
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[recordKey(record)] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, record domain.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, recordKey(record))
+	return nil
+}
+
+// newIdempotencyRouter собирает роутер регистрации, который выдает каждому запросу новый токен
+func newIdempotencyRouter(store IdempotencyStore) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	calls := 0
+	router.POST("/users", SignupIdempotencyMiddleware(store, time.Hour), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{
+			"user_id":       fmt.Sprintf("user-%d", calls),
+			"access_token":  fmt.Sprintf("access-%d", calls),
+			"refresh_token": fmt.Sprintf("refresh-%d", calls),
+		})
+	})
+	return router, &calls
+}
+
+func postUser(router *gin.Engine, ip, userAgent, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.RemoteAddr = ip + ":12345"
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(IdempotencyKeyHeader, key)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysSignupWithoutTokens(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	router, calls := newIdempotencyRouter(store)
+	body := `{"username":"alice","email":"alice@example.com"}`
+
+	first := postUser(router, "192.0.2.1", "app/1.0", "key-1", body)
+	retry := postUser(router, "192.0.2.1", "app/1.0", "key-1", body)
+
+	if *calls != 1 {
+		t.Fatalf("обработчик вызван %d раз, ожидался один", *calls)
+	}
+	if first.Code != http.StatusCreated || !strings.Contains(first.Body.String(), "refresh-1") {
+		t.Fatalf("первый запрос: %d %s", first.Code, first.Body.String())
+	}
+	if retry.Code != http.StatusConflict || retry.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Fatalf("повтор: %d, ожидался 409", retry.Code)
+	}
+	if !strings.Contains(retry.Body.String(), `"user_id":"user-1"`) || strings.Contains(retry.Body.String(), "token") {
+		t.Fatalf("повтор вернул %s, ожидался только ID пользователя", retry.Body.String())
+	}
+
+	for _, record := range store.records {
+		if strings.Contains(string(record.ResponseBody), "access-1") || strings.Contains(string(record.ResponseBody), "refresh-1") {
+			t.Fatalf("в хранилище сохранены токены: %s", record.ResponseBody)
+		}
+	}
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	calls := 0
+	router.POST("/orders", IdempotencyMiddleware(newMemoryIdempotencyStore(), time.Hour), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"order_id": fmt.Sprintf("order-%d", calls)})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"reward_id":"r"}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first, retry := send(), send()
+	if calls != 1 || retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("повтор: %d %s, ожидался сохраненный ответ %s", retry.Code, retry.Body.String(), first.Body.String())
+	}
+}
+
+func TestIdempotencyDoesNotShareAnonymousKeys(t *testing.T) {
+	body := `{"username":"alice","email":"alice@example.com"}`
+
+	tests := []struct {
+		name      string
+		ip        string
+		userAgent string
+		body      string
+	}{
+		{"other ip", "198.51.100.7", "app/1.0", body},
+		{"other user agent", "192.0.2.1", "curl/8.0", body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, calls := newIdempotencyRouter(newMemoryIdempotencyStore())
+
+			victim := postUser(router, "192.0.2.1", "app/1.0", "key-1", body)
+			other := postUser(router, tt.ip, tt.userAgent, "key-1", tt.body)
+
+			if other.Header().Get(IdempotencyReplayedHeader) != "" || other.Body.String() == victim.Body.String() {
+				t.Fatalf("другому клиенту воспроизведен чужой ответ: %s", other.Body.String())
+			}
+			if *calls != 2 {
+				t.Fatalf("обработчик вызван %d раз, ожидалось 2", *calls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyRejectsAnonymousKeyWithOtherBody(t *testing.T) {
+	router, calls := newIdempotencyRouter(newMemoryIdempotencyStore())
+
+	postUser(router, "192.0.2.1", "app/1.0", "key-1", `{"username":"alice","email":"alice@example.com"}`)
+	other := postUser(router, "192.0.2.1", "app/1.0", "key-1", `{"username":"bob","email":"bob@example.com"}`)
+
+	if other.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("повтор ключа с другим телом: %d %s, ожидался 422", other.Code, other.Body.String())
+	}
+	if *calls != 1 {
+		t.Fatalf("обработчик вызван %d раз, ожидался один", *calls)
+	}
+}
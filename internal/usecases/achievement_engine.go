@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+// AchievementEngine выдает пользователям значки, условия которых выполнены
+type AchievementEngine struct {
+	postgres PostgreSQLAdapter
+	badges   []domain.Badge
+	location *time.Location
+}
+
+// NewAchievementEngine создает движок значков с набором определений badges.
+// location задает часовой пояс, в котором начинается неделя для значков за место в таблице лидеров.
+func NewAchievementEngine(postgres PostgreSQLAdapter, badges []domain.Badge, location *time.Location) *AchievementEngine {
+	return &AchievementEngine{
+		postgres: postgres,
+		badges:   badges,
+		location: location,
+	}
+}
+
+// Badges возвращает определения значков
+func (e *AchievementEngine) Badges() []domain.Badge {
+	return e.badges
+}
+
+// Evaluate проверяет условия значков, которых еще нет у пользователя, и выдает значки
+// с выполненными условиями. Возвращает выданные значки.
+func (e *AchievementEngine) Evaluate(ctx context.Context, userID domain.UserID) ([]domain.Badge, error) {
+	owned, err := e.postgres.GetUserBadges(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении значков: %w", err)
+	}
+
+	ownedKeys := make(map[string]bool, len(owned))
+	for _, badge := range owned {
+		ownedKeys[badge.BadgeKey] = true
+	}
+
+	var pending []domain.Badge
+	for _, badge := range e.badges {
+		if !ownedKeys[badge.Key] {
+			pending = append(pending, badge)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	stats, err := e.stats(ctx, userID, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var awarded []domain.Badge
+	for _, badge := range pending {
+		if !badge.IsEarned(stats) {
+			continue
+		}
+
+		created, err := e.postgres.AwardBadge(ctx, domain.NewUserBadge(userID, badge, now))
+		if err != nil {
+			return awarded, fmt.Errorf("ошибка при выдаче значка %s: %w", badge.Key, err)
+		}
+		if created {
+			awarded = append(awarded, badge)
+		}
+	}
+
+	return awarded, nil
+}
+
+// EvaluateCommitted проверяет значки пользователей после фиксации транзакции, в которой
+// изменились их показатели. Ошибка только записывается в журнал: изменения уже сохранены,
+// а значок будет выдан при следующей проверке или пересчете.
+func (e *AchievementEngine) EvaluateCommitted(ctx context.Context, userIDs ...domain.UserID) {
+	for _, userID := range userIDs {
+		if _, err := e.Evaluate(ctx, userID); err != nil {
+			slog.Error("Ошибка проверки значков пользователя", "user_id", userID.String(), "error", err)
+		}
+	}
+}
+
+// EvaluateCommittedWithReferrer проверяет после фиксации транзакции значки пользователя
+// и его реферера. Выполненное пользователем задание может завершить его реферальную связь
+// и увеличить число приглашений и баланс реферера.
+func (e *AchievementEngine) EvaluateCommittedWithReferrer(ctx context.Context, userID domain.UserID) {
+	userIDs := []domain.UserID{userID}
+
+	referral, err := e.postgres.GetReferralByReferredUserID(ctx, userID)
+	if err != nil {
+		slog.Error("Ошибка проверки значков реферера", "user_id", userID.String(), "error", err)
+	} else if referral != nil && referral.Status == domain.ReferralVested {
+		userIDs = append(userIDs, referral.ReferrerID)
+	}
+
+	e.EvaluateCommitted(ctx, userIDs...)
+}
+
+// stats загружает показатели пользователя, которые нужны для проверки значков pending.
+// Для места в таблице лидеров недели загружается только верх таблицы до наибольшего порога.
+func (e *AchievementEngine) stats(ctx context.Context, userID domain.UserID, pending []domain.Badge) (domain.AchievementStats, error) {
+	needed := make(map[domain.BadgeCriterion]bool)
+	weeklyRankLimit := 0
+	for _, badge := range pending {
+		needed[badge.Criterion] = true
+		if badge.Criterion == domain.BadgeCriterionWeeklyRank {
+			weeklyRankLimit = max(weeklyRankLimit, badge.Threshold)
+		}
+	}
+
+	var stats domain.AchievementStats
+
+	if needed[domain.BadgeCriterionCompletedTasks] {
+		tasks, err := e.postgres.GetTasksByUserID(ctx, userID)
+		if err != nil {
+			return stats, fmt.Errorf("ошибка при получении заданий: %w", err)
+		}
+		stats.CompletedTasks = len(tasks)
+	}
+
+	if needed[domain.BadgeCriterionReferrals] {
+		referrals, err := e.postgres.CountReferralsByReferrerID(ctx, userID)
+		if err != nil {
+			return stats, fmt.Errorf("ошибка при получении рефералов: %w", err)
+		}
+		stats.Referrals = referrals
+	}
+
+	if needed[domain.BadgeCriterionBalance] {
+		user, err := e.postgres.GetUserByID(ctx, userID)
+		if err != nil {
+			return stats, err
+		}
+		if user == nil {
+			return stats, domain.ErrUserNotFound
+		}
+		stats.Balance = user.Balance.Value()
+	}
+
+	if weeklyRankLimit > 0 {
+		since, _ := LeaderboardWeekly.Since(time.Now(), e.location)
+		entries, err := e.postgres.GetPeriodLeaderboard(ctx, storedTime(since), time.Time{}, weeklyRankLimit, nil)
+		if err != nil {
+			return stats, fmt.Errorf("ошибка при получении таблицы лидеров недели: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.UserID == userID.String() {
+				stats.WeeklyRank = entry.Rank
+				break
+			}
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ApproveSubmissionUseCase struct {
+	postgres     PostgreSQLAdapter
+	commissions  *CommissionPayer
+	vester       *ReferralVester
+	achievements *AchievementEngine
+}
+
+func NewApproveSubmissionUseCase(postgres PostgreSQLAdapter, commissions *CommissionPayer, vester *ReferralVester, achievements *AchievementEngine) *ApproveSubmissionUseCase {
+	return &ApproveSubmissionUseCase{
+		postgres:     postgres,
+		commissions:  commissions,
+		vester:       vester,
+		achievements: achievements,
+	}
+}
+
+// Execute одобряет заявку и начисляет поинты за задание, в том числе рефереру за invite_friend
+func (uc *ApproveSubmissionUseCase) Execute(ctx context.Context, moderatorIDStr, submissionIDStr string) (dto.SubmissionOutput, error) {
+	moderatorID, err := domain.UserIDFromString(moderatorIDStr)
+	if err != nil {
+		return dto.SubmissionOutput{}, err
+	}
+
+	submission, err := getSubmission(ctx, uc.postgres, submissionIDStr)
+	if err != nil {
+		return dto.SubmissionOutput{}, err
+	}
+
+	if err := submission.Approve(moderatorID, time.Now()); err != nil {
+		return dto.SubmissionOutput{}, err
+	}
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.postgres.LockUser(ctx, submission.UserID); err != nil {
+			return err
+		}
+
+		task, err := submission.CompletedTask()
+		if err != nil {
+			return err
+		}
+
+		if _, err := creditTaskCompletion(ctx, uc.postgres, uc.commissions, uc.vester, task); err != nil {
+			return err
+		}
+
+		submission.UserTaskID = &task.ID
+		if err := uc.postgres.UpdateSubmissionReview(ctx, submission); err != nil {
+			return fmt.Errorf("ошибка при сохранении решения по заявке: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return dto.SubmissionOutput{}, err
+	}
+
+	uc.achievements.EvaluateCommittedWithReferrer(ctx, submission.UserID)
+	return submissionToDTO(submission), nil
+}
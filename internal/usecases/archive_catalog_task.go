@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/dto"
+)
+
+type ArchiveCatalogTaskUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewArchiveCatalogTaskUseCase(postgres PostgreSQLAdapter) *ArchiveCatalogTaskUseCase {
+	return &ArchiveCatalogTaskUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute выводит задание из каталога. История выполнений задания сохраняется.
+func (uc *ArchiveCatalogTaskUseCase) Execute(ctx context.Context, key string) (dto.CatalogTaskOutput, error) {
+	task, err := getCatalogTask(ctx, uc.postgres, key)
+	if err != nil {
+		return dto.CatalogTaskOutput{}, err
+	}
+
+	if err := task.Archive(time.Now()); err != nil {
+		return dto.CatalogTaskOutput{}, err
+	}
+
+	if err := uc.postgres.UpdateCatalogTask(ctx, task); err != nil {
+		return dto.CatalogTaskOutput{}, fmt.Errorf("ошибка при архивации задания: %w", err)
+	}
+
+	return catalogTaskToDTO(task), nil
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+)
+
+// backfillBadgesBatchSize число пользователей, загружаемых за один запрос
+const backfillBadgesBatchSize = 500
+
+type BackfillBadgesUseCase struct {
+	postgres     PostgreSQLAdapter
+	achievements *AchievementEngine
+}
+
+func NewBackfillBadgesUseCase(postgres PostgreSQLAdapter, achievements *AchievementEngine) *BackfillBadgesUseCase {
+	return &BackfillBadgesUseCase{
+		postgres:     postgres,
+		achievements: achievements,
+	}
+}
+
+// Execute проверяет значки всех пользователей и выдает значки с выполненными условиями.
+// Значки за место в таблице лидеров проверяются по текущей неделе.
+// Возвращает число проверенных пользователей и число выданных значков.
+func (uc *BackfillBadgesUseCase) Execute(ctx context.Context) (users, awarded int, err error) {
+	var after *domain.UserID
+	for {
+		userIDs, err := uc.postgres.ListUserIDs(ctx, after, backfillBadgesBatchSize)
+		if err != nil {
+			return users, awarded, fmt.Errorf("ошибка при получении пользователей: %w", err)
+		}
+
+		for _, userID := range userIDs {
+			badges, err := uc.achievements.Evaluate(ctx, userID)
+			if err != nil {
+				return users, awarded, fmt.Errorf("ошибка при проверке значков пользователя %s: %w", userID, err)
+			}
+			users++
+			awarded += len(badges)
+		}
+
+		if len(userIDs) < backfillBadgesBatchSize {
+			return users, awarded, nil
+		}
+		after = &userIDs[len(userIDs)-1]
+	}
+}
@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+)
+
+// CommissionPayer начисляет вышестоящим реферерам комиссии с поинтов, заработанных пользователем
+type CommissionPayer struct {
+	postgres PostgreSQLAdapter
+	plan     domain.CommissionPlan
+}
+
+func NewCommissionPayer(postgres PostgreSQLAdapter, plan domain.CommissionPlan) *CommissionPayer {
+	return &CommissionPayer{
+		postgres: postgres,
+		plan:     plan,
+	}
+}
+
+// Pay начисляет комиссии с записи журнала entry всем реферерам цепочки в пределах глубины плана.
+// Повторный вызов для той же записи не начисляет комиссии второй раз.
+// Должен вызываться внутри транзакции, в которой создана entry.
+func (p *CommissionPayer) Pay(ctx context.Context, entry domain.LedgerEntry) error {
+	if p.plan.Depth() == 0 || !entry.EarnsCommission() {
+		return nil
+	}
+
+	chain, err := p.postgres.GetReferralChain(ctx, entry.UserID, p.plan.Depth())
+	if err != nil {
+		return fmt.Errorf("ошибка при получении цепочки рефереров: %w", err)
+	}
+
+	for i, beneficiaryID := range chain {
+		commission, ok, err := domain.NewReferralCommission(p.plan, beneficiaryID, i+1, entry)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		created, err := p.postgres.CreateReferralCommission(ctx, commission)
+		if err != nil {
+			return fmt.Errorf("ошибка при сохранении комиссии: %w", err)
+		}
+		if !created {
+			continue
+		}
+
+		commissionEntry, err := commission.LedgerEntry()
+		if err != nil {
+			return err
+		}
+
+		if _, err := p.postgres.AddLedgerEntry(ctx, commissionEntry); err != nil {
+			return fmt.Errorf("ошибка при начислении комиссии: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Plan возвращает действующий план комиссий
+func (p *CommissionPayer) Plan() domain.CommissionPlan {
+	return p.plan
+}
@@ -1,24 +1,52 @@
 package usecases
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
 
 	"user-rewards-api/internal/domain"
 	"user-rewards-api/internal/dto"
 )
 
+// maxScreenshotSize максимальный размер загружаемого скриншота
+const maxScreenshotSize = 5 << 20
+
+// screenshotContentTypes допустимые форматы скриншотов
+var screenshotContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
 type CompleteTaskUseCase struct {
-	postgres PostgreSQLAdapter
+	postgres     PostgreSQLAdapter
+	proofStorage ProofStorage
+	commissions  *CommissionPayer
+	vester       *ReferralVester
+	achievements *AchievementEngine
+	location     *time.Location
 }
 
-func NewCompleteTaskUseCase(postgres PostgreSQLAdapter) *CompleteTaskUseCase {
+// NewCompleteTaskUseCase создает use case выполнения задания.
+// location задает часовой пояс, в котором сбрасываются ежедневные и еженедельные задания.
+func NewCompleteTaskUseCase(postgres PostgreSQLAdapter, proofStorage ProofStorage, commissions *CommissionPayer, vester *ReferralVester, achievements *AchievementEngine, location *time.Location) *CompleteTaskUseCase {
 	return &CompleteTaskUseCase{
-		postgres: postgres,
+		postgres:     postgres,
+		proofStorage: proofStorage,
+		commissions:  commissions,
+		vester:       vester,
+		achievements: achievements,
+		location:     location,
 	}
 }
 
-// Execute выполняет задание пользователя
+// Execute выполняет задание пользователя.
+// Если задание требует подтверждения, поинты не начисляются, а создается заявка на проверку модератором.
 func (uc *CompleteTaskUseCase) Execute(ctx context.Context, userIDStr string, input dto.CompleteTaskInput) (dto.CompleteTaskOutput, error) {
 	userID, err := domain.UserIDFromString(userIDStr)
 	if err != nil {
@@ -30,71 +58,251 @@ func (uc *CompleteTaskUseCase) Execute(ctx context.Context, userIDStr string, in
 		return dto.CompleteTaskOutput{}, err
 	}
 
-	user, err := uc.postgres.GetUserByID(ctx, userID)
+	user, catalogTask, err := uc.load(ctx, userID, taskType)
 	if err != nil {
 		return dto.CompleteTaskOutput{}, err
 	}
-	if user == nil {
-		return dto.CompleteTaskOutput{}, domain.ErrUserNotFound
+
+	switch {
+	case catalogTask.ProofType == domain.ProofTypeExternal:
+		return dto.CompleteTaskOutput{}, fmt.Errorf("%w: %s", domain.ErrExternalVerificationOnly, taskType)
+	case catalogTask.ProofType.RequiresReview():
+		return uc.submit(ctx, user, catalogTask, input)
+	}
+
+	output, err := uc.complete(ctx, userID, catalogTask)
+	if err != nil {
+		return dto.CompleteTaskOutput{}, err
+	}
+
+	uc.achievements.EvaluateCommittedWithReferrer(ctx, userID)
+	return output, nil
+}
+
+// completeVerified выполняет задание, подтвержденное партнерским сервисом, без проверки модератором.
+// Партнер может подтверждать только задания с типом подтверждения external.
+// Значки не проверяются: вызывающий должен проверить их после фиксации своей транзакции.
+func (uc *CompleteTaskUseCase) completeVerified(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (dto.CompleteTaskOutput, error) {
+	_, catalogTask, err := uc.load(ctx, userID, taskType)
+	if err != nil {
+		return dto.CompleteTaskOutput{}, err
 	}
 
-	existingTask, err := uc.postgres.GetTaskByUserAndType(ctx, userID, taskType)
+	if catalogTask.ProofType != domain.ProofTypeExternal {
+		return dto.CompleteTaskOutput{}, fmt.Errorf("%w: задание %s не подтверждается партнером", domain.ErrInvalidTaskType, taskType)
+	}
+
+	return uc.complete(ctx, userID, catalogTask)
+}
+
+// load получает пользователя и доступное для выполнения задание каталога
+func (uc *CompleteTaskUseCase) load(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (domain.User, domain.Task, error) {
+	catalogTask, err := uc.postgres.GetCatalogTask(ctx, taskType)
 	if err != nil {
-		return dto.CompleteTaskOutput{}, fmt.Errorf("ошибка при проверке задания: %w", err)
+		return domain.User{}, domain.Task{}, fmt.Errorf("ошибка при получении задания из каталога: %w", err)
 	}
-	if existingTask != nil {
-		return dto.CompleteTaskOutput{}, domain.ErrTaskAlreadyExists
+	if catalogTask == nil || !catalogTask.IsAvailable() {
+		return domain.User{}, domain.Task{}, fmt.Errorf("%w: %s", domain.ErrInvalidTaskType, taskType)
+	}
+
+	user, err := uc.postgres.GetUserByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, domain.Task{}, err
+	}
+	if user == nil {
+		return domain.User{}, domain.Task{}, domain.ErrUserNotFound
 	}
 
+	return *user, *catalogTask, nil
+}
+
+// complete засчитывает выполнение задания и начисляет поинты
+func (uc *CompleteTaskUseCase) complete(ctx context.Context, userID domain.UserID, catalogTask domain.Task) (dto.CompleteTaskOutput, error) {
 	var task domain.UserTask
 	var newBalance domain.Balance
 
-	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
-		task, err = domain.NewUserTask(userID, taskType)
-		if err != nil {
+	err := uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.checkAvailability(ctx, userID, catalogTask); err != nil {
 			return err
 		}
 
-		if err := uc.postgres.CreateTask(ctx, task); err != nil {
-			return fmt.Errorf("ошибка при создании задания: %w", err)
+		var err error
+		task, err = domain.NewUserTask(userID, catalogTask)
+		if err != nil {
+			return err
 		}
 
-		newBalance = user.Balance.Add(taskType.GetPoints())
-		if err := uc.postgres.UpdateUserBalance(ctx, userID, newBalance); err != nil {
-			return fmt.Errorf("ошибка при обновлении баланса: %w", err)
+		newBalance, err = creditTaskCompletion(ctx, uc.postgres, uc.commissions, uc.vester, task)
+		return err
+	})
+
+	if err != nil {
+		return dto.CompleteTaskOutput{}, err
+	}
+
+	return dto.CompleteTaskOutput{
+		Status:     "completed",
+		TaskID:     task.ID.String(),
+		TaskType:   catalogTask.Key.String(),
+		Points:     task.Points,
+		NewBalance: newBalance.Value(),
+	}, nil
+}
+
+// submit создает заявку на проверку выполнения задания.
+// Скриншот сохраняется в хранилище до начала транзакции и удаляется, если заявку создать не удалось.
+func (uc *CompleteTaskUseCase) submit(ctx context.Context, user domain.User, catalogTask domain.Task, input dto.CompleteTaskInput) (dto.CompleteTaskOutput, error) {
+	proof := input.Proof
+
+	if catalogTask.ProofType == domain.ProofTypeScreenshot {
+		key, err := uc.storeScreenshot(ctx, input.Screenshot)
+		if err != nil {
+			return dto.CompleteTaskOutput{}, err
 		}
+		proof = key
+	}
 
-		if taskType == domain.TaskTypeInviteFriend {
-			referral, err := uc.postgres.GetReferralByReferredUserID(ctx, userID)
-			if err != nil {
-				return fmt.Errorf("ошибка при проверке реферальной связи: %w", err)
+	submission, err := domain.NewTaskSubmission(user.ID, catalogTask, proof)
+	if err == nil {
+		err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := uc.checkAvailability(ctx, user.ID, catalogTask); err != nil {
+				return err
 			}
 
-			if referral != nil {
-				referrer, err := uc.postgres.GetUserByID(ctx, referral.ReferrerID)
-				if err != nil {
-					return fmt.Errorf("ошибка при получении реферера: %w", err)
-				}
-				if referrer != nil {
-					referrerNewBalance := referrer.Balance.Add(taskType.GetPoints())
-					if err := uc.postgres.UpdateUserBalance(ctx, referral.ReferrerID, referrerNewBalance); err != nil {
-						return fmt.Errorf("ошибка при обновлении баланса реферера: %w", err)
-					}
-				}
+			if err := uc.postgres.CreateSubmission(ctx, submission); err != nil {
+				return fmt.Errorf("ошибка при создании заявки: %w", err)
 			}
-		}
-
-		return nil
-	})
+			return nil
+		})
+	}
 
 	if err != nil {
+		if catalogTask.ProofType == domain.ProofTypeScreenshot {
+			if deleteErr := uc.proofStorage.Delete(context.WithoutCancel(ctx), proof); deleteErr != nil {
+				slog.Error("Ошибка удаления файла подтверждения", "key", proof, "error", deleteErr)
+			}
+		}
 		return dto.CompleteTaskOutput{}, err
 	}
 
 	return dto.CompleteTaskOutput{
-		TaskID:     task.ID.String(),
-		TaskType:   taskType.String(),
-		Points:     taskType.GetPoints(),
-		NewBalance: newBalance.Value(),
+		Status:       submission.Status.String(),
+		SubmissionID: submission.ID.String(),
+		TaskType:     submission.TaskType.String(),
+		Points:       submission.Points,
+		NewBalance:   user.Balance.Value(),
 	}, nil
 }
+
+// checkAvailability блокирует пользователя и проверяет, можно ли сейчас выполнить задание.
+// Должен вызываться внутри транзакции.
+func (uc *CompleteTaskUseCase) checkAvailability(ctx context.Context, userID domain.UserID, catalogTask domain.Task) error {
+	if err := uc.postgres.LockUser(ctx, userID); err != nil {
+		return err
+	}
+
+	completions, lastCompletedAt, err := uc.postgres.GetTaskCompletionStats(ctx, userID, catalogTask.Key)
+	if err != nil {
+		return fmt.Errorf("ошибка при проверке задания: %w", err)
+	}
+
+	return catalogTask.Repeat.CheckAvailability(completions, lastCompletedAt, time.Now(), uc.location)
+}
+
+// storeScreenshot проверяет формат и размер скриншота и сохраняет его в хранилище.
+// Формат определяется по содержимому файла, а не по имени или заголовкам запроса.
+func (uc *CompleteTaskUseCase) storeScreenshot(ctx context.Context, upload *dto.ProofUpload) (string, error) {
+	if upload == nil || upload.Content == nil {
+		return "", fmt.Errorf("%w: задание требует загрузки скриншота", domain.ErrInvalidProof)
+	}
+	if upload.Size <= 0 || upload.Size > maxScreenshotSize {
+		return "", fmt.Errorf("%w: размер скриншота должен быть не больше %d МБ", domain.ErrInvalidProof, maxScreenshotSize>>20)
+	}
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(upload.Content, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("ошибка чтения скриншота: %w", err)
+	}
+	header = header[:n]
+
+	contentType := http.DetectContentType(header)
+	if !screenshotContentTypes[contentType] {
+		return "", fmt.Errorf("%w: поддерживаются скриншоты PNG, JPEG и WebP", domain.ErrInvalidProof)
+	}
+
+	content := io.MultiReader(bytes.NewReader(header), io.LimitReader(upload.Content, maxScreenshotSize-int64(n)))
+
+	key, err := uc.proofStorage.Save(ctx, contentType, content)
+	if err != nil {
+		return "", fmt.Errorf("ошибка сохранения скриншота: %w", err)
+	}
+	return key, nil
+}
+
+// creditTaskCompletion сохраняет выполненное задание и начисляет за него поинты.
+// За задание invite_friend поинты также получает реферер пользователя: сразу, если бонусы по связи
+// уже начислены, иначе при начислении бонусов в ReferralVester. Со всех заработанных поинтов
+// начисляются комиссии по реферальной цепочке.
+// Выполненное задание может выполнить условия начисления реферальных бонусов пользователя.
+// Должен вызываться внутри транзакции, возвращает новый баланс пользователя.
+func creditTaskCompletion(ctx context.Context, postgres PostgreSQLAdapter, commissions *CommissionPayer, vester *ReferralVester, task domain.UserTask) (domain.Balance, error) {
+	if err := postgres.CreateTask(ctx, task); err != nil {
+		return domain.Balance{}, fmt.Errorf("ошибка при создании задания: %w", err)
+	}
+
+	// Состояние связи проверяется до EvaluateUser: если связь начислится этим заданием,
+	// поинты за него рефереру начислит ReferralVester вместе с бонусами
+	var vestedReferral *domain.Referral
+	if task.TaskType == domain.TaskTypeInviteFriend {
+		referral, err := postgres.GetReferralByReferredUserID(ctx, task.UserID)
+		if err != nil {
+			return domain.Balance{}, fmt.Errorf("ошибка при проверке реферальной связи: %w", err)
+		}
+		if referral != nil && referral.Status == domain.ReferralVested {
+			vestedReferral = referral
+		}
+	}
+
+	// Бонус за выполненные условия начисляется до поинтов за задание,
+	// чтобы возвращаемый баланс учитывал оба начисления
+	if err := vester.EvaluateUser(ctx, task.UserID); err != nil {
+		return domain.Balance{}, err
+	}
+
+	entry, err := domain.NewLedgerEntry(task.UserID, task.Points, domain.LedgerSourceTask, task.ID.String())
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	newBalance, err := postgres.AddLedgerEntry(ctx, entry)
+	if err != nil {
+		return domain.Balance{}, fmt.Errorf("ошибка при начислении поинтов: %w", err)
+	}
+
+	if err := commissions.Pay(ctx, entry); err != nil {
+		return domain.Balance{}, err
+	}
+
+	if vestedReferral != nil {
+		if err := payInviteFriendCredit(ctx, postgres, vestedReferral.ReferrerID, task); err != nil {
+			return domain.Balance{}, err
+		}
+	}
+
+	return newBalance, nil
+}
+
+// payInviteFriendCredit начисляет рефереру поинты за задание invite_friend приглашенного пользователя.
+// Основанием записи служит выполнение задания, поэтому за одно выполнение реферер получает поинты один раз.
+func payInviteFriendCredit(ctx context.Context, postgres PostgreSQLAdapter, referrerID domain.UserID, task domain.UserTask) error {
+	entry, err := domain.NewLedgerEntry(referrerID, task.Points, domain.LedgerSourceReferral, task.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if _, err := postgres.AddLedgerEntry(ctx, entry); err != nil {
+		return fmt.Errorf("ошибка при начислении поинтов рефереру: %w", err)
+	}
+	return nil
+}
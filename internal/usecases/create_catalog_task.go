@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type CreateCatalogTaskUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewCreateCatalogTaskUseCase(postgres PostgreSQLAdapter) *CreateCatalogTaskUseCase {
+	return &CreateCatalogTaskUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute добавляет новое задание в каталог
+func (uc *CreateCatalogTaskUseCase) Execute(ctx context.Context, input dto.CreateCatalogTaskInput) (dto.CatalogTaskOutput, error) {
+	task, err := domain.NewTask(input.Key, input.Title, input.Description, input.Points)
+	if err != nil {
+		return dto.CatalogTaskOutput{}, err
+	}
+
+	if input.Repeat != nil {
+		task.Repeat, err = repeatPolicyFromDTO(*input.Repeat)
+		if err != nil {
+			return dto.CatalogTaskOutput{}, err
+		}
+	}
+
+	if input.ProofType != "" {
+		task.ProofType, err = domain.NewProofType(input.ProofType)
+		if err != nil {
+			return dto.CatalogTaskOutput{}, err
+		}
+	}
+
+	if err := uc.postgres.CreateCatalogTask(ctx, task); err != nil {
+		return dto.CatalogTaskOutput{}, fmt.Errorf("ошибка при создании задания: %w", err)
+	}
+
+	return catalogTaskToDTO(task), nil
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type CreateRewardUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewCreateRewardUseCase(postgres PostgreSQLAdapter) *CreateRewardUseCase {
+	return &CreateRewardUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute добавляет новый товар в магазин наград
+func (uc *CreateRewardUseCase) Execute(ctx context.Context, input dto.CreateRewardInput) (dto.RewardOutput, error) {
+	reward, err := domain.NewReward(input.Title, input.Description, input.Cost, input.Stock,
+		input.PerUserLimit, input.AvailableFrom, input.AvailableUntil)
+	if err != nil {
+		return dto.RewardOutput{}, err
+	}
+
+	if err := uc.postgres.CreateReward(ctx, reward); err != nil {
+		return dto.RewardOutput{}, fmt.Errorf("ошибка при создании товара: %w", err)
+	}
+
+	return rewardToDTO(reward), nil
+}
+
+func rewardToDTO(reward domain.Reward) dto.RewardOutput {
+	return dto.RewardOutput{
+		ID:             reward.ID.String(),
+		Title:          reward.Title,
+		Description:    reward.Description,
+		Cost:           reward.Cost,
+		Stock:          reward.Stock,
+		PerUserLimit:   reward.PerUserLimit,
+		AvailableFrom:  reward.AvailableFrom,
+		AvailableUntil: reward.AvailableUntil,
+		Active:         reward.Active,
+		CreatedAt:      reward.CreatedAt,
+	}
+}
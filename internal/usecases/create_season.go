@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type CreateSeasonUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewCreateSeasonUseCase(postgres PostgreSQLAdapter) *CreateSeasonUseCase {
+	return &CreateSeasonUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute создает сезон. Сезоны не могут пересекаться по времени,
+// чтобы каждый заработанный поинт учитывался не более чем в одном сезоне.
+func (uc *CreateSeasonUseCase) Execute(ctx context.Context, input dto.CreateSeasonInput) (dto.SeasonOutput, error) {
+	season, err := domain.NewSeason(input.Name, input.StartsAt, input.EndsAt)
+	if err != nil {
+		return dto.SeasonOutput{}, err
+	}
+
+	// Границы сезона сравниваются с created_at начислений, поэтому хранятся в часовом поясе процесса
+	stored := season
+	stored.StartsAt, stored.EndsAt = storedTime(season.StartsAt), storedTime(season.EndsAt)
+
+	err = uc.postgres.WithTransaction(ctx, func(txCtx context.Context) error {
+		overlaps, err := uc.postgres.HasOverlappingSeason(txCtx, stored.StartsAt, stored.EndsAt)
+		if err != nil {
+			return fmt.Errorf("ошибка при проверке пересечения сезонов: %w", err)
+		}
+		if overlaps {
+			return domain.ErrSeasonOverlaps
+		}
+
+		if err := uc.postgres.CreateSeason(txCtx, stored); err != nil {
+			return fmt.Errorf("ошибка при создании сезона: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.SeasonOutput{}, err
+	}
+
+	return seasonToDTO(season, time.Now()), nil
+}
+
+func seasonToDTO(season domain.Season, now time.Time) dto.SeasonOutput {
+	return dto.SeasonOutput{
+		ID:          season.ID.String(),
+		Name:        season.Name,
+		StartsAt:    season.StartsAt,
+		EndsAt:      season.EndsAt,
+		Status:      string(season.Status(now)),
+		FinalizedAt: season.FinalizedAt,
+	}
+}
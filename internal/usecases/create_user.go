@@ -2,24 +2,31 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
 
 	"user-rewards-api/internal/domain"
 	"user-rewards-api/internal/dto"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type CreateUserUseCase struct {
-	postgres  PostgreSQLAdapter
-	jwtSecret string
+	postgres            PostgreSQLAdapter
+	tokenIssuer         *TokenIssuer
+	vester              *ReferralVester
+	achievements        *AchievementEngine
+	strictReferralCodes bool
 }
 
-func NewCreateUserUseCase(postgres PostgreSQLAdapter, jwtSecret string) *CreateUserUseCase {
+// NewCreateUserUseCase создает use case регистрации пользователя.
+// strictReferralCodes определяет, отклонять ли регистрацию с неверным реферальным кодом
+// или регистрировать пользователя без реферера.
+func NewCreateUserUseCase(postgres PostgreSQLAdapter, tokenIssuer *TokenIssuer, vester *ReferralVester, achievements *AchievementEngine, strictReferralCodes bool) *CreateUserUseCase {
 	return &CreateUserUseCase{
-		postgres:  postgres,
-		jwtSecret: jwtSecret,
+		postgres:            postgres,
+		tokenIssuer:         tokenIssuer,
+		vester:              vester,
+		achievements:        achievements,
+		strictReferralCodes: strictReferralCodes,
 	}
 }
 
@@ -42,31 +49,100 @@ func (uc *CreateUserUseCase) Execute(ctx context.Context, input dto.CreateUserIn
 		return dto.CreateUserOutput{}, err
 	}
 
-	if err := uc.postgres.CreateUser(ctx, user); err != nil {
-		return dto.CreateUserOutput{}, fmt.Errorf("ошибка при создании пользователя: %w", err)
+	var referrerID *domain.UserID
+	var referralWarning string
+	if input.ReferralCode != "" {
+		id, err := resolveReferrer(ctx, uc.postgres, input.ReferralCode)
+		switch {
+		case err == nil:
+			referrerID = &id
+		case errors.Is(err, domain.ErrReferrerNotFound) && !uc.strictReferralCodes:
+			referralWarning = "реферальный код не найден и не был применен"
+		case errors.Is(err, domain.ErrReferrerNotFound):
+			return dto.CreateUserOutput{}, fmt.Errorf("%w: реферер не найден", domain.ErrInvalidReferralCode)
+		default:
+			return dto.CreateUserOutput{}, err
+		}
 	}
 
-	token, err := uc.generateJWT(user.ID.String())
+	fingerprint := clientFingerprint(input.Client)
+
+	var tokens dto.TokenOutput
+	var referralCode domain.ReferralCode
+	var referral *domain.Referral
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.postgres.CreateUser(ctx, user); err != nil {
+			return fmt.Errorf("ошибка при создании пользователя: %w", err)
+		}
+
+		event := domain.NewClientEvent(user.ID, domain.ClientEventSignup, fingerprint)
+		if err := uc.postgres.RecordClientEvent(ctx, event); err != nil {
+			return fmt.Errorf("ошибка при сохранении отпечатка клиента: %w", err)
+		}
+
+		referralCode, err = assignReferralCode(ctx, uc.postgres, user.ID)
+		if err != nil {
+			return err
+		}
+
+		if referrerID != nil {
+			created, err := uc.vester.Create(ctx, *referrerID, user.ID, fingerprint)
+			if err != nil {
+				return err
+			}
+			referral = &created
+		}
+
+		tokens, err = uc.tokenIssuer.IssueTokens(ctx, user)
+		return err
+	})
+
 	if err != nil {
-		return dto.CreateUserOutput{}, fmt.Errorf("ошибка при генерации токена: %w", err)
+		return dto.CreateUserOutput{}, err
+	}
+
+	output := dto.CreateUserOutput{
+		UserID:          user.ID.String(),
+		Username:        user.Username.String(),
+		Email:           user.Email.String(),
+		Balance:         user.Balance.Value(),
+		ReferralCode:    referralCode.Code,
+		ReferralWarning: referralWarning,
+		AccessToken:     tokens.AccessToken,
+		RefreshToken:    tokens.RefreshToken,
 	}
+	if referral != nil {
+		output.ReferrerID = referral.ReferrerID.String()
+		output.ReferralStatus = string(referral.Status)
+		output.ReferralBonus = referral.BonusPoints
 
-	return dto.CreateUserOutput{
-		UserID:      user.ID.String(),
-		Username:    user.Username.String(),
-		Email:       user.Email.String(),
-		AccessToken: token,
-	}, nil
+		uc.achievements.EvaluateCommitted(ctx, referral.ReferrerID, user.ID)
+	}
+
+	return output, nil
 }
 
-// generateJWT генерирует JWT токен для пользователя
-func (uc *CreateUserUseCase) generateJWT(userID string) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(),
-		"iat":     time.Now().Unix(),
+// maxReferralCodeAttempts число попыток сгенерировать незанятый реферальный код
+const maxReferralCodeAttempts = 5
+
+// assignReferralCode генерирует пользователю реферальный код, повторяя попытку,
+// если случайный код совпал с уже занятым
+func assignReferralCode(ctx context.Context, postgres PostgreSQLAdapter, userID domain.UserID) (domain.ReferralCode, error) {
+	for attempt := 0; attempt < maxReferralCodeAttempts; attempt++ {
+		code, err := domain.NewGeneratedReferralCode(userID)
+		if err != nil {
+			return domain.ReferralCode{}, err
+		}
+
+		created, err := postgres.CreateReferralCode(ctx, code)
+		if err != nil {
+			return domain.ReferralCode{}, fmt.Errorf("ошибка при создании реферального кода: %w", err)
+		}
+		if created {
+			return code, nil
+		}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(uc.jwtSecret))
+	return domain.ReferralCode{}, fmt.Errorf("не удалось сгенерировать незанятый реферальный код за %d попыток", maxReferralCodeAttempts)
 }
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+// expirePointsBatchSize число пользователей, загружаемых за один запрос
+const expirePointsBatchSize = 500
+
+type ExpirePointsUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewExpirePointsUseCase(postgres PostgreSQLAdapter) *ExpirePointsUseCase {
+	return &ExpirePointsUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute списывает непотраченные остатки сгоревших партий поинтов.
+// Перед списанием срок партий, разложенных миграцией по политике по умолчанию,
+// приводится к действующей политике. Для каждой партии в журнал записывается отдельное списание. Возвращает число
+// пользователей, у которых сгорели поинты, и общее число сгоревших поинтов.
+func (uc *ExpirePointsUseCase) Execute(ctx context.Context) (users, points int, err error) {
+	if _, err := uc.postgres.AlignBackfilledPointLots(ctx); err != nil {
+		return 0, 0, fmt.Errorf("ошибка при пересчете срока действия партий поинтов: %w", err)
+	}
+
+	now := time.Now()
+	for {
+		userIDs, err := uc.postgres.ListUsersWithExpiredPoints(ctx, now, expirePointsBatchSize)
+		if err != nil {
+			return users, points, fmt.Errorf("ошибка при получении пользователей со сгоревшими поинтами: %w", err)
+		}
+
+		for _, userID := range userIDs {
+			expired, err := uc.expire(ctx, userID, now)
+			if err != nil {
+				return users, points, fmt.Errorf("ошибка при списании сгоревших поинтов пользователя %s: %w", userID, err)
+			}
+			if expired > 0 {
+				users++
+				points += expired
+			}
+		}
+
+		// Обработанные пользователи больше не попадают в выборку, поэтому курсор не нужен
+		if len(userIDs) < expirePointsBatchSize {
+			return users, points, nil
+		}
+	}
+}
+
+// expire списывает сгоревшие к моменту now поинты одного пользователя
+func (uc *ExpirePointsUseCase) expire(ctx context.Context, userID domain.UserID, now time.Time) (int, error) {
+	expired := 0
+	err := uc.postgres.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Блокировка пользователя не дает списанию расходовать партии, которые сейчас сгорают
+		if err := uc.postgres.LockUser(txCtx, userID); err != nil {
+			return err
+		}
+
+		lots, err := uc.postgres.ExpirePointLots(txCtx, userID, now)
+		if err != nil {
+			return err
+		}
+
+		for _, lot := range lots {
+			entry, err := lot.ExpirationEntry()
+			if err != nil {
+				return err
+			}
+			if _, err := uc.postgres.AddLedgerEntry(txCtx, entry); err != nil {
+				return fmt.Errorf("ошибка при записи сгорания поинтов: %w", err)
+			}
+			expired += lot.Remaining
+		}
+		return nil
+	})
+	return expired, err
+}
@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type FinalizeSeasonsUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewFinalizeSeasonsUseCase(postgres PostgreSQLAdapter) *FinalizeSeasonsUseCase {
+	return &FinalizeSeasonsUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute сохраняет итоговые таблицы всех завершившихся сезонов и возвращает их число.
+// Сезон, итоги которого уже сохранил другой экземпляр приложения, пропускается.
+func (uc *FinalizeSeasonsUseCase) Execute(ctx context.Context) (int, error) {
+	now := storedTime(time.Now())
+
+	seasons, err := uc.postgres.ListSeasonsToFinalize(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при получении завершившихся сезонов: %w", err)
+	}
+
+	finalized := 0
+	for _, season := range seasons {
+		var done bool
+		err := uc.postgres.WithTransaction(ctx, func(txCtx context.Context) error {
+			var err error
+			done, err = uc.postgres.FinalizeSeason(txCtx, season, now)
+			return err
+		})
+		if err != nil {
+			return finalized, fmt.Errorf("ошибка при сохранении итогов сезона %s: %w", season.ID, err)
+		}
+
+		if done {
+			finalized++
+		}
+	}
+
+	return finalized, nil
+}
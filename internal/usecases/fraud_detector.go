@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+)
+
+// FraudDetector проверяет новые реферальные связи набором правил FraudRule
+// и суммирует найденные признаки в общую оценку
+type FraudDetector struct {
+	rules     []FraudRule
+	holdScore int
+}
+
+// NewFraudDetector создает проверку реферальных связей.
+// Связь с оценкой не ниже holdScore задерживается до решения модератора.
+func NewFraudDetector(holdScore int, rules ...FraudRule) *FraudDetector {
+	return &FraudDetector{
+		rules:     rules,
+		holdScore: holdScore,
+	}
+}
+
+// Assess проверяет связь всеми правилами
+func (d *FraudDetector) Assess(ctx context.Context, check FraudCheck) (domain.FraudAssessment, error) {
+	var assessment domain.FraudAssessment
+	for _, rule := range d.rules {
+		signal, err := rule.Evaluate(ctx, check)
+		if err != nil {
+			return domain.FraudAssessment{}, fmt.Errorf("ошибка при проверке правилом %T: %w", rule, err)
+		}
+		if signal.Score > 0 {
+			assessment.Add(signal)
+		}
+	}
+	return assessment, nil
+}
+
+// ShouldHold проверяет, нужно ли задержать связь с такой оценкой
+func (d *FraudDetector) ShouldHold(assessment domain.FraudAssessment) bool {
+	return assessment.Score > 0 && assessment.Score >= d.holdScore
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+// Вклад правил в оценку реферальной связи
+const (
+	sharedWithReferrerScore    = 60
+	sharedWithOtherUsersScore  = 40
+	referralBurstScore         = 50
+	disposableEmailDomainScore = 40
+)
+
+// SharedFingerprintRule отмечает связь, если приглашенный пользователь пришел с того же
+// IP адреса или устройства, что и реферер, или с адреса, общего для многих аккаунтов
+type SharedFingerprintRule struct {
+	postgres    PostgreSQLAdapter
+	window      time.Duration
+	maxAccounts int
+}
+
+// NewSharedFingerprintRule создает правило общего отпечатка.
+// window задает период, за который сравниваются отпечатки, maxAccounts - сколько
+// других аккаунтов с тем же отпечатком допускается без признака мошенничества.
+func NewSharedFingerprintRule(postgres PostgreSQLAdapter, window time.Duration, maxAccounts int) *SharedFingerprintRule {
+	return &SharedFingerprintRule{
+		postgres:    postgres,
+		window:      window,
+		maxAccounts: maxAccounts,
+	}
+}
+
+func (r *SharedFingerprintRule) Evaluate(ctx context.Context, check FraudCheck) (domain.FraudSignal, error) {
+	if check.Fingerprint.IP == "" {
+		return domain.FraudSignal{}, nil
+	}
+	since := check.Now.Add(-r.window)
+
+	shared, err := r.postgres.UserSharesFingerprint(ctx, check.Referrer.ID, check.Fingerprint, since)
+	if err != nil {
+		return domain.FraudSignal{}, err
+	}
+	if shared {
+		return domain.FraudSignal{
+			Rule:   "shared_fingerprint",
+			Score:  sharedWithReferrerScore,
+			Reason: "IP адрес или устройство совпадает с реферером",
+		}, nil
+	}
+
+	accounts, err := r.postgres.CountUsersSharingFingerprint(ctx, check.Fingerprint, check.Referred.ID, since)
+	if err != nil {
+		return domain.FraudSignal{}, err
+	}
+	if accounts > r.maxAccounts {
+		return domain.FraudSignal{
+			Rule:   "shared_fingerprint",
+			Score:  sharedWithOtherUsersScore,
+			Reason: fmt.Sprintf("IP адрес или устройство использовали еще %d аккаунтов", accounts),
+		}, nil
+	}
+
+	return domain.FraudSignal{}, nil
+}
+
+// ReferralBurstRule отмечает связь, если реферер пригласил слишком много пользователей за короткое время
+type ReferralBurstRule struct {
+	postgres     PostgreSQLAdapter
+	window       time.Duration
+	maxReferrals int
+}
+
+// NewReferralBurstRule создает правило всплеска приглашений: за window реферер
+// может пригласить не больше maxReferrals пользователей без признака мошенничества
+func NewReferralBurstRule(postgres PostgreSQLAdapter, window time.Duration, maxReferrals int) *ReferralBurstRule {
+	return &ReferralBurstRule{
+		postgres:     postgres,
+		window:       window,
+		maxReferrals: maxReferrals,
+	}
+}
+
+func (r *ReferralBurstRule) Evaluate(ctx context.Context, check FraudCheck) (domain.FraudSignal, error) {
+	count, err := r.postgres.CountReferralsByReferrerSince(ctx, check.Referrer.ID, check.Now.Add(-r.window))
+	if err != nil {
+		return domain.FraudSignal{}, err
+	}
+
+	// Проверяемая связь еще не сохранена, поэтому учитывается отдельно
+	if count+1 > r.maxReferrals {
+		return domain.FraudSignal{
+			Rule:   "referral_burst",
+			Score:  referralBurstScore,
+			Reason: fmt.Sprintf("реферер пригласил %d пользователей за %s", count+1, r.window),
+		}, nil
+	}
+
+	return domain.FraudSignal{}, nil
+}
+
+// DisposableEmailRule отмечает связь, если приглашенный пользователь зарегистрирован
+// на адрес одноразовой почты
+type DisposableEmailRule struct {
+	domains map[string]struct{}
+}
+
+// NewDisposableEmailRule создает правило одноразовой почты со списком доменов
+func NewDisposableEmailRule(domains []string) *DisposableEmailRule {
+	set := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		set[strings.ToLower(strings.TrimSpace(d))] = struct{}{}
+	}
+	return &DisposableEmailRule{domains: set}
+}
+
+func (r *DisposableEmailRule) Evaluate(ctx context.Context, check FraudCheck) (domain.FraudSignal, error) {
+	_, emailDomain, ok := strings.Cut(check.Referred.Email.String(), "@")
+	if !ok {
+		return domain.FraudSignal{}, nil
+	}
+
+	emailDomain = strings.ToLower(emailDomain)
+	if _, disposable := r.domains[emailDomain]; disposable {
+		return domain.FraudSignal{
+			Rule:   "disposable_email",
+			Score:  disposableEmailDomainScore,
+			Reason: fmt.Sprintf("одноразовая почта %s", emailDomain),
+		}, nil
+	}
+
+	return domain.FraudSignal{}, nil
+}
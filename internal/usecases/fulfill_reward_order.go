@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type FulfillRewardOrderUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewFulfillRewardOrderUseCase(postgres PostgreSQLAdapter) *FulfillRewardOrderUseCase {
+	return &FulfillRewardOrderUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute отмечает заказ выданным
+func (uc *FulfillRewardOrderUseCase) Execute(ctx context.Context, adminIDStr, orderIDStr string) (dto.RewardOrderOutput, error) {
+	adminID, err := domain.UserIDFromString(adminIDStr)
+	if err != nil {
+		return dto.RewardOrderOutput{}, err
+	}
+
+	order, err := getRewardOrder(ctx, uc.postgres, orderIDStr)
+	if err != nil {
+		return dto.RewardOrderOutput{}, err
+	}
+
+	if err := order.Fulfill(adminID, time.Now()); err != nil {
+		return dto.RewardOrderOutput{}, err
+	}
+
+	fulfilled, err := uc.postgres.FulfillRewardOrder(ctx, order)
+	if err != nil {
+		return dto.RewardOrderOutput{}, fmt.Errorf("ошибка при сохранении выдачи заказа: %w", err)
+	}
+	if !fulfilled {
+		return dto.RewardOrderOutput{}, domain.ErrOrderFulfilled
+	}
+
+	return rewardOrderToDTO(order), nil
+}
@@ -2,47 +2,145 @@ package usecases
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"user-rewards-api/internal/domain"
 	"user-rewards-api/internal/dto"
 )
 
+// defaultLeaderboardLimit размер страницы таблицы лидеров по умолчанию
+const defaultLeaderboardLimit = 100
+
 type GetLeaderboardUseCase struct {
 	postgres PostgreSQLAdapter
+	maxLimit int
+	location *time.Location
 }
 
-func NewGetLeaderboardUseCase(postgres PostgreSQLAdapter) *GetLeaderboardUseCase {
+// NewGetLeaderboardUseCase создает use case получения таблицы лидеров.
+// maxLimit ограничивает размер одной страницы, location задает часовой пояс границ периодов.
+func NewGetLeaderboardUseCase(postgres PostgreSQLAdapter, maxLimit int, location *time.Location) *GetLeaderboardUseCase {
 	return &GetLeaderboardUseCase{
 		postgres: postgres,
+		maxLimit: maxLimit,
+		location: location,
 	}
 }
 
-// Execute выполняет получение таблицы лидеров
-func (uc *GetLeaderboardUseCase) Execute(ctx context.Context, limit int) (dto.GetLeaderboardOutput, error) {
+// Execute выполняет получение страницы таблицы лидеров за период.
+// Таблица за все время строится по балансу, за остальные периоды - по поинтам, заработанным с начала периода.
+// cursor - значение next_cursor из предыдущего ответа, пустая строка означает первую страницу.
+func (uc *GetLeaderboardUseCase) Execute(ctx context.Context, period LeaderboardPeriod, limit int, cursor string) (dto.GetLeaderboardOutput, error) {
 	if limit <= 0 {
-		limit = 100
+		limit = defaultLeaderboardLimit
 	}
-	if limit > 100 {
-		limit = 100
+	if limit > uc.maxLimit {
+		limit = uc.maxLimit
 	}
 
-	entries, err := uc.postgres.GetLeaderboard(ctx, limit)
+	since, windowed := period.Since(time.Now(), uc.location)
+
+	scope := ""
+	if windowed {
+		scope = period.String()
+	}
+
+	var after *LeaderboardCursor
+	if cursor != "" {
+		decoded, err := decodeLeaderboardCursor(cursor, scope)
+		if err != nil {
+			return dto.GetLeaderboardOutput{}, err
+		}
+		after = &decoded
+	}
+
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	var entries []LeaderboardEntry
+	var total int
+	var err error
+	if windowed {
+		entries, err = uc.postgres.GetPeriodLeaderboard(ctx, storedTime(since), time.Time{}, limit+1, after)
+	} else {
+		entries, err = uc.postgres.GetLeaderboard(ctx, limit+1, after)
+	}
 	if err != nil {
 		return dto.GetLeaderboardOutput{}, fmt.Errorf("ошибка при получении таблицы лидеров: %w", err)
 	}
 
-	leaderboardEntries := make([]dto.LeaderboardEntry, len(entries))
-	for i := 0; i < len(entries); i++ {
-		leaderboardEntries[i] = dto.LeaderboardEntry{
-			Rank:     entries[i].Rank,
-			UserID:   entries[i].UserID,
-			Username: entries[i].Username,
-			Balance:  entries[i].Balance,
-		}
+	if windowed {
+		total, err = uc.postgres.CountPeriodLeaderboardUsers(ctx, storedTime(since), time.Time{})
+	} else {
+		total, err = uc.postgres.CountLeaderboardUsers(ctx)
+	}
+	if err != nil {
+		return dto.GetLeaderboardOutput{}, fmt.Errorf("ошибка при подсчете участников таблицы лидеров: %w", err)
+	}
+
+	var nextCursor string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next := entries[len(entries)-1].Cursor()
+		next.Period = scope
+		nextCursor = encodeLeaderboardCursor(next)
+	}
+
+	output := dto.GetLeaderboardOutput{
+		Period:     period.String(),
+		Users:      leaderboardEntriesToDTO(entries),
+		Total:      total,
+		NextCursor: nextCursor,
+	}
+	if windowed {
+		output.Since = &since
+	}
+
+	return output, nil
+}
+
+func leaderboardEntriesToDTO(entries []LeaderboardEntry) []dto.LeaderboardEntry {
+	result := make([]dto.LeaderboardEntry, len(entries))
+	for i := range entries {
+		result[i] = leaderboardEntryToDTO(entries[i])
+	}
+	return result
+}
+
+func leaderboardEntryToDTO(entry LeaderboardEntry) dto.LeaderboardEntry {
+	return dto.LeaderboardEntry{
+		Rank:     entry.Rank,
+		UserID:   entry.UserID,
+		Username: entry.Username,
+		Balance:  entry.Balance,
+	}
+}
+
+// encodeLeaderboardCursor кодирует курсор в непрозрачную для клиента строку
+func encodeLeaderboardCursor(cursor LeaderboardCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeLeaderboardCursor разбирает курсор, полученный от клиента.
+// scope определяет таблицу лидеров, курсор другой таблицы не принимается.
+func decodeLeaderboardCursor(value string, scope string) (LeaderboardCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return LeaderboardCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+
+	var cursor LeaderboardCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.CreatedAt.IsZero() {
+		return LeaderboardCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+	if _, err := domain.UserIDFromString(cursor.UserID); err != nil {
+		return LeaderboardCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+	if cursor.Period != scope {
+		return LeaderboardCursor{}, fmt.Errorf("%w: курсор выдан для другой таблицы лидеров", domain.ErrInvalidPagination)
 	}
 
-	return dto.GetLeaderboardOutput{
-		Users: leaderboardEntries,
-		Total: len(leaderboardEntries),
-	}, nil
+	return cursor, nil
 }
@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type GetReferralCommissionsUseCase struct {
+	postgres    PostgreSQLAdapter
+	commissions *CommissionPayer
+}
+
+func NewGetReferralCommissionsUseCase(postgres PostgreSQLAdapter, commissions *CommissionPayer) *GetReferralCommissionsUseCase {
+	return &GetReferralCommissionsUseCase{
+		postgres:    postgres,
+		commissions: commissions,
+	}
+}
+
+// Execute возвращает доход пользователя от реферальных комиссий по уровням цепочки.
+// В ответ попадают все уровни действующего плана, даже без начислений,
+// и уровни, комиссии по которым начислялись по прежнему плану.
+func (uc *GetReferralCommissionsUseCase) Execute(ctx context.Context, userIDStr string) (dto.GetReferralCommissionsOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.GetReferralCommissionsOutput{}, err
+	}
+
+	user, err := uc.postgres.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.GetReferralCommissionsOutput{}, err
+	}
+	if user == nil {
+		return dto.GetReferralCommissionsOutput{}, domain.ErrUserNotFound
+	}
+
+	summary, err := uc.postgres.GetCommissionSummary(ctx, userID)
+	if err != nil {
+		return dto.GetReferralCommissionsOutput{}, fmt.Errorf("ошибка при получении комиссий: %w", err)
+	}
+
+	plan := uc.commissions.Plan()
+	depth := plan.Depth()
+	for _, level := range summary {
+		depth = max(depth, level.Level)
+	}
+
+	levels := make([]dto.CommissionLevelOutput, depth)
+	for i := range levels {
+		levels[i] = dto.CommissionLevelOutput{
+			Level:       i + 1,
+			RatePercent: float64(plan.Rate(i+1)) / 100,
+		}
+	}
+
+	total := 0
+	for _, level := range summary {
+		output := &levels[level.Level-1]
+		output.Referrals = level.Referrals
+		output.Commissions = level.Commissions
+		output.Points = level.Points
+		total += level.Points
+	}
+
+	return dto.GetReferralCommissionsOutput{
+		UserID:      userID.String(),
+		TotalPoints: total,
+		Levels:      levels,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/dto"
+)
+
+type GetReferralLeaderboardUseCase struct {
+	postgres PostgreSQLAdapter
+	maxLimit int
+	location *time.Location
+}
+
+// NewGetReferralLeaderboardUseCase создает use case получения таблицы лидеров по рефералам.
+// maxLimit ограничивает размер одной страницы, location задает часовой пояс границ периодов.
+func NewGetReferralLeaderboardUseCase(postgres PostgreSQLAdapter, maxLimit int, location *time.Location) *GetReferralLeaderboardUseCase {
+	return &GetReferralLeaderboardUseCase{
+		postgres: postgres,
+		maxLimit: maxLimit,
+		location: location,
+	}
+}
+
+// Execute выполняет получение страницы рефереров, отсортированных по числу приглашенных за период пользователей.
+// cursor - значение next_cursor из предыдущего ответа, пустая строка означает первую страницу.
+func (uc *GetReferralLeaderboardUseCase) Execute(ctx context.Context, period LeaderboardPeriod, limit int, cursor string) (dto.GetReferralLeaderboardOutput, error) {
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	if limit > uc.maxLimit {
+		limit = uc.maxLimit
+	}
+
+	since, windowed := period.Since(time.Now(), uc.location)
+	scope := "referrals:" + period.String()
+
+	var after *LeaderboardCursor
+	if cursor != "" {
+		decoded, err := decodeLeaderboardCursor(cursor, scope)
+		if err != nil {
+			return dto.GetReferralLeaderboardOutput{}, err
+		}
+		after = &decoded
+	}
+
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	entries, err := uc.postgres.GetReferralLeaderboard(ctx, storedTime(since), limit+1, after)
+	if err != nil {
+		return dto.GetReferralLeaderboardOutput{}, fmt.Errorf("ошибка при получении таблицы лидеров по рефералам: %w", err)
+	}
+
+	total, err := uc.postgres.CountReferralLeaderboardUsers(ctx, storedTime(since))
+	if err != nil {
+		return dto.GetReferralLeaderboardOutput{}, fmt.Errorf("ошибка при подсчете участников таблицы лидеров по рефералам: %w", err)
+	}
+
+	var nextCursor string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next := entries[len(entries)-1].Cursor()
+		next.Period = scope
+		nextCursor = encodeLeaderboardCursor(next)
+	}
+
+	users := make([]dto.ReferralLeaderboardEntry, len(entries))
+	for i, entry := range entries {
+		users[i] = dto.ReferralLeaderboardEntry{
+			Rank:      entry.Rank,
+			UserID:    entry.UserID,
+			Username:  entry.Username,
+			Referrals: entry.Balance,
+		}
+	}
+
+	output := dto.GetReferralLeaderboardOutput{
+		Period:     period.String(),
+		Users:      users,
+		Total:      total,
+		NextCursor: nextCursor,
+	}
+	if windowed {
+		output.Since = &since
+	}
+
+	return output, nil
+}
@@ -0,0 +1,181 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type GetSeasonLeaderboardUseCase struct {
+	postgres PostgreSQLAdapter
+	maxLimit int
+}
+
+// NewGetSeasonLeaderboardUseCase создает use case получения таблицы лидеров сезона.
+// maxLimit ограничивает размер одной страницы.
+func NewGetSeasonLeaderboardUseCase(postgres PostgreSQLAdapter, maxLimit int) *GetSeasonLeaderboardUseCase {
+	return &GetSeasonLeaderboardUseCase{
+		postgres: postgres,
+		maxLimit: maxLimit,
+	}
+}
+
+// Execute выполняет получение страницы таблицы лидеров сезона.
+// Для завершенного сезона возвращаются сохраненные итоги, для идущего -
+// текущие очки, заработанные с начала сезона. У еще не начавшегося сезона таблица пуста.
+func (uc *GetSeasonLeaderboardUseCase) Execute(ctx context.Context, seasonIDStr string, limit int, cursor string) (dto.GetSeasonLeaderboardOutput, error) {
+	seasonID, err := domain.SeasonIDFromString(seasonIDStr)
+	if err != nil {
+		return dto.GetSeasonLeaderboardOutput{}, err
+	}
+
+	season, err := uc.postgres.GetSeason(ctx, seasonID)
+	if err != nil {
+		return dto.GetSeasonLeaderboardOutput{}, fmt.Errorf("ошибка при получении сезона: %w", err)
+	}
+	if season == nil {
+		return dto.GetSeasonLeaderboardOutput{}, domain.ErrSeasonNotFound
+	}
+
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	if limit > uc.maxLimit {
+		limit = uc.maxLimit
+	}
+
+	now := time.Now()
+	output := dto.GetSeasonLeaderboardOutput{
+		Season: seasonToDTO(*season, now),
+		Users:  []dto.LeaderboardEntry{},
+	}
+
+	switch season.Status(now) {
+	case domain.SeasonUpcoming:
+		return output, nil
+	case domain.SeasonFinalized:
+		err = uc.standings(ctx, *season, limit, cursor, &output)
+	default:
+		err = uc.live(ctx, *season, limit, cursor, &output)
+	}
+	if err != nil {
+		return dto.GetSeasonLeaderboardOutput{}, err
+	}
+
+	return output, nil
+}
+
+// live заполняет страницу таблицы лидеров по поинтам, заработанным с начала сезона
+func (uc *GetSeasonLeaderboardUseCase) live(ctx context.Context, season domain.Season, limit int, cursor string, output *dto.GetSeasonLeaderboardOutput) error {
+	scope := "season:" + season.ID.String()
+
+	var after *LeaderboardCursor
+	if cursor != "" {
+		decoded, err := decodeLeaderboardCursor(cursor, scope)
+		if err != nil {
+			return err
+		}
+		after = &decoded
+	}
+
+	entries, err := uc.postgres.GetPeriodLeaderboard(ctx, storedTime(season.StartsAt), storedTime(season.EndsAt), limit+1, after)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении таблицы лидеров сезона: %w", err)
+	}
+
+	total, err := uc.postgres.CountPeriodLeaderboardUsers(ctx, storedTime(season.StartsAt), storedTime(season.EndsAt))
+	if err != nil {
+		return fmt.Errorf("ошибка при подсчете участников сезона: %w", err)
+	}
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next := entries[len(entries)-1].Cursor()
+		next.Period = scope
+		output.NextCursor = encodeLeaderboardCursor(next)
+	}
+
+	output.Users = leaderboardEntriesToDTO(entries)
+	output.Total = total
+	return nil
+}
+
+// standings заполняет страницу сохраненной итоговой таблицы сезона
+func (uc *GetSeasonLeaderboardUseCase) standings(ctx context.Context, season domain.Season, limit int, cursor string, output *dto.GetSeasonLeaderboardOutput) error {
+	afterRank := 0
+	if cursor != "" {
+		decoded, err := decodeSeasonStandingsCursor(cursor, season.ID)
+		if err != nil {
+			return err
+		}
+		afterRank = decoded.Rank
+	}
+
+	standings, err := uc.postgres.GetSeasonStandings(ctx, season.ID, afterRank, limit+1)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении итогов сезона: %w", err)
+	}
+
+	total, err := uc.postgres.CountSeasonStandings(ctx, season.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка при подсчете участников сезона: %w", err)
+	}
+
+	if len(standings) > limit {
+		standings = standings[:limit]
+		output.NextCursor = encodeSeasonStandingsCursor(seasonStandingsCursor{
+			SeasonID: season.ID.String(),
+			Rank:     standings[len(standings)-1].Rank,
+		})
+	}
+
+	users := make([]dto.LeaderboardEntry, len(standings))
+	for i, standing := range standings {
+		users[i] = dto.LeaderboardEntry{
+			Rank:     standing.Rank,
+			UserID:   standing.UserID.String(),
+			Username: standing.Username,
+			Balance:  standing.Score,
+		}
+	}
+
+	output.Users = users
+	output.Total = total
+	return nil
+}
+
+// seasonStandingsCursor позиция в итоговой таблице сезона. Места в ней не меняются,
+// поэтому для продолжения достаточно последнего полученного места.
+type seasonStandingsCursor struct {
+	SeasonID string `json:"s"`
+	Rank     int    `json:"r"`
+}
+
+// encodeSeasonStandingsCursor кодирует курсор в непрозрачную для клиента строку
+func encodeSeasonStandingsCursor(cursor seasonStandingsCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSeasonStandingsCursor разбирает курсор итоговой таблицы, полученный от клиента
+func decodeSeasonStandingsCursor(value string, seasonID domain.SeasonID) (seasonStandingsCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return seasonStandingsCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+
+	var cursor seasonStandingsCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Rank <= 0 {
+		return seasonStandingsCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+	if cursor.SeasonID != seasonID.String() {
+		return seasonStandingsCursor{}, fmt.Errorf("%w: курсор выдан для другой таблицы лидеров", domain.ErrInvalidPagination)
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path/filepath"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type GetSubmissionProofUseCase struct {
+	postgres     PostgreSQLAdapter
+	proofStorage ProofStorage
+}
+
+func NewGetSubmissionProofUseCase(postgres PostgreSQLAdapter, proofStorage ProofStorage) *GetSubmissionProofUseCase {
+	return &GetSubmissionProofUseCase{
+		postgres:     postgres,
+		proofStorage: proofStorage,
+	}
+}
+
+// Execute открывает загруженный скриншот заявки. Вызывающий должен закрыть Content.
+func (uc *GetSubmissionProofUseCase) Execute(ctx context.Context, submissionIDStr string) (dto.ProofFileOutput, error) {
+	submission, err := getSubmission(ctx, uc.postgres, submissionIDStr)
+	if err != nil {
+		return dto.ProofFileOutput{}, err
+	}
+
+	if submission.ProofType != domain.ProofTypeScreenshot {
+		return dto.ProofFileOutput{}, fmt.Errorf("%w: у заявки нет загруженного файла", domain.ErrInvalidProof)
+	}
+
+	content, err := uc.proofStorage.Open(ctx, submission.Proof)
+	if err != nil {
+		return dto.ProofFileOutput{}, fmt.Errorf("ошибка при открытии файла подтверждения: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(submission.Proof))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return dto.ProofFileOutput{
+		ContentType: contentType,
+		Content:     content,
+	}, nil
+}
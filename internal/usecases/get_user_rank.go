@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+// Ограничения числа соседей пользователя в таблице лидеров
+const (
+	defaultRankNeighbours = 5
+	maxRankNeighbours     = 50
+)
+
+type GetUserRankUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewGetUserRankUseCase(postgres PostgreSQLAdapter) *GetUserRankUseCase {
+	return &GetUserRankUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает место пользователя в таблице лидеров и neighbours пользователей выше и ниже него
+func (uc *GetUserRankUseCase) Execute(ctx context.Context, userIDStr string, neighbours int) (dto.GetUserRankOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.GetUserRankOutput{}, err
+	}
+
+	if neighbours < 0 {
+		neighbours = defaultRankNeighbours
+	}
+	if neighbours > maxRankNeighbours {
+		neighbours = maxRankNeighbours
+	}
+
+	position, err := uc.postgres.GetLeaderboardPosition(ctx, userID)
+	if err != nil {
+		return dto.GetUserRankOutput{}, fmt.Errorf("ошибка при получении места в таблице лидеров: %w", err)
+	}
+	if position == nil {
+		return dto.GetUserRankOutput{}, domain.ErrUserNotFound
+	}
+
+	total, err := uc.postgres.CountLeaderboardUsers(ctx)
+	if err != nil {
+		return dto.GetUserRankOutput{}, fmt.Errorf("ошибка при подсчете участников таблицы лидеров: %w", err)
+	}
+
+	above, below := []LeaderboardEntry{}, []LeaderboardEntry{}
+	if neighbours > 0 {
+		cursor := position.Cursor()
+
+		above, err = uc.postgres.GetLeaderboardBefore(ctx, cursor, neighbours)
+		if err != nil {
+			return dto.GetUserRankOutput{}, fmt.Errorf("ошибка при получении соседей в таблице лидеров: %w", err)
+		}
+
+		below, err = uc.postgres.GetLeaderboard(ctx, neighbours, &cursor)
+		if err != nil {
+			return dto.GetUserRankOutput{}, fmt.Errorf("ошибка при получении соседей в таблице лидеров: %w", err)
+		}
+	}
+
+	return dto.GetUserRankOutput{
+		User:       leaderboardEntryToDTO(*position),
+		Total:      total,
+		Percentile: rankPercentile(position.Rank, total),
+		Above:      leaderboardEntriesToDTO(above),
+		Below:      leaderboardEntriesToDTO(below),
+	}, nil
+}
+
+// rankPercentile возвращает долю остальных участников в процентах, которые стоят ниже пользователя
+func rankPercentile(rank, total int) float64 {
+	if total <= 1 {
+		return 100
+	}
+	percentile := float64(total-rank) / float64(total-1) * 100
+	return math.Round(percentile*100) / 100
+}
@@ -3,18 +3,25 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"user-rewards-api/internal/domain"
 	"user-rewards-api/internal/dto"
 )
 
 type GetUserStatusUseCase struct {
-	postgres PostgreSQLAdapter
+	postgres       PostgreSQLAdapter
+	achievements   *AchievementEngine
+	expiringWindow time.Duration
 }
 
-func NewGetUserStatusUseCase(postgres PostgreSQLAdapter) *GetUserStatusUseCase {
+// NewGetUserStatusUseCase создает use case получения статуса пользователя.
+// expiringWindow задает, за какой срок до сгорания поинты показываются в статусе как сгорающие.
+func NewGetUserStatusUseCase(postgres PostgreSQLAdapter, achievements *AchievementEngine, expiringWindow time.Duration) *GetUserStatusUseCase {
 	return &GetUserStatusUseCase{
-		postgres: postgres,
+		postgres:       postgres,
+		achievements:   achievements,
+		expiringWindow: expiringWindow,
 	}
 }
 
@@ -43,10 +50,33 @@ func (uc *GetUserStatusUseCase) Execute(ctx context.Context, userIDStr string) (
 		return dto.GetUserStatusOutput{}, fmt.Errorf("ошибка при получении рефералов: %w", err)
 	}
 
+	referralCodes, err := uc.postgres.GetReferralCodesByUserID(ctx, userID)
+	if err != nil {
+		return dto.GetUserStatusOutput{}, fmt.Errorf("ошибка при получении реферальных кодов: %w", err)
+	}
+
+	expiringBefore := time.Now().Add(uc.expiringWindow)
+	expiringPoints, err := uc.postgres.SumExpiringPoints(ctx, userID, expiringBefore)
+	if err != nil {
+		return dto.GetUserStatusOutput{}, fmt.Errorf("ошибка при получении сгорающих поинтов: %w", err)
+	}
+
+	badges, err := uc.postgres.GetUserBadges(ctx, userID)
+	if err != nil {
+		return dto.GetUserStatusOutput{}, fmt.Errorf("ошибка при получении значков: %w", err)
+	}
+
+	codes := referralCodesToDTO(referralCodes)
+
 	return dto.GetUserStatusOutput{
-		UserID:         user.ID.String(),
-		Balance:        user.Balance.Value(),
-		CompletedTasks: len(tasks),
-		ReferralCount:  referralCount,
+		UserID:             user.ID.String(),
+		Balance:            user.Balance.Value(),
+		CompletedTasks:     len(tasks),
+		ReferralCount:      referralCount,
+		ReferralCode:       codes.ReferralCode,
+		VanityReferralCode: codes.VanityReferralCode,
+		ExpiringPoints:     expiringPoints,
+		ExpiringBefore:     expiringBefore,
+		Badges:             userBadgesToDTO(uc.achievements.Badges(), badges),
 	}, nil
 }
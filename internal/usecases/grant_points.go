@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type GrantPointsUseCase struct {
+	postgres PostgreSQLAdapter
+	expiry   domain.PointsExpiryPolicy
+}
+
+func NewGrantPointsUseCase(postgres PostgreSQLAdapter, expiry domain.PointsExpiryPolicy) *GrantPointsUseCase {
+	return &GrantPointsUseCase{
+		postgres: postgres,
+		expiry:   expiry,
+	}
+}
+
+// Execute начисляет поинты пользователю от имени администратора.
+// Начисленные поинты сгорают так же, как заработанные за задания.
+// Причина начисления сохраняется в записи журнала.
+func (uc *GrantPointsUseCase) Execute(ctx context.Context, userIDStr string, input dto.GrantPointsInput) (dto.GrantPointsOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.GrantPointsOutput{}, err
+	}
+
+	entry, err := domain.NewPointsGrant(userID, input.Points, input.Reason)
+	if err != nil {
+		return dto.GrantPointsOutput{}, err
+	}
+
+	balance, err := uc.postgres.AddLedgerEntry(ctx, entry)
+	if err != nil {
+		return dto.GrantPointsOutput{}, fmt.Errorf("ошибка при начислении поинтов: %w", err)
+	}
+
+	return dto.GrantPointsOutput{
+		UserID:     userID.String(),
+		Points:     entry.Amount,
+		NewBalance: balance.Value(),
+		ExpiresAt:  uc.expiry.ExpiresAt(entry.CreatedAt),
+	}, nil
+}
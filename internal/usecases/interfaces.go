@@ -2,16 +2,57 @@ package usecases
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"user-rewards-api/internal/domain"
 )
 
 // LeaderboardEntry запись в таблице лидеров
 type LeaderboardEntry struct {
-	Rank     int
-	UserID   string
-	Username string
-	Balance  int
+	Rank      int
+	UserID    string
+	Username  string
+	Balance   int
+	CreatedAt time.Time
+}
+
+// Cursor возвращает позицию записи в таблице лидеров
+func (e LeaderboardEntry) Cursor() LeaderboardCursor {
+	return LeaderboardCursor{
+		Balance:   e.Balance,
+		CreatedAt: e.CreatedAt,
+		UserID:    e.UserID,
+	}
+}
+
+// LeaderboardCursor позиция в таблице лидеров, после которой начинается следующая страница.
+// Поля повторяют порядок сортировки balance DESC, created_at ASC, id ASC.
+// Место в курсор не входит: адаптер пересчитывает его по позиции, поэтому поддельный курсор
+// не может изменить места в выдаче.
+// Period задает таблицу, для которой выдан курсор: период или сезон. Для таблицы за все время пуст.
+type LeaderboardCursor struct {
+	Balance   int       `json:"b"`
+	CreatedAt time.Time `json:"c"`
+	UserID    string    `json:"u"`
+	Period    string    `json:"p,omitempty"`
+}
+
+// ReferredUser пользователь в списке приглашенных реферером.
+// GeneratedPoints - поинты, полученные реферером благодаря этому пользователю:
+// бонус за приглашение после начисления и комиссии с его поинтов.
+type ReferredUser struct {
+	Referral        domain.Referral
+	Username        string
+	JoinedAt        time.Time
+	GeneratedPoints int
+}
+
+// ReferredUsersCursor позиция в списке приглашенных пользователей, после которой начинается
+// следующая страница. Поля повторяют порядок сортировки created_at DESC, id DESC.
+type ReferredUsersCursor struct {
+	CreatedAt  time.Time `json:"c"`
+	ReferralID string    `json:"i"`
 }
 
 // PostgreSQLAdapter интерфейс для работы с PostgreSQL
@@ -21,19 +62,165 @@ type PostgreSQLAdapter interface {
 	GetUserByID(ctx context.Context, userID domain.UserID) (*domain.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
-	UpdateUserBalance(ctx context.Context, userID domain.UserID, balance domain.Balance) error
-	GetLeaderboard(ctx context.Context, limit int) ([]LeaderboardEntry, error)
+	LockUser(ctx context.Context, userID domain.UserID) error
+	ListUserIDs(ctx context.Context, after *domain.UserID, limit int) ([]domain.UserID, error)
+	GetLeaderboard(ctx context.Context, limit int, after *LeaderboardCursor) ([]LeaderboardEntry, error)
+	GetLeaderboardBefore(ctx context.Context, before LeaderboardCursor, limit int) ([]LeaderboardEntry, error)
+	GetLeaderboardPosition(ctx context.Context, userID domain.UserID) (*LeaderboardEntry, error)
+	CountLeaderboardUsers(ctx context.Context) (int, error)
+	GetPeriodLeaderboard(ctx context.Context, since, until time.Time, limit int, after *LeaderboardCursor) ([]LeaderboardEntry, error)
+	CountPeriodLeaderboardUsers(ctx context.Context, since, until time.Time) (int, error)
 
 	// Методы для работы с заданиями
 	CreateTask(ctx context.Context, task domain.UserTask) error
 	GetTasksByUserID(ctx context.Context, userID domain.UserID) ([]domain.UserTask, error)
-	GetTaskByUserAndType(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (*domain.UserTask, error)
+	GetTaskCompletionStats(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (int, *time.Time, error)
+
+	// Методы для работы с каталогом заданий
+	CreateCatalogTask(ctx context.Context, task domain.Task) error
+	UpdateCatalogTask(ctx context.Context, task domain.Task) error
+	GetCatalogTask(ctx context.Context, key domain.TaskType) (*domain.Task, error)
+	ListCatalogTasks(ctx context.Context, includeInactive bool) ([]domain.Task, error)
+
+	// Методы для работы с заявками на выполнение заданий
+	CreateSubmission(ctx context.Context, submission domain.TaskSubmission) error
+	GetSubmission(ctx context.Context, id domain.SubmissionID) (*domain.TaskSubmission, error)
+	UpdateSubmissionReview(ctx context.Context, submission domain.TaskSubmission) error
+	ListSubmissionsByStatus(ctx context.Context, status domain.SubmissionStatus, limit int) ([]domain.TaskSubmission, error)
+	ListSubmissionsByUserID(ctx context.Context, userID domain.UserID) ([]domain.TaskSubmission, error)
 
 	// Методы для работы с рефералами
 	CreateReferral(ctx context.Context, referral domain.Referral) error
 	GetReferralByReferredUserID(ctx context.Context, referredUserID domain.UserID) (*domain.Referral, error)
+	ListPendingReferrals(ctx context.Context, after *domain.Referral, limit int) ([]domain.Referral, error)
+	UpdateReferralStatus(ctx context.Context, referral domain.Referral, from domain.ReferralStatus) (bool, error)
+	GetReferral(ctx context.Context, referralID domain.ReferralID) (*domain.Referral, error)
+	ListReferralsByStatus(ctx context.Context, status domain.ReferralStatus, limit int) ([]domain.Referral, error)
+	CountReferralsByReferrerSince(ctx context.Context, referrerID domain.UserID, since time.Time) (int, error)
 	CountReferralsByReferrerID(ctx context.Context, referrerID domain.UserID) (int, error)
+	ListReferredUsers(ctx context.Context, referrerID domain.UserID, after *ReferredUsersCursor, limit int) ([]ReferredUser, error)
+	GetReferralLeaderboard(ctx context.Context, since time.Time, limit int, after *LeaderboardCursor) ([]LeaderboardEntry, error)
+	CountReferralLeaderboardUsers(ctx context.Context, since time.Time) (int, error)
+
+	// Методы для работы с реферальными кодами
+	CreateReferralCode(ctx context.Context, code domain.ReferralCode) (bool, error)
+	GetReferralCode(ctx context.Context, code string) (*domain.ReferralCode, error)
+	GetReferralCodesByUserID(ctx context.Context, userID domain.UserID) ([]domain.ReferralCode, error)
+
+	// Методы для работы с отпечатками клиентов
+	RecordClientEvent(ctx context.Context, event domain.ClientEvent) error
+	CountUsersSharingFingerprint(ctx context.Context, fingerprint domain.ClientFingerprint, excludeUserID domain.UserID, since time.Time) (int, error)
+	UserSharesFingerprint(ctx context.Context, userID domain.UserID, fingerprint domain.ClientFingerprint, since time.Time) (bool, error)
+
+	// Методы для работы с реферальными комиссиями
+	GetReferralChain(ctx context.Context, userID domain.UserID, depth int) ([]domain.UserID, error)
+	CreateReferralCommission(ctx context.Context, commission domain.ReferralCommission) (bool, error)
+	GetCommissionSummary(ctx context.Context, beneficiaryID domain.UserID) ([]domain.CommissionLevelSummary, error)
+
+	// Методы для работы с внешними аккаунтами
+	CreateExternalAccount(ctx context.Context, account domain.ExternalAccount) error
+	GetExternalAccount(ctx context.Context, provider domain.ExternalProvider, externalID string) (*domain.ExternalAccount, error)
+	RecordWebhookEvent(ctx context.Context, partner domain.ExternalProvider, eventID string) (bool, error)
+
+	// Методы для работы с журналом поинтов
+	AddLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, error)
+	DebitLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, bool, error)
+	AlignBackfilledPointLots(ctx context.Context) (int, error)
+	ListUsersWithExpiredPoints(ctx context.Context, now time.Time, limit int) ([]domain.UserID, error)
+	ExpirePointLots(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.PointLot, error)
+	SumExpiringPoints(ctx context.Context, userID domain.UserID, until time.Time) (int, error)
+
+	// Методы для работы с магазином наград
+	CreateReward(ctx context.Context, reward domain.Reward) error
+	UpdateReward(ctx context.Context, reward domain.Reward) error
+	GetReward(ctx context.Context, rewardID domain.RewardID) (*domain.Reward, error)
+	ListRewards(ctx context.Context, includeUnavailable bool, now time.Time) ([]domain.Reward, error)
+	ReserveRewardStock(ctx context.Context, rewardID domain.RewardID) (bool, error)
+	CreateRewardOrder(ctx context.Context, order domain.RewardOrder) error
+	GetRewardOrder(ctx context.Context, orderID domain.RewardOrderID) (*domain.RewardOrder, error)
+	CountRewardOrdersByUser(ctx context.Context, userID domain.UserID, rewardID domain.RewardID) (int, error)
+	ListRewardOrdersByUserID(ctx context.Context, userID domain.UserID) ([]domain.RewardOrder, error)
+	ListRewardOrdersByStatus(ctx context.Context, status domain.RewardOrderStatus, limit int) ([]domain.RewardOrder, error)
+	FulfillRewardOrder(ctx context.Context, order domain.RewardOrder) (bool, error)
+
+	// Методы для работы со значками
+	AwardBadge(ctx context.Context, badge domain.UserBadge) (bool, error)
+	GetUserBadges(ctx context.Context, userID domain.UserID) ([]domain.UserBadge, error)
+
+	// Методы для работы с сезонами
+	CreateSeason(ctx context.Context, season domain.Season) error
+	HasOverlappingSeason(ctx context.Context, startsAt, endsAt time.Time) (bool, error)
+	GetSeason(ctx context.Context, seasonID domain.SeasonID) (*domain.Season, error)
+	ListSeasons(ctx context.Context) ([]domain.Season, error)
+	ListSeasonsToFinalize(ctx context.Context, now time.Time) ([]domain.Season, error)
+	FinalizeSeason(ctx context.Context, season domain.Season, finalizedAt time.Time) (bool, error)
+	GetSeasonStandings(ctx context.Context, seasonID domain.SeasonID, afterRank, limit int) ([]domain.SeasonStanding, error)
+	CountSeasonStandings(ctx context.Context, seasonID domain.SeasonID) (int, error)
+
+	// Методы для работы с refresh токенами
+	CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, tokenID domain.RefreshTokenID) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID domain.RefreshTokenID) error
+
+	// Методы для работы с кодами входа
+	CreateLoginCode(ctx context.Context, code domain.LoginCode) error
+	GetLatestLoginCode(ctx context.Context, userID domain.UserID) (*domain.LoginCode, error)
+	ClaimLoginCodeAttempt(ctx context.Context, codeID domain.LoginCodeID, now time.Time) (bool, error)
+	ConsumeLoginCode(ctx context.Context, codeID domain.LoginCodeID) (bool, error)
+	RecordLoginCodeRequest(ctx context.Context, email, ip string, since time.Time) (int, int, error)
+	DeleteLoginCodeRequests(ctx context.Context, before time.Time) (int64, error)
 
 	// Методы для работы с транзакциями
 	WithTransaction(ctx context.Context, fn func(context.Context) error) error
 }
+
+// EmailMessage письмо, отправляемое пользователю
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer интерфейс для отправки писем
+type Mailer interface {
+	Send(ctx context.Context, message EmailMessage) error
+}
+
+// ProofStorage интерфейс хранилища файлов подтверждения выполнения заданий
+type ProofStorage interface {
+	// Save сохраняет файл и возвращает ключ, по которому его можно получить
+	Save(ctx context.Context, contentType string, content io.Reader) (string, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// TaskCallback подтвержденное партнерским сервисом выполнение задания
+type TaskCallback struct {
+	EventID           string
+	ExternalAccountID string
+	TaskType          string
+	OccurredAt        time.Time
+}
+
+// TaskVerifier интерфейс проверки подписанных callback от партнерских сервисов
+type TaskVerifier interface {
+	// Verify проверяет подпись и время отправки callback и возвращает его содержимое.
+	// При невалидной подписи или устаревшем timestamp возвращает domain.ErrInvalidSignature.
+	Verify(partner domain.ExternalProvider, signature, timestamp string, body []byte) (TaskCallback, error)
+}
+
+// FraudCheck данные новой реферальной связи, которые проверяют правила FraudRule
+type FraudCheck struct {
+	Referrer    domain.User
+	Referred    domain.User
+	Fingerprint domain.ClientFingerprint
+	Now         time.Time
+}
+
+// FraudRule правило проверки реферальной связи на мошенничество.
+// Правило возвращает признак с положительным Score, если связь выглядит подозрительно,
+// и признак с нулевым Score, если правило ничего не нашло.
+type FraudRule interface {
+	Evaluate(ctx context.Context, check FraudCheck) (domain.FraudSignal, error)
+}
@@ -0,0 +1,104 @@
+package usecases_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+)
+
+// newInviteFriendUseCase настраивает callback на задание invite_friend пользователя,
+// приглашенного по реферальной связи в состоянии status
+func newInviteFriendUseCase(t *testing.T, minCompletedTasks int, status domain.ReferralStatus) (*usecases.ProcessTaskCallbackUseCase, *callbackStore, domain.UserID) {
+	t.Helper()
+
+	policy, err := domain.NewReferralVestingPolicy(0, 0, minCompletedTasks, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc, store := newCallbackUseCaseWithPolicy(t, policy)
+
+	task, err := domain.NewTask(domain.TaskTypeInviteFriend.String(), "Пригласить друга", "", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.ProofType = domain.ProofTypeExternal
+	store.task = task
+
+	referrerID, err := domain.NewUserID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	referral, err := domain.NewReferral(referrerID, store.user.ID, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	referral.Status = status
+	store.referral = &referral
+
+	return uc, store, referrerID
+}
+
+func completeInviteFriend(t *testing.T, uc *usecases.ProcessTaskCallbackUseCase, eventID string) {
+	t.Helper()
+
+	body := []byte(fmt.Sprintf(`{"event_id":%q,"external_account_id":"42","task_type":"invite_friend"}`, eventID))
+	signature, timestamp := signCallback(callbackSecret, time.Now(), body)
+	if _, err := uc.Execute(context.Background(), "telegram", signature, timestamp, body); err != nil {
+		t.Fatalf("%s: %v", eventID, err)
+	}
+}
+
+// assertInviteCredits проверяет, что реферер получил want начислений, не больше одного за выполнение задания.
+// Если want не равно нулю, поинты должны быть начислены за каждое выполнение.
+func assertInviteCredits(t *testing.T, store *callbackStore, referrerID domain.UserID, want int) {
+	t.Helper()
+
+	credits := store.entriesFor(referrerID)
+	if len(credits) != want {
+		t.Fatalf("реферер получил %d начислений, ожидалось %d", len(credits), want)
+	}
+
+	paid := make(map[string]bool)
+	for _, credit := range credits {
+		if credit.Source != domain.LedgerSourceReferral || credit.Amount != 100 || paid[credit.ReferenceID] {
+			t.Fatalf("некорректное начисление рефереру: %+v", credit)
+		}
+		paid[credit.ReferenceID] = true
+	}
+	for _, task := range store.tasks {
+		if want != 0 && !paid[task.ID.String()] {
+			t.Errorf("за выполнение %s реферер не получил поинты", task.ID)
+		}
+	}
+}
+
+func TestInviteFriendCreditPaidWhenReferralVestsLater(t *testing.T) {
+	uc, store, referrerID := newInviteFriendUseCase(t, 2, domain.ReferralPending)
+
+	completeInviteFriend(t, uc, "evt-1")
+	if store.referral.Status != domain.ReferralPending {
+		t.Fatalf("связь в состоянии %s, ожидалось pending", store.referral.Status)
+	}
+	assertInviteCredits(t, store, referrerID, 0)
+
+	// Второе задание выполняет условия: реферер получает поинты за оба выполнения
+	completeInviteFriend(t, uc, "evt-2")
+	if store.referral.Status != domain.ReferralVested {
+		t.Fatalf("связь в состоянии %s, ожидалось vested", store.referral.Status)
+	}
+	assertInviteCredits(t, store, referrerID, 2)
+
+	completeInviteFriend(t, uc, "evt-3")
+	assertInviteCredits(t, store, referrerID, 3)
+}
+
+func TestInviteFriendCreditNotPaidForCancelledReferral(t *testing.T) {
+	uc, store, referrerID := newInviteFriendUseCase(t, 1, domain.ReferralCancelled)
+
+	completeInviteFriend(t, uc, "evt-1")
+	assertInviteCredits(t, store, referrerID, 0)
+}
@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type IssueTokenUseCase struct {
+	postgres    PostgreSQLAdapter
+	tokenIssuer *TokenIssuer
+}
+
+func NewIssueTokenUseCase(postgres PostgreSQLAdapter, tokenIssuer *TokenIssuer) *IssueTokenUseCase {
+	return &IssueTokenUseCase{
+		postgres:    postgres,
+		tokenIssuer: tokenIssuer,
+	}
+}
+
+// Execute обменивает действующий access токен пользователя на новый access токен в текущем
+// формате и с актуальной ролью. Refresh токен не выпускается, а новый токен истекает
+// не позже предъявленного expiresAt, поэтому украденный access токен нельзя превратить
+// в долгую сессию. Новую пару токенов выдают только вход по коду и /auth/refresh.
+func (uc *IssueTokenUseCase) Execute(ctx context.Context, userIDStr string, expiresAt time.Time) (dto.TokenOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.TokenOutput{}, err
+	}
+
+	user, err := uc.postgres.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.TokenOutput{}, err
+	}
+	if user == nil {
+		return dto.TokenOutput{}, domain.ErrUserNotFound
+	}
+
+	return uc.tokenIssuer.IssueAccessToken(*user, expiresAt)
+}
@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+// LeaderboardPeriod период, за который строится таблица лидеров
+type LeaderboardPeriod string
+
+const (
+	LeaderboardDaily   LeaderboardPeriod = "daily"
+	LeaderboardWeekly  LeaderboardPeriod = "weekly"
+	LeaderboardMonthly LeaderboardPeriod = "monthly"
+	LeaderboardAllTime LeaderboardPeriod = "all_time"
+)
+
+// NewLeaderboardPeriod создает новый LeaderboardPeriod с валидацией.
+// Пустое значение означает таблицу лидеров за все время.
+func NewLeaderboardPeriod(value string) (LeaderboardPeriod, error) {
+	if value == "" {
+		return LeaderboardAllTime, nil
+	}
+
+	period := LeaderboardPeriod(value)
+	switch period {
+	case LeaderboardDaily, LeaderboardWeekly, LeaderboardMonthly, LeaderboardAllTime:
+		return period, nil
+	}
+	return "", fmt.Errorf("%w: %s, ожидается daily, weekly, monthly или all_time", domain.ErrInvalidLeaderboardPeriod, value)
+}
+
+// String возвращает строковое представление LeaderboardPeriod
+func (p LeaderboardPeriod) String() string {
+	return string(p)
+}
+
+// Since возвращает начало текущего периода в часовом поясе loc.
+// Неделя начинается в понедельник. Для all_time возвращает false.
+func (p LeaderboardPeriod) Since(now time.Time, loc *time.Location) (time.Time, bool) {
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch p {
+	case LeaderboardDaily:
+		return today, true
+	case LeaderboardWeekly:
+		daysSinceMonday := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -daysSinceMonday), true
+	case LeaderboardMonthly:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// storedTime переводит момент времени в часовой пояс процесса.
+// Колонки TIMESTAMP хранят время, записанное приложением по time.Now(), без часового пояса,
+// а PostgreSQL отбрасывает смещение у параметров запроса. Границы периодов, построенные
+// в другом часовом поясе, нужно переводить перед сравнением с такими колонками,
+// иначе период сдвигается на разницу часовых поясов.
+func storedTime(t time.Time) time.Time {
+	return t.In(time.Local)
+}
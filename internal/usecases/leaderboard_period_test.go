@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// storedWallClock возвращает значение, которое PostgreSQL сравнивает для колонки TIMESTAMP:
+// время без часового пояса
+func storedWallClock(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// setProcessLocation подменяет часовой пояс процесса на время теста
+func setProcessLocation(t *testing.T, loc *time.Location) {
+	t.Helper()
+
+	previous := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = previous })
+}
+
+func TestLeaderboardPeriodWindowMatchesStoredTimestamps(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2026, 3, 10, 1, 30, 0, 0, moscow)
+
+	processLocations := []*time.Location{
+		time.UTC,
+		time.FixedZone("UTC+5", 5*60*60),
+		time.FixedZone("UTC-7", -7*60*60),
+	}
+
+	for _, processLocation := range processLocations {
+		for _, period := range []LeaderboardPeriod{LeaderboardDaily, LeaderboardWeekly, LeaderboardMonthly} {
+			t.Run(processLocation.String()+"/"+period.String(), func(t *testing.T) {
+				setProcessLocation(t, processLocation)
+
+				since, ok := period.Since(now, moscow)
+				if !ok {
+					t.Fatalf("период %s не ограничен", period)
+				}
+				if since.Hour() != 0 || since.Minute() != 0 || since.Location() != moscow {
+					t.Fatalf("начало периода %v, ожидалась полночь по Москве", since)
+				}
+
+				boundary := storedWallClock(storedTime(since))
+
+				// Приложение записывает created_at по time.Now() в часовом поясе процесса
+				inside := storedWallClock(since.Add(5 * time.Minute).In(time.Local))
+				outside := storedWallClock(since.Add(-5 * time.Minute).In(time.Local))
+
+				if inside < boundary {
+					t.Errorf("начисление после начала периода (%s) не попало в окно с %s", inside, boundary)
+				}
+				if outside >= boundary {
+					t.Errorf("начисление до начала периода (%s) попало в окно с %s", outside, boundary)
+				}
+			})
+		}
+	}
+}
+
+// periodLeaderboardStore запоминает границы, с которыми запрошена таблица лидеров за период
+type periodLeaderboardStore struct {
+	PostgreSQLAdapter
+
+	since []time.Time
+}
+
+func (s *periodLeaderboardStore) GetPeriodLeaderboard(ctx context.Context, since, until time.Time, limit int, after *LeaderboardCursor) ([]LeaderboardEntry, error) {
+	s.since = append(s.since, since)
+	return nil, nil
+}
+
+func (s *periodLeaderboardStore) CountPeriodLeaderboardUsers(ctx context.Context, since, until time.Time) (int, error) {
+	s.since = append(s.since, since)
+	return 0, nil
+}
+
+func TestGetLeaderboardQueriesInStoredTimezone(t *testing.T) {
+	processLocation := time.FixedZone("UTC-7", -7*60*60)
+	setProcessLocation(t, processLocation)
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	store := &periodLeaderboardStore{}
+	uc := NewGetLeaderboardUseCase(store, 100, moscow)
+
+	output, err := uc.Execute(context.Background(), LeaderboardDaily, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Since == nil || output.Since.Location() != moscow {
+		t.Fatalf("начало периода в ответе %v, ожидалось время по Москве", output.Since)
+	}
+
+	if len(store.since) != 2 {
+		t.Fatalf("выполнено %d запросов, ожидалось 2", len(store.since))
+	}
+	for _, since := range store.since {
+		if !since.Equal(*output.Since) {
+			t.Errorf("запрос с %v, ожидалось %v", since, *output.Since)
+		}
+		if since.Location() != processLocation {
+			t.Errorf("граница периода передана в часовом поясе %v, ожидался часовой пояс процесса", since.Location())
+		}
+	}
+}
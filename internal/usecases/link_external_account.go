@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type LinkExternalAccountUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewLinkExternalAccountUseCase(postgres PostgreSQLAdapter) *LinkExternalAccountUseCase {
+	return &LinkExternalAccountUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute привязывает аккаунт партнерского сервиса к пользователю.
+// Один внешний аккаунт может принадлежать только одному пользователю.
+func (uc *LinkExternalAccountUseCase) Execute(ctx context.Context, userIDStr string, input dto.LinkExternalAccountInput) (dto.ExternalAccountOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.ExternalAccountOutput{}, err
+	}
+
+	provider, err := domain.NewExternalProvider(input.Provider)
+	if err != nil {
+		return dto.ExternalAccountOutput{}, err
+	}
+
+	account, err := domain.NewExternalAccount(provider, input.ExternalID, userID)
+	if err != nil {
+		return dto.ExternalAccountOutput{}, err
+	}
+
+	user, err := uc.postgres.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.ExternalAccountOutput{}, err
+	}
+	if user == nil {
+		return dto.ExternalAccountOutput{}, domain.ErrUserNotFound
+	}
+
+	if err := uc.postgres.CreateExternalAccount(ctx, account); err != nil {
+		return dto.ExternalAccountOutput{}, fmt.Errorf("ошибка при привязке аккаунта: %w", err)
+	}
+
+	return dto.ExternalAccountOutput{
+		Provider:   account.Provider.String(),
+		ExternalID: account.ExternalID,
+		UserID:     account.UserID.String(),
+		CreatedAt:  account.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ListCatalogTasksUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListCatalogTasksUseCase(postgres PostgreSQLAdapter) *ListCatalogTasksUseCase {
+	return &ListCatalogTasksUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает задания каталога. Неактивные и архивные задания
+// возвращаются только при includeInactive.
+func (uc *ListCatalogTasksUseCase) Execute(ctx context.Context, includeInactive bool) (dto.ListCatalogTasksOutput, error) {
+	tasks, err := uc.postgres.ListCatalogTasks(ctx, includeInactive)
+	if err != nil {
+		return dto.ListCatalogTasksOutput{}, fmt.Errorf("ошибка при получении каталога заданий: %w", err)
+	}
+
+	result := make([]dto.CatalogTaskOutput, len(tasks))
+	for i := range tasks {
+		result[i] = catalogTaskToDTO(tasks[i])
+	}
+
+	return dto.ListCatalogTasksOutput{
+		Tasks: result,
+		Total: len(result),
+	}, nil
+}
+
+// getCatalogTask получает задание каталога по ключу или возвращает domain.ErrTaskNotFound
+func getCatalogTask(ctx context.Context, postgres PostgreSQLAdapter, key string) (domain.Task, error) {
+	taskType, err := domain.NewTaskType(key)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	task, err := postgres.GetCatalogTask(ctx, taskType)
+	if err != nil {
+		return domain.Task{}, fmt.Errorf("ошибка при получении задания: %w", err)
+	}
+	if task == nil {
+		return domain.Task{}, domain.ErrTaskNotFound
+	}
+	return *task, nil
+}
+
+func catalogTaskToDTO(task domain.Task) dto.CatalogTaskOutput {
+	return dto.CatalogTaskOutput{
+		Key:         task.Key.String(),
+		Title:       task.Title,
+		Description: task.Description,
+		Points:      task.Points,
+		Repeat:      repeatPolicyToDTO(task.Repeat),
+		ProofType:   task.ProofType.String(),
+		Active:      task.Active,
+		CreatedAt:   task.CreatedAt,
+		ArchivedAt:  task.ArchivedAt,
+	}
+}
+
+func repeatPolicyFromDTO(input dto.RepeatPolicyInput) (domain.RepeatPolicy, error) {
+	return domain.NewRepeatPolicy(input.Policy, input.IntervalHours, input.ResetTime, input.ResetWeekday, input.MaxCompletions)
+}
+
+func repeatPolicyToDTO(policy domain.RepeatPolicy) dto.RepeatPolicyOutput {
+	output := dto.RepeatPolicyOutput{
+		Policy:         policy.Kind.String(),
+		MaxCompletions: policy.MaxCompletions,
+	}
+
+	switch policy.Kind {
+	case domain.RepeatInterval:
+		output.IntervalHours = policy.IntervalHours
+	case domain.RepeatWeekly:
+		output.ResetWeekday = strings.ToLower(policy.ResetWeekday.String())
+		output.ResetTime = policy.ResetTime()
+	case domain.RepeatDaily:
+		output.ResetTime = policy.ResetTime()
+	}
+
+	return output
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+// maxReferralsReviewPage максимальное число реферальных связей в одном ответе очереди модерации
+const maxReferralsReviewPage = 100
+
+type ListReferralsForReviewUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListReferralsForReviewUseCase(postgres PostgreSQLAdapter) *ListReferralsForReviewUseCase {
+	return &ListReferralsForReviewUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает реферальные связи с указанным статусом вместе с признаками
+// мошенничества, начиная с самых старых. По умолчанию возвращаются задержанные связи.
+func (uc *ListReferralsForReviewUseCase) Execute(ctx context.Context, statusStr string, limit int) (dto.ListReferralsForReviewOutput, error) {
+	status := domain.ReferralOnHold
+	if statusStr != "" {
+		var err error
+		status, err = domain.NewReferralStatus(statusStr)
+		if err != nil {
+			return dto.ListReferralsForReviewOutput{}, err
+		}
+	}
+
+	if limit <= 0 || limit > maxReferralsReviewPage {
+		limit = maxReferralsReviewPage
+	}
+
+	referrals, err := uc.postgres.ListReferralsByStatus(ctx, status, limit)
+	if err != nil {
+		return dto.ListReferralsForReviewOutput{}, fmt.Errorf("ошибка при получении реферальных связей: %w", err)
+	}
+
+	result := make([]dto.ReferralReviewOutput, len(referrals))
+	for i := range referrals {
+		result[i] = referralReviewToDTO(referrals[i])
+	}
+
+	return dto.ListReferralsForReviewOutput{
+		Referrals: result,
+		Total:     len(result),
+	}, nil
+}
+
+// getReferral получает реферальную связь по ID или возвращает domain.ErrReferralNotFound
+func getReferral(ctx context.Context, postgres PostgreSQLAdapter, referralIDStr string) (domain.Referral, error) {
+	referralID, err := domain.ReferralIDFromString(referralIDStr)
+	if err != nil {
+		return domain.Referral{}, domain.ErrReferralNotFound
+	}
+
+	referral, err := postgres.GetReferral(ctx, referralID)
+	if err != nil {
+		return domain.Referral{}, fmt.Errorf("ошибка при получении реферальной связи: %w", err)
+	}
+	if referral == nil {
+		return domain.Referral{}, domain.ErrReferralNotFound
+	}
+	return *referral, nil
+}
+
+func referralReviewToDTO(referral domain.Referral) dto.ReferralReviewOutput {
+	signals := make([]dto.FraudSignalOutput, len(referral.FraudSignals))
+	for i, signal := range referral.FraudSignals {
+		signals[i] = dto.FraudSignalOutput{
+			Rule:   signal.Rule,
+			Score:  signal.Score,
+			Reason: signal.Reason,
+		}
+	}
+
+	output := dto.ReferralReviewOutput{
+		ReferralID:     referral.ID.String(),
+		ReferrerID:     referral.ReferrerID.String(),
+		ReferredUserID: referral.ReferredUserID.String(),
+		Status:         string(referral.Status),
+		FraudScore:     referral.FraudScore,
+		Signals:        signals,
+		ReviewedAt:     referral.ReviewedAt,
+		CreatedAt:      referral.CreatedAt,
+	}
+	if referral.ReviewedBy != nil {
+		output.ReviewedBy = referral.ReviewedBy.String()
+	}
+	return output
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+const (
+	// defaultReferredUsersLimit размер страницы списка приглашенных пользователей по умолчанию
+	defaultReferredUsersLimit = 50
+	// maxReferredUsersLimit максимальный размер страницы списка приглашенных пользователей
+	maxReferredUsersLimit = 100
+)
+
+type ListReferredUsersUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListReferredUsersUseCase(postgres PostgreSQLAdapter) *ListReferredUsersUseCase {
+	return &ListReferredUsersUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает страницу пользователей, приглашенных пользователем, начиная с последних.
+// cursor - значение next_cursor из предыдущего ответа, пустая строка означает первую страницу.
+func (uc *ListReferredUsersUseCase) Execute(ctx context.Context, userIDStr string, limit int, cursor string) (dto.ListReferredUsersOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.ListReferredUsersOutput{}, err
+	}
+
+	user, err := uc.postgres.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.ListReferredUsersOutput{}, err
+	}
+	if user == nil {
+		return dto.ListReferredUsersOutput{}, domain.ErrUserNotFound
+	}
+
+	if limit <= 0 {
+		limit = defaultReferredUsersLimit
+	}
+	if limit > maxReferredUsersLimit {
+		limit = maxReferredUsersLimit
+	}
+
+	var after *ReferredUsersCursor
+	if cursor != "" {
+		decoded, err := decodeReferredUsersCursor(cursor)
+		if err != nil {
+			return dto.ListReferredUsersOutput{}, err
+		}
+		after = &decoded
+	}
+
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	referred, err := uc.postgres.ListReferredUsers(ctx, userID, after, limit+1)
+	if err != nil {
+		return dto.ListReferredUsersOutput{}, fmt.Errorf("ошибка при получении приглашенных пользователей: %w", err)
+	}
+
+	output := dto.ListReferredUsersOutput{
+		UserID: userID.String(),
+	}
+
+	if len(referred) > limit {
+		referred = referred[:limit]
+		last := referred[len(referred)-1].Referral
+		output.NextCursor = encodeReferredUsersCursor(ReferredUsersCursor{
+			CreatedAt:  last.CreatedAt,
+			ReferralID: last.ID.String(),
+		})
+	}
+
+	output.Referrals = make([]dto.ReferredUserOutput, len(referred))
+	for i, item := range referred {
+		output.Referrals[i] = dto.ReferredUserOutput{
+			UserID:              item.Referral.ReferredUserID.String(),
+			Username:            item.Username,
+			JoinedAt:            item.JoinedAt,
+			ReferredAt:          item.Referral.CreatedAt,
+			Status:              string(item.Referral.Status),
+			BonusPoints:         item.Referral.BonusPoints,
+			ReferrerBonusPoints: item.Referral.ReferrerBonusPoints,
+			PointsGenerated:     item.GeneratedPoints,
+		}
+	}
+
+	return output, nil
+}
+
+// encodeReferredUsersCursor кодирует курсор в непрозрачную для клиента строку
+func encodeReferredUsersCursor(cursor ReferredUsersCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeReferredUsersCursor разбирает курсор списка приглашенных пользователей, полученный от клиента
+func decodeReferredUsersCursor(value string) (ReferredUsersCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ReferredUsersCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+
+	var cursor ReferredUsersCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.CreatedAt.IsZero() {
+		return ReferredUsersCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+	if _, err := domain.ReferralIDFromString(cursor.ReferralID); err != nil {
+		return ReferredUsersCursor{}, fmt.Errorf("%w: некорректный курсор", domain.ErrInvalidPagination)
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+// maxRewardOrdersPage максимальное число заказов в одном ответе очереди выдачи
+const maxRewardOrdersPage = 100
+
+type ListRewardOrdersUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListRewardOrdersUseCase(postgres PostgreSQLAdapter) *ListRewardOrdersUseCase {
+	return &ListRewardOrdersUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает заказы с указанным статусом, начиная с самых старых.
+// По умолчанию возвращаются заказы, ожидающие выдачи.
+func (uc *ListRewardOrdersUseCase) Execute(ctx context.Context, statusStr string, limit int) (dto.ListRewardOrdersOutput, error) {
+	status := domain.RewardOrderPending
+	if statusStr != "" {
+		var err error
+		status, err = domain.NewRewardOrderStatus(statusStr)
+		if err != nil {
+			return dto.ListRewardOrdersOutput{}, err
+		}
+	}
+
+	if limit <= 0 || limit > maxRewardOrdersPage {
+		limit = maxRewardOrdersPage
+	}
+
+	orders, err := uc.postgres.ListRewardOrdersByStatus(ctx, status, limit)
+	if err != nil {
+		return dto.ListRewardOrdersOutput{}, fmt.Errorf("ошибка при получении заказов: %w", err)
+	}
+
+	return rewardOrdersToDTO(orders), nil
+}
+
+// getRewardOrder получает заказ по ID или возвращает domain.ErrOrderNotFound
+func getRewardOrder(ctx context.Context, postgres PostgreSQLAdapter, orderIDStr string) (domain.RewardOrder, error) {
+	orderID, err := domain.RewardOrderIDFromString(orderIDStr)
+	if err != nil {
+		return domain.RewardOrder{}, err
+	}
+
+	order, err := postgres.GetRewardOrder(ctx, orderID)
+	if err != nil {
+		return domain.RewardOrder{}, fmt.Errorf("ошибка при получении заказа: %w", err)
+	}
+	if order == nil {
+		return domain.RewardOrder{}, domain.ErrOrderNotFound
+	}
+	return *order, nil
+}
+
+func rewardOrdersToDTO(orders []domain.RewardOrder) dto.ListRewardOrdersOutput {
+	result := make([]dto.RewardOrderOutput, len(orders))
+	for i := range orders {
+		result[i] = rewardOrderToDTO(orders[i])
+	}
+
+	return dto.ListRewardOrdersOutput{
+		Orders: result,
+		Total:  len(result),
+	}
+}
+
+func rewardOrderToDTO(order domain.RewardOrder) dto.RewardOrderOutput {
+	return dto.RewardOrderOutput{
+		ID:          order.ID.String(),
+		UserID:      order.UserID.String(),
+		RewardID:    order.RewardID.String(),
+		Title:       order.Title,
+		Cost:        order.Cost,
+		Status:      string(order.Status),
+		CreatedAt:   order.CreatedAt,
+		FulfilledAt: order.FulfilledAt,
+	}
+}
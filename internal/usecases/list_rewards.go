@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ListRewardsUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListRewardsUseCase(postgres PostgreSQLAdapter) *ListRewardsUseCase {
+	return &ListRewardsUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает товары магазина наград. Неактивные товары и товары вне периода
+// продаж возвращаются только при includeUnavailable.
+func (uc *ListRewardsUseCase) Execute(ctx context.Context, includeUnavailable bool) (dto.ListRewardsOutput, error) {
+	rewards, err := uc.postgres.ListRewards(ctx, includeUnavailable, time.Now())
+	if err != nil {
+		return dto.ListRewardsOutput{}, fmt.Errorf("ошибка при получении товаров: %w", err)
+	}
+
+	result := make([]dto.RewardOutput, len(rewards))
+	for i := range rewards {
+		result[i] = rewardToDTO(rewards[i])
+	}
+
+	return dto.ListRewardsOutput{
+		Rewards: result,
+		Total:   len(result),
+	}, nil
+}
+
+// getReward получает товар по ID или возвращает domain.ErrRewardNotFound
+func getReward(ctx context.Context, postgres PostgreSQLAdapter, rewardIDStr string) (domain.Reward, error) {
+	rewardID, err := domain.RewardIDFromString(rewardIDStr)
+	if err != nil {
+		return domain.Reward{}, err
+	}
+
+	reward, err := postgres.GetReward(ctx, rewardID)
+	if err != nil {
+		return domain.Reward{}, fmt.Errorf("ошибка при получении товара: %w", err)
+	}
+	if reward == nil {
+		return domain.Reward{}, domain.ErrRewardNotFound
+	}
+	return *reward, nil
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/dto"
+)
+
+type ListSeasonsUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListSeasonsUseCase(postgres PostgreSQLAdapter) *ListSeasonsUseCase {
+	return &ListSeasonsUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает все сезоны, начиная с самых поздних
+func (uc *ListSeasonsUseCase) Execute(ctx context.Context) (dto.ListSeasonsOutput, error) {
+	seasons, err := uc.postgres.ListSeasons(ctx)
+	if err != nil {
+		return dto.ListSeasonsOutput{}, fmt.Errorf("ошибка при получении сезонов: %w", err)
+	}
+
+	now := time.Now()
+	result := make([]dto.SeasonOutput, len(seasons))
+	for i := range seasons {
+		result[i] = seasonToDTO(seasons[i], now)
+	}
+
+	return dto.ListSeasonsOutput{
+		Seasons: result,
+		Total:   len(result),
+	}, nil
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+// maxSubmissionsPage максимальное число заявок в одном ответе очереди модерации
+const maxSubmissionsPage = 100
+
+type ListSubmissionsUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListSubmissionsUseCase(postgres PostgreSQLAdapter) *ListSubmissionsUseCase {
+	return &ListSubmissionsUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает очередь заявок с указанным статусом, начиная с самых старых.
+// По умолчанию возвращаются заявки, ожидающие проверки.
+func (uc *ListSubmissionsUseCase) Execute(ctx context.Context, statusStr string, limit int) (dto.ListSubmissionsOutput, error) {
+	status := domain.SubmissionPending
+	if statusStr != "" {
+		var err error
+		status, err = domain.NewSubmissionStatus(statusStr)
+		if err != nil {
+			return dto.ListSubmissionsOutput{}, err
+		}
+	}
+
+	if limit <= 0 || limit > maxSubmissionsPage {
+		limit = maxSubmissionsPage
+	}
+
+	submissions, err := uc.postgres.ListSubmissionsByStatus(ctx, status, limit)
+	if err != nil {
+		return dto.ListSubmissionsOutput{}, fmt.Errorf("ошибка при получении заявок: %w", err)
+	}
+
+	return submissionsToDTO(submissions), nil
+}
+
+// getSubmission получает заявку по ID или возвращает domain.ErrSubmissionNotFound
+func getSubmission(ctx context.Context, postgres PostgreSQLAdapter, submissionIDStr string) (domain.TaskSubmission, error) {
+	submissionID, err := domain.SubmissionIDFromString(submissionIDStr)
+	if err != nil {
+		return domain.TaskSubmission{}, err
+	}
+
+	submission, err := postgres.GetSubmission(ctx, submissionID)
+	if err != nil {
+		return domain.TaskSubmission{}, fmt.Errorf("ошибка при получении заявки: %w", err)
+	}
+	if submission == nil {
+		return domain.TaskSubmission{}, domain.ErrSubmissionNotFound
+	}
+	return *submission, nil
+}
+
+func submissionsToDTO(submissions []domain.TaskSubmission) dto.ListSubmissionsOutput {
+	result := make([]dto.SubmissionOutput, len(submissions))
+	for i := range submissions {
+		result[i] = submissionToDTO(submissions[i])
+	}
+
+	return dto.ListSubmissionsOutput{
+		Submissions: result,
+		Total:       len(result),
+	}
+}
+
+func submissionToDTO(submission domain.TaskSubmission) dto.SubmissionOutput {
+	return dto.SubmissionOutput{
+		ID:              submission.ID.String(),
+		UserID:          submission.UserID.String(),
+		TaskType:        submission.TaskType.String(),
+		ProofType:       submission.ProofType.String(),
+		Proof:           submission.Proof,
+		Points:          submission.Points,
+		Status:          submission.Status.String(),
+		RejectionReason: submission.RejectionReason,
+		ReviewedAt:      submission.ReviewedAt,
+		CreatedAt:       submission.CreatedAt,
+	}
+}
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ListUserBadgesUseCase struct {
+	postgres     PostgreSQLAdapter
+	achievements *AchievementEngine
+}
+
+func NewListUserBadgesUseCase(postgres PostgreSQLAdapter, achievements *AchievementEngine) *ListUserBadgesUseCase {
+	return &ListUserBadgesUseCase{
+		postgres:     postgres,
+		achievements: achievements,
+	}
+}
+
+// Execute получает значки пользователя в порядке получения
+func (uc *ListUserBadgesUseCase) Execute(ctx context.Context, userIDStr string) (dto.ListUserBadgesOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.ListUserBadgesOutput{}, err
+	}
+
+	user, err := uc.postgres.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.ListUserBadgesOutput{}, err
+	}
+	if user == nil {
+		return dto.ListUserBadgesOutput{}, domain.ErrUserNotFound
+	}
+
+	badges, err := uc.postgres.GetUserBadges(ctx, userID)
+	if err != nil {
+		return dto.ListUserBadgesOutput{}, fmt.Errorf("ошибка при получении значков: %w", err)
+	}
+
+	return dto.ListUserBadgesOutput{
+		UserID: userID.String(),
+		Badges: userBadgesToDTO(uc.achievements.Badges(), badges),
+	}, nil
+}
+
+// userBadgesToDTO преобразует значки пользователя, дополняя их описанием из определений.
+// Значки, определения которых больше нет, пропускаются.
+func userBadgesToDTO(definitions []domain.Badge, badges []domain.UserBadge) []dto.BadgeOutput {
+	byKey := make(map[string]domain.Badge, len(definitions))
+	for _, badge := range definitions {
+		byKey[badge.Key] = badge
+	}
+
+	result := make([]dto.BadgeOutput, 0, len(badges))
+	for _, badge := range badges {
+		definition, ok := byKey[badge.BadgeKey]
+		if !ok {
+			continue
+		}
+		result = append(result, dto.BadgeOutput{
+			Key:         definition.Key,
+			Title:       definition.Title,
+			Description: definition.Description,
+			AwardedAt:   badge.AwardedAt,
+		})
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ListUserRewardOrdersUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListUserRewardOrdersUseCase(postgres PostgreSQLAdapter) *ListUserRewardOrdersUseCase {
+	return &ListUserRewardOrdersUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает историю заказов пользователя, начиная с последних
+func (uc *ListUserRewardOrdersUseCase) Execute(ctx context.Context, userIDStr string) (dto.ListRewardOrdersOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.ListRewardOrdersOutput{}, err
+	}
+
+	orders, err := uc.postgres.ListRewardOrdersByUserID(ctx, userID)
+	if err != nil {
+		return dto.ListRewardOrdersOutput{}, fmt.Errorf("ошибка при получении заказов: %w", err)
+	}
+
+	return rewardOrdersToDTO(orders), nil
+}
@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ListUserSubmissionsUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewListUserSubmissionsUseCase(postgres PostgreSQLAdapter) *ListUserSubmissionsUseCase {
+	return &ListUserSubmissionsUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute возвращает заявки пользователя вместе с причинами отклонения
+func (uc *ListUserSubmissionsUseCase) Execute(ctx context.Context, userIDStr string) (dto.ListSubmissionsOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.ListSubmissionsOutput{}, err
+	}
+
+	user, err := uc.postgres.GetUserByID(ctx, userID)
+	if err != nil {
+		return dto.ListSubmissionsOutput{}, err
+	}
+	if user == nil {
+		return dto.ListSubmissionsOutput{}, domain.ErrUserNotFound
+	}
+
+	submissions, err := uc.postgres.ListSubmissionsByUserID(ctx, userID)
+	if err != nil {
+		return dto.ListSubmissionsOutput{}, fmt.Errorf("ошибка при получении заявок: %w", err)
+	}
+
+	return submissionsToDTO(submissions), nil
+}
@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type LogoutUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewLogoutUseCase(postgres PostgreSQLAdapter) *LogoutUseCase {
+	return &LogoutUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute завершает сессию, отзывая семейство переданного refresh токена
+func (uc *LogoutUseCase) Execute(ctx context.Context, input dto.LogoutInput) error {
+	refreshToken, err := uc.postgres.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(input.RefreshToken))
+	if err != nil {
+		return fmt.Errorf("ошибка при поиске refresh токена: %w", err)
+	}
+	if refreshToken == nil {
+		return nil
+	}
+
+	if err := uc.postgres.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyID); err != nil {
+		return fmt.Errorf("ошибка при отзыве refresh токенов: %w", err)
+	}
+	return nil
+}
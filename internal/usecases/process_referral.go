@@ -9,41 +9,45 @@ import (
 )
 
 type ProcessReferralUseCase struct {
-	postgres PostgreSQLAdapter
+	postgres     PostgreSQLAdapter
+	vester       *ReferralVester
+	achievements *AchievementEngine
 }
 
-func NewProcessReferralUseCase(postgres PostgreSQLAdapter) *ProcessReferralUseCase {
+func NewProcessReferralUseCase(postgres PostgreSQLAdapter, vester *ReferralVester, achievements *AchievementEngine) *ProcessReferralUseCase {
 	return &ProcessReferralUseCase{
-		postgres: postgres,
+		postgres:     postgres,
+		vester:       vester,
+		achievements: achievements,
 	}
 }
 
-// Execute выполняет обработку реферального кода
+// Execute выполняет обработку реферального кода.
+// Бонусы начисляются, когда приглашенный пользователь выполнит условия политики,
+// до этого связь находится в состоянии pending. Подозрительная связь задерживается
+// в состоянии on_hold до решения модератора.
 func (uc *ProcessReferralUseCase) Execute(ctx context.Context, referredUserIDStr string, input dto.ProcessReferralInput) (dto.ProcessReferralOutput, error) {
 	referredUserID, err := domain.UserIDFromString(referredUserIDStr)
 	if err != nil {
 		return dto.ProcessReferralOutput{}, err
 	}
 
-	referrerID, err := domain.UserIDFromString(input.ReferrerID)
-	if err != nil {
-		return dto.ProcessReferralOutput{}, domain.ErrReferrerNotFound
+	code := input.Code
+	if code == "" {
+		code = input.ReferrerID
 	}
 
-	referredUser, err := uc.postgres.GetUserByID(ctx, referredUserID)
+	referrerID, err := resolveReferrer(ctx, uc.postgres, code)
 	if err != nil {
 		return dto.ProcessReferralOutput{}, err
 	}
-	if referredUser == nil {
-		return dto.ProcessReferralOutput{}, domain.ErrUserNotFound
-	}
 
-	referrer, err := uc.postgres.GetUserByID(ctx, referrerID)
+	referredUser, err := uc.postgres.GetUserByID(ctx, referredUserID)
 	if err != nil {
 		return dto.ProcessReferralOutput{}, err
 	}
-	if referrer == nil {
-		return dto.ProcessReferralOutput{}, domain.ErrReferrerNotFound
+	if referredUser == nil {
+		return dto.ProcessReferralOutput{}, domain.ErrUserNotFound
 	}
 
 	existingReferral, err := uc.postgres.GetReferralByReferredUserID(ctx, referredUserID)
@@ -54,36 +58,77 @@ func (uc *ProcessReferralUseCase) Execute(ctx context.Context, referredUserIDStr
 		return dto.ProcessReferralOutput{}, domain.ErrReferralExists
 	}
 
+	fingerprint := clientFingerprint(input.Client)
+
 	var referral domain.Referral
-	var newBalance domain.Balance
 
 	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
-		referral, err = domain.NewReferral(referrerID, referredUserID)
-		if err != nil {
-			return err
+		event := domain.NewClientEvent(referredUserID, domain.ClientEventReferral, fingerprint)
+		if err := uc.postgres.RecordClientEvent(ctx, event); err != nil {
+			return fmt.Errorf("ошибка при сохранении отпечатка клиента: %w", err)
 		}
 
-		if err := uc.postgres.CreateReferral(ctx, referral); err != nil {
-			return fmt.Errorf("ошибка при создании реферальной связи: %w", err)
-		}
+		referral, err = uc.vester.Create(ctx, referrerID, referredUserID, fingerprint)
+		return err
+	})
 
-		newBalance = referredUser.Balance.Add(referral.BonusPoints)
-		if err := uc.postgres.UpdateUserBalance(ctx, referredUserID, newBalance); err != nil {
-			return fmt.Errorf("ошибка при обновлении баланса: %w", err)
-		}
+	if err != nil {
+		return dto.ProcessReferralOutput{}, err
+	}
 
-		return nil
-	})
+	uc.achievements.EvaluateCommitted(ctx, referrerID, referredUserID)
 
+	referredUser, err = uc.postgres.GetUserByID(ctx, referredUserID)
 	if err != nil {
 		return dto.ProcessReferralOutput{}, err
 	}
+	if referredUser == nil {
+		return dto.ProcessReferralOutput{}, domain.ErrUserNotFound
+	}
 
 	return dto.ProcessReferralOutput{
-		ReferralID:     referral.ID.String(),
-		ReferrerID:     referrerID.String(),
-		ReferredUserID: referredUserID.String(),
-		BonusPoints:    referral.BonusPoints,
-		NewBalance:     newBalance.Value(),
+		ReferralID:          referral.ID.String(),
+		ReferrerID:          referrerID.String(),
+		ReferredUserID:      referredUserID.String(),
+		Status:              string(referral.Status),
+		BonusPoints:         referral.BonusPoints,
+		ReferrerBonusPoints: referral.ReferrerBonusPoints,
+		NewBalance:          referredUser.Balance.Value(),
 	}, nil
 }
+
+// resolveReferrer находит пригласившего пользователя по реферальному коду.
+// Для совместимости со старыми клиентами вместо кода принимается UUID пользователя.
+func resolveReferrer(ctx context.Context, postgres PostgreSQLAdapter, value string) (domain.UserID, error) {
+	referrerID, err := domain.UserIDFromString(value)
+	if err != nil {
+		code := domain.NormalizeReferralCode(value)
+		if code == "" {
+			return domain.UserID{}, domain.ErrReferrerNotFound
+		}
+
+		referralCode, err := postgres.GetReferralCode(ctx, code)
+		if err != nil {
+			return domain.UserID{}, fmt.Errorf("ошибка при поиске реферального кода: %w", err)
+		}
+		if referralCode == nil {
+			return domain.UserID{}, domain.ErrReferrerNotFound
+		}
+		referrerID = referralCode.UserID
+	}
+
+	referrer, err := postgres.GetUserByID(ctx, referrerID)
+	if err != nil {
+		return domain.UserID{}, err
+	}
+	if referrer == nil {
+		return domain.UserID{}, domain.ErrReferrerNotFound
+	}
+
+	return referrerID, nil
+}
+
+// clientFingerprint строит отпечаток клиента из данных запроса
+func clientFingerprint(client dto.ClientInfo) domain.ClientFingerprint {
+	return domain.NewClientFingerprint(client.IP, client.UserAgent, client.DeviceID)
+}
@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ProcessTaskCallbackUseCase struct {
+	postgres       PostgreSQLAdapter
+	verifier       TaskVerifier
+	completeTaskUC *CompleteTaskUseCase
+}
+
+func NewProcessTaskCallbackUseCase(postgres PostgreSQLAdapter, verifier TaskVerifier, completeTaskUC *CompleteTaskUseCase) *ProcessTaskCallbackUseCase {
+	return &ProcessTaskCallbackUseCase{
+		postgres:       postgres,
+		verifier:       verifier,
+		completeTaskUC: completeTaskUC,
+	}
+}
+
+// Execute обрабатывает подписанный callback партнерского сервиса о выполнении задания.
+// Повторная доставка события с тем же event_id не начисляет поинты второй раз.
+func (uc *ProcessTaskCallbackUseCase) Execute(ctx context.Context, partnerStr, signature, timestamp string, body []byte) (dto.TaskCallbackOutput, error) {
+	partner, err := domain.NewExternalProvider(partnerStr)
+	if err != nil {
+		return dto.TaskCallbackOutput{}, err
+	}
+
+	callback, err := uc.verifier.Verify(partner, signature, timestamp, body)
+	if err != nil {
+		return dto.TaskCallbackOutput{}, err
+	}
+
+	taskType, err := domain.NewTaskType(callback.TaskType)
+	if err != nil {
+		return dto.TaskCallbackOutput{}, err
+	}
+
+	account, err := uc.postgres.GetExternalAccount(ctx, partner, callback.ExternalAccountID)
+	if err != nil {
+		return dto.TaskCallbackOutput{}, fmt.Errorf("ошибка при получении внешнего аккаунта: %w", err)
+	}
+	if account == nil {
+		return dto.TaskCallbackOutput{}, domain.ErrExternalAccountNotFound
+	}
+
+	output := dto.TaskCallbackOutput{
+		Status:   "duplicate",
+		EventID:  callback.EventID,
+		UserID:   account.UserID.String(),
+		TaskType: taskType.String(),
+	}
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		recorded, err := uc.postgres.RecordWebhookEvent(ctx, partner, callback.EventID)
+		if err != nil {
+			return fmt.Errorf("ошибка при сохранении события: %w", err)
+		}
+		if !recorded {
+			return nil
+		}
+
+		completed, err := uc.completeTaskUC.completeVerified(ctx, account.UserID, taskType)
+		if err != nil {
+			return err
+		}
+
+		output.Status = completed.Status
+		output.TaskID = completed.TaskID
+		output.Points = completed.Points
+		return nil
+	})
+
+	if err != nil {
+		return dto.TaskCallbackOutput{}, err
+	}
+
+	if output.TaskID != "" {
+		uc.completeTaskUC.achievements.EvaluateCommittedWithReferrer(ctx, account.UserID)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,283 @@
+package usecases_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/adapters/webhook"
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/usecases"
+)
+
+const callbackSecret = "partner-secret"
+
+// signCallback подписывает callback так же, как партнерский сервис
+func signCallback(secret string, sentAt time.Time, body []byte) (signature, timestamp string) {
+	timestamp = strconv.FormatInt(sentAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil)), timestamp
+}
+
+// callbackStore хранилище в памяти с методами, которые использует обработка callback.
+// Остальные методы PostgreSQLAdapter не реализованы и при вызове паникуют.
+type callbackStore struct {
+	usecases.PostgreSQLAdapter
+
+	user     domain.User
+	account  domain.ExternalAccount
+	task     domain.Task
+	referral *domain.Referral
+	events   map[string]bool
+	tasks    []domain.UserTask
+	entries  []domain.LedgerEntry
+}
+
+func (s *callbackStore) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func (s *callbackStore) GetExternalAccount(ctx context.Context, provider domain.ExternalProvider, externalID string) (*domain.ExternalAccount, error) {
+	if provider != s.account.Provider || externalID != s.account.ExternalID {
+		return nil, nil
+	}
+	account := s.account
+	return &account, nil
+}
+
+func (s *callbackStore) RecordWebhookEvent(ctx context.Context, partner domain.ExternalProvider, eventID string) (bool, error) {
+	key := partner.String() + "/" + eventID
+	if s.events[key] {
+		return false, nil
+	}
+	s.events[key] = true
+	return true, nil
+}
+
+func (s *callbackStore) GetCatalogTask(ctx context.Context, key domain.TaskType) (*domain.Task, error) {
+	if key != s.task.Key {
+		return nil, nil
+	}
+	task := s.task
+	return &task, nil
+}
+
+func (s *callbackStore) GetUserByID(ctx context.Context, userID domain.UserID) (*domain.User, error) {
+	user := s.user
+	return &user, nil
+}
+
+func (s *callbackStore) LockUser(ctx context.Context, userID domain.UserID) error {
+	return nil
+}
+
+func (s *callbackStore) GetTaskCompletionStats(ctx context.Context, userID domain.UserID, taskType domain.TaskType) (int, *time.Time, error) {
+	return 0, nil, nil
+}
+
+func (s *callbackStore) CreateTask(ctx context.Context, task domain.UserTask) error {
+	s.tasks = append(s.tasks, task)
+	return nil
+}
+
+func (s *callbackStore) GetReferralByReferredUserID(ctx context.Context, referredUserID domain.UserID) (*domain.Referral, error) {
+	if s.referral == nil || s.referral.ReferredUserID != referredUserID {
+		return nil, nil
+	}
+	referral := *s.referral
+	return &referral, nil
+}
+
+func (s *callbackStore) UpdateReferralStatus(ctx context.Context, referral domain.Referral, from domain.ReferralStatus) (bool, error) {
+	if s.referral == nil || s.referral.Status != from {
+		return false, nil
+	}
+	*s.referral = referral
+	return true, nil
+}
+
+func (s *callbackStore) GetTasksByUserID(ctx context.Context, userID domain.UserID) ([]domain.UserTask, error) {
+	var result []domain.UserTask
+	for _, task := range s.tasks {
+		if task.UserID == userID {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+func (s *callbackStore) AddLedgerEntry(ctx context.Context, entry domain.LedgerEntry) (domain.Balance, error) {
+	s.entries = append(s.entries, entry)
+	if entry.UserID != s.user.ID {
+		return domain.NewBalance(entry.Amount), nil
+	}
+	s.user.AddPoints(entry.Amount)
+	return s.user.Balance, nil
+}
+
+// entriesFor возвращает записи журнала пользователя
+func (s *callbackStore) entriesFor(userID domain.UserID) []domain.LedgerEntry {
+	var result []domain.LedgerEntry
+	for _, entry := range s.entries {
+		if entry.UserID == userID {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func (s *callbackStore) GetUserBadges(ctx context.Context, userID domain.UserID) ([]domain.UserBadge, error) {
+	return nil, nil
+}
+
+func newCallbackUseCase(t *testing.T) (*usecases.ProcessTaskCallbackUseCase, *callbackStore) {
+	t.Helper()
+
+	vestingPolicy, err := domain.NewReferralVestingPolicy(0, 0, 1, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newCallbackUseCaseWithPolicy(t, vestingPolicy)
+}
+
+func newCallbackUseCaseWithPolicy(t *testing.T, vestingPolicy domain.ReferralVestingPolicy) (*usecases.ProcessTaskCallbackUseCase, *callbackStore) {
+	t.Helper()
+
+	user, err := domain.NewUser("partner_user", "partner@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := domain.NewExternalAccount(domain.ExternalProviderTelegram, "42", user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := domain.NewTask("subscribe_telegram", "Подписка на Telegram", "", 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.ProofType = domain.ProofTypeExternal
+
+	store := &callbackStore{
+		user:    user,
+		account: account,
+		task:    task,
+		events:  make(map[string]bool),
+	}
+
+	verifier, err := webhook.NewHMACTaskVerifier(map[string]string{"telegram": callbackSecret}, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completeTaskUC := usecases.NewCompleteTaskUseCase(
+		store,
+		nil,
+		usecases.NewCommissionPayer(store, domain.CommissionPlan{}),
+		usecases.NewReferralVester(store, vestingPolicy, usecases.NewFraudDetector(0)),
+		usecases.NewAchievementEngine(store, nil, time.UTC),
+		time.UTC,
+	)
+
+	return usecases.NewProcessTaskCallbackUseCase(store, verifier, completeTaskUC), store
+}
+
+func TestProcessTaskCallbackCreditsOnce(t *testing.T) {
+	uc, store := newCallbackUseCase(t)
+	ctx := context.Background()
+
+	body := []byte(`{"event_id":"evt-1","external_account_id":"42","task_type":"subscribe_telegram"}`)
+	signature, timestamp := signCallback(callbackSecret, time.Now(), body)
+
+	first, err := uc.Execute(ctx, "telegram", signature, timestamp, body)
+	if err != nil {
+		t.Fatalf("первая доставка: %v", err)
+	}
+	if first.Status != "completed" || first.Points != 50 {
+		t.Fatalf("первая доставка: %+v", first)
+	}
+
+	replayed, err := uc.Execute(ctx, "telegram", signature, timestamp, body)
+	if err != nil {
+		t.Fatalf("повторная доставка: %v", err)
+	}
+	if replayed.Status != "duplicate" || replayed.TaskID != "" {
+		t.Errorf("повторная доставка: %+v, ожидался статус duplicate", replayed)
+	}
+
+	if len(store.tasks) != 1 || len(store.entries) != 1 {
+		t.Errorf("заданий %d, записей журнала %d, ожидалось по одному", len(store.tasks), len(store.entries))
+	}
+}
+
+func TestProcessTaskCallbackRejectsInvalidSignature(t *testing.T) {
+	body := []byte(`{"event_id":"evt-1","external_account_id":"42","task_type":"subscribe_telegram"}`)
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		secret string
+		sentAt time.Time
+		body   []byte
+	}{
+		{"tampered body", callbackSecret, now, []byte(`{"event_id":"evt-1","external_account_id":"7","task_type":"subscribe_telegram"}`)},
+		{"wrong secret", "other-secret", now, body},
+		{"stale timestamp", callbackSecret, now.Add(-time.Hour), body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, store := newCallbackUseCase(t)
+
+			signature, timestamp := signCallback(tt.secret, tt.sentAt, body)
+			_, err := uc.Execute(context.Background(), "telegram", signature, timestamp, tt.body)
+
+			if !errors.Is(err, domain.ErrInvalidSignature) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, domain.ErrInvalidSignature)
+			}
+			if len(store.events) != 0 || len(store.entries) != 0 {
+				t.Errorf("callback с неверной подписью изменил данные")
+			}
+		})
+	}
+}
+
+func TestProcessTaskCallbackChecksTaskAgainstCatalog(t *testing.T) {
+	tests := []struct {
+		name     string
+		taskType string
+		wantErr  error
+	}{
+		{"task added by admin", "follow_discord", nil},
+		{"task missing from catalog", "subscribe_twitter", domain.ErrInvalidTaskType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, store := newCallbackUseCase(t)
+			store.task.Key = domain.TaskType("follow_discord")
+
+			body := []byte(`{"event_id":"evt-1","external_account_id":"42","task_type":"` + tt.taskType + `"}`)
+			signature, timestamp := signCallback(callbackSecret, time.Now(), body)
+			_, err := uc.Execute(context.Background(), "telegram", signature, timestamp, body)
+
+			if tt.wantErr == nil {
+				if err != nil || len(store.entries) != 1 {
+					t.Fatalf("ошибка %v, записей журнала %d, ожидалось начисление", err, len(store.entries))
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, tt.wantErr)
+			}
+			if len(store.entries) != 0 {
+				t.Errorf("за задание вне каталога начислены поинты")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type RedeemRewardUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewRedeemRewardUseCase(postgres PostgreSQLAdapter) *RedeemRewardUseCase {
+	return &RedeemRewardUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute оформляет заказ товара за поинты. Списание поинтов, уменьшение остатка
+// и создание заказа выполняются в одной транзакции: при нехватке поинтов возвращается
+// *domain.InsufficientBalanceError, при нехватке товара - domain.ErrRewardOutOfStock.
+func (uc *RedeemRewardUseCase) Execute(ctx context.Context, userIDStr string, input dto.RedeemRewardInput) (dto.RedeemRewardOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.RedeemRewardOutput{}, err
+	}
+
+	reward, err := getReward(ctx, uc.postgres, input.RewardID)
+	if err != nil {
+		return dto.RedeemRewardOutput{}, err
+	}
+
+	if err := reward.CheckAvailable(time.Now()); err != nil {
+		return dto.RedeemRewardOutput{}, err
+	}
+
+	order, err := domain.NewRewardOrder(userID, reward)
+	if err != nil {
+		return dto.RedeemRewardOutput{}, err
+	}
+
+	entry, err := order.LedgerEntry()
+	if err != nil {
+		return dto.RedeemRewardOutput{}, err
+	}
+	order.LedgerEntryID = entry.ID
+
+	var balance domain.Balance
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		// Заказы одного пользователя выполняются последовательно, чтобы лимит на пользователя
+		// и баланс проверялись по актуальным данным
+		if err := uc.postgres.LockUser(ctx, userID); err != nil {
+			return err
+		}
+
+		user, err := uc.postgres.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return domain.ErrUserNotFound
+		}
+
+		if _, err := user.Balance.Subtract(order.Cost); err != nil {
+			return err
+		}
+
+		if reward.PerUserLimit > 0 {
+			count, err := uc.postgres.CountRewardOrdersByUser(ctx, userID, reward.ID)
+			if err != nil {
+				return fmt.Errorf("ошибка при подсчете заказов: %w", err)
+			}
+			if count >= reward.PerUserLimit {
+				return domain.ErrRewardLimitReached
+			}
+		}
+
+		reserved, err := uc.postgres.ReserveRewardStock(ctx, reward.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка при резервировании товара: %w", err)
+		}
+		if !reserved {
+			return domain.ErrRewardOutOfStock
+		}
+
+		var debited bool
+		balance, debited, err = uc.postgres.DebitLedgerEntry(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("ошибка при списании поинтов: %w", err)
+		}
+		if !debited {
+			return &domain.InsufficientBalanceError{Balance: user.Balance.Value(), Required: order.Cost}
+		}
+
+		if err := uc.postgres.CreateRewardOrder(ctx, order); err != nil {
+			return fmt.Errorf("ошибка при создании заказа: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return dto.RedeemRewardOutput{}, err
+	}
+
+	return dto.RedeemRewardOutput{
+		Order:      rewardOrderToDTO(order),
+		NewBalance: balance.Value(),
+	}, nil
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+// ReferralVester создает реферальные связи и начисляет по ним бонусы,
+// когда приглашенный пользователь выполнил условия политики
+type ReferralVester struct {
+	postgres PostgreSQLAdapter
+	policy   domain.ReferralVestingPolicy
+	detector *FraudDetector
+}
+
+func NewReferralVester(postgres PostgreSQLAdapter, policy domain.ReferralVestingPolicy, detector *FraudDetector) *ReferralVester {
+	return &ReferralVester{
+		postgres: postgres,
+		policy:   policy,
+		detector: detector,
+	}
+}
+
+// Create создает реферальную связь и проверяет ее на мошенничество. Подозрительная связь
+// задерживается до решения модератора, остальные сразу проверяются по условиям политики:
+// пользователь, который выполнил их до указания реферера, получает бонус немедленно.
+// fingerprint - отпечаток клиента приглашенного пользователя.
+// Должен вызываться внутри транзакции.
+func (v *ReferralVester) Create(ctx context.Context, referrerID, referredUserID domain.UserID, fingerprint domain.ClientFingerprint) (domain.Referral, error) {
+	referral, err := domain.NewReferral(referrerID, referredUserID, v.policy)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+
+	referrer, err := v.postgres.GetUserByID(ctx, referrerID)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	if referrer == nil {
+		return domain.Referral{}, domain.ErrReferrerNotFound
+	}
+
+	referred, err := v.postgres.GetUserByID(ctx, referredUserID)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	if referred == nil {
+		return domain.Referral{}, domain.ErrUserNotFound
+	}
+
+	assessment, err := v.detector.Assess(ctx, FraudCheck{
+		Referrer:    *referrer,
+		Referred:    *referred,
+		Fingerprint: fingerprint,
+		Now:         time.Now(),
+	})
+	if err != nil {
+		return domain.Referral{}, err
+	}
+
+	referral.RecordFraudAssessment(assessment)
+	if v.detector.ShouldHold(assessment) {
+		if err := referral.Hold(); err != nil {
+			return domain.Referral{}, err
+		}
+	}
+
+	if err := v.postgres.CreateReferral(ctx, referral); err != nil {
+		return domain.Referral{}, fmt.Errorf("ошибка при создании реферальной связи: %w", err)
+	}
+
+	if referral.Status == domain.ReferralOnHold {
+		return referral, nil
+	}
+	return v.Evaluate(ctx, referral, time.Now())
+}
+
+// EvaluateUser проверяет условия по реферальной связи приглашенного пользователя, если она ожидает начисления.
+// Должен вызываться внутри транзакции.
+func (v *ReferralVester) EvaluateUser(ctx context.Context, referredUserID domain.UserID) error {
+	referral, err := v.postgres.GetReferralByReferredUserID(ctx, referredUserID)
+	if err != nil {
+		return fmt.Errorf("ошибка при проверке реферальной связи: %w", err)
+	}
+	if referral == nil || referral.Status != domain.ReferralPending {
+		return nil
+	}
+
+	_, err = v.Evaluate(ctx, *referral, time.Now())
+	return err
+}
+
+// Evaluate применяет к связи в состоянии pending решение политики на момент now:
+// начисляет бонусы обоим пользователям, отменяет связь или оставляет ее ожидать.
+// Должен вызываться внутри транзакции, возвращает связь в итоговом состоянии.
+func (v *ReferralVester) Evaluate(ctx context.Context, referral domain.Referral, now time.Time) (domain.Referral, error) {
+	referredUser, err := v.postgres.GetUserByID(ctx, referral.ReferredUserID)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	if referredUser == nil {
+		return domain.Referral{}, domain.ErrUserNotFound
+	}
+
+	tasks, err := v.postgres.GetTasksByUserID(ctx, referral.ReferredUserID)
+	if err != nil {
+		return domain.Referral{}, fmt.Errorf("ошибка при получении заданий: %w", err)
+	}
+
+	switch v.policy.Decide(referral, referredUser.CreatedAt, len(tasks), now) {
+	case domain.VestingVest:
+		return v.vest(ctx, referral, tasks, now)
+	case domain.VestingCancel:
+		if err := referral.Cancel(now); err != nil {
+			return domain.Referral{}, err
+		}
+		if _, err := v.postgres.UpdateReferralStatus(ctx, referral, domain.ReferralPending); err != nil {
+			return domain.Referral{}, fmt.Errorf("ошибка при отмене реферальной связи: %w", err)
+		}
+	}
+
+	return referral, nil
+}
+
+// vest переводит связь в состояние vested и начисляет бонусы обоим пользователям.
+// Рефереру также начисляются поинты за задания invite_friend, выполненные приглашенным
+// пользователем, пока связь ожидала начисления. tasks - выполненные им задания.
+func (v *ReferralVester) vest(ctx context.Context, referral domain.Referral, tasks []domain.UserTask, now time.Time) (domain.Referral, error) {
+	pending := referral
+	if err := referral.Vest(now); err != nil {
+		return domain.Referral{}, err
+	}
+
+	updated, err := v.postgres.UpdateReferralStatus(ctx, referral, domain.ReferralPending)
+	if err != nil {
+		return domain.Referral{}, fmt.Errorf("ошибка при сохранении реферальной связи: %w", err)
+	}
+	if !updated {
+		// Связь уже завершена параллельной операцией, бонусы начислены ею
+		return pending, nil
+	}
+
+	bonuses := []struct {
+		userID domain.UserID
+		points int
+	}{
+		{referral.ReferredUserID, referral.BonusPoints},
+		{referral.ReferrerID, referral.ReferrerBonusPoints},
+	}
+
+	for _, bonus := range bonuses {
+		if bonus.points == 0 {
+			continue
+		}
+
+		entry, err := domain.NewLedgerEntry(bonus.userID, bonus.points, domain.LedgerSourceReferral, referral.ID.String())
+		if err != nil {
+			return domain.Referral{}, err
+		}
+
+		if _, err := v.postgres.AddLedgerEntry(ctx, entry); err != nil {
+			return domain.Referral{}, fmt.Errorf("ошибка при начислении реферального бонуса: %w", err)
+		}
+	}
+
+	for _, task := range tasks {
+		if task.TaskType != domain.TaskTypeInviteFriend {
+			continue
+		}
+		if err := payInviteFriendCredit(ctx, v.postgres, referral.ReferrerID, task); err != nil {
+			return domain.Referral{}, err
+		}
+	}
+
+	return referral, nil
+}
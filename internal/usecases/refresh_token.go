@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type RefreshTokenUseCase struct {
+	postgres    PostgreSQLAdapter
+	tokenIssuer *TokenIssuer
+}
+
+func NewRefreshTokenUseCase(postgres PostgreSQLAdapter, tokenIssuer *TokenIssuer) *RefreshTokenUseCase {
+	return &RefreshTokenUseCase{
+		postgres:    postgres,
+		tokenIssuer: tokenIssuer,
+	}
+}
+
+// Execute обменивает refresh токен на новую пару токенов.
+// Повторное предъявление уже обменянного токена отзывает все семейство.
+// Отозванный, но не обменянный токен (например, после выхода) просто недействителен.
+func (uc *RefreshTokenUseCase) Execute(ctx context.Context, input dto.RefreshTokenInput) (dto.TokenOutput, error) {
+	refreshToken, err := uc.postgres.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(input.RefreshToken))
+	if err != nil {
+		return dto.TokenOutput{}, fmt.Errorf("ошибка при поиске refresh токена: %w", err)
+	}
+	if refreshToken == nil {
+		return dto.TokenOutput{}, domain.ErrInvalidRefreshToken
+	}
+
+	if refreshToken.IsUsed() {
+		return dto.TokenOutput{}, uc.revokeFamily(ctx, *refreshToken)
+	}
+	if refreshToken.IsRevoked() || refreshToken.IsExpired(time.Now()) {
+		return dto.TokenOutput{}, domain.ErrInvalidRefreshToken
+	}
+
+	var output dto.TokenOutput
+	concurrent := false
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		marked, err := uc.postgres.MarkRefreshTokenUsed(ctx, refreshToken.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка при обновлении refresh токена: %w", err)
+		}
+		if !marked {
+			// токен успели обменять или отозвать параллельным запросом
+			concurrent = true
+			return nil
+		}
+
+		user, err := uc.postgres.GetUserByID(ctx, refreshToken.UserID)
+		if err != nil {
+			return err
+		}
+
+		output, err = uc.tokenIssuer.RotateTokens(ctx, *user, *refreshToken)
+		return err
+	})
+
+	if err != nil {
+		return dto.TokenOutput{}, err
+	}
+	if concurrent {
+		return dto.TokenOutput{}, uc.rejectConcurrent(ctx, input.RefreshToken)
+	}
+
+	return output, nil
+}
+
+// rejectConcurrent возвращает ошибку для токена, который параллельный запрос успел обменять или отозвать.
+// Семейство отзывается, только если токен был обменян: отзыв при выходе повторным использованием не считается.
+func (uc *RefreshTokenUseCase) rejectConcurrent(ctx context.Context, plain string) error {
+	refreshToken, err := uc.postgres.GetRefreshTokenByHash(ctx, domain.HashRefreshToken(plain))
+	if err != nil {
+		return fmt.Errorf("ошибка при поиске refresh токена: %w", err)
+	}
+	if refreshToken == nil || !refreshToken.IsUsed() {
+		return domain.ErrInvalidRefreshToken
+	}
+	return uc.revokeFamily(ctx, *refreshToken)
+}
+
+// revokeFamily отзывает семейство токенов после обнаружения повторного использования
+func (uc *RefreshTokenUseCase) revokeFamily(ctx context.Context, refreshToken domain.RefreshToken) error {
+	slog.Warn("Повторное использование refresh токена, семейство отозвано",
+		"user_id", refreshToken.UserID.String(),
+		"family_id", refreshToken.FamilyID.String(),
+	)
+
+	if err := uc.postgres.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyID); err != nil {
+		return fmt.Errorf("ошибка при отзыве refresh токенов: %w", err)
+	}
+	return domain.ErrRefreshTokenReused
+}
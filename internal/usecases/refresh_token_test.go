@@ -0,0 +1,109 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+	"user-rewards-api/internal/usecases"
+)
+
+// refreshTokenStore хранит один refresh токен и считает отзывы семейства
+type refreshTokenStore struct {
+	usecases.PostgreSQLAdapter
+
+	token   domain.RefreshToken
+	revoked int
+	// logoutOnMark имитирует выход, выполненный параллельно с обменом токена
+	logoutOnMark bool
+}
+
+func (s *refreshTokenStore) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func (s *refreshTokenStore) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	if tokenHash != s.token.TokenHash {
+		return nil, nil
+	}
+	token := s.token
+	return &token, nil
+}
+
+func (s *refreshTokenStore) MarkRefreshTokenUsed(ctx context.Context, tokenID domain.RefreshTokenID) (bool, error) {
+	if s.logoutOnMark {
+		now := time.Now()
+		s.token.RevokedAt = &now
+	}
+	if s.token.IsUsed() || s.token.IsRevoked() {
+		return false, nil
+	}
+	now := time.Now()
+	s.token.UsedAt = &now
+	return true, nil
+}
+
+func (s *refreshTokenStore) RevokeRefreshTokenFamily(ctx context.Context, familyID domain.RefreshTokenID) error {
+	s.revoked++
+	now := time.Now()
+	s.token.RevokedAt = &now
+	return nil
+}
+
+func newRefreshTokenStore(t *testing.T) (*refreshTokenStore, string) {
+	t.Helper()
+
+	userID, err := domain.NewUserID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, plain, err := domain.NewRefreshToken(userID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &refreshTokenStore{token: token}, plain
+}
+
+func TestRefreshTokenRejectsLoggedOutTokenWithoutRevokingFamily(t *testing.T) {
+	store, plain := newRefreshTokenStore(t)
+	revokedAt := time.Now()
+	store.token.RevokedAt = &revokedAt
+
+	_, err := usecases.NewRefreshTokenUseCase(store, nil).Execute(context.Background(), dto.RefreshTokenInput{RefreshToken: plain})
+	if !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Fatalf("ожидалась ошибка недействительного токена, получено %v", err)
+	}
+	if store.revoked != 0 {
+		t.Errorf("семейство отозвано %d раз, ожидалось 0", store.revoked)
+	}
+}
+
+func TestRefreshTokenRejectsTokenLoggedOutConcurrently(t *testing.T) {
+	store, plain := newRefreshTokenStore(t)
+	store.logoutOnMark = true
+
+	_, err := usecases.NewRefreshTokenUseCase(store, nil).Execute(context.Background(), dto.RefreshTokenInput{RefreshToken: plain})
+	if !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Fatalf("ожидалась ошибка недействительного токена, получено %v", err)
+	}
+	if store.revoked != 0 {
+		t.Errorf("семейство отозвано %d раз, ожидалось 0", store.revoked)
+	}
+}
+
+func TestRefreshTokenRevokesFamilyOnReuse(t *testing.T) {
+	store, plain := newRefreshTokenStore(t)
+	usedAt := time.Now()
+	store.token.UsedAt = &usedAt
+
+	_, err := usecases.NewRefreshTokenUseCase(store, nil).Execute(context.Background(), dto.RefreshTokenInput{RefreshToken: plain})
+	if !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("ожидалась ошибка повторного использования, получено %v", err)
+	}
+	if store.revoked != 1 {
+		t.Errorf("семейство отозвано %d раз, ожидалось 1", store.revoked)
+	}
+}
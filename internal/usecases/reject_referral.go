@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type RejectReferralUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewRejectReferralUseCase(postgres PostgreSQLAdapter) *RejectReferralUseCase {
+	return &RejectReferralUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute отменяет задержанную связь. Бонусы по ней не начисляются.
+func (uc *RejectReferralUseCase) Execute(ctx context.Context, moderatorIDStr, referralIDStr string) (dto.ReferralReviewOutput, error) {
+	moderatorID, err := domain.UserIDFromString(moderatorIDStr)
+	if err != nil {
+		return dto.ReferralReviewOutput{}, err
+	}
+
+	referral, err := getReferral(ctx, uc.postgres, referralIDStr)
+	if err != nil {
+		return dto.ReferralReviewOutput{}, err
+	}
+
+	if err := referral.Reject(moderatorID, time.Now()); err != nil {
+		return dto.ReferralReviewOutput{}, err
+	}
+
+	updated, err := uc.postgres.UpdateReferralStatus(ctx, referral, domain.ReferralOnHold)
+	if err != nil {
+		return dto.ReferralReviewOutput{}, fmt.Errorf("ошибка при сохранении решения по реферальной связи: %w", err)
+	}
+	if !updated {
+		return dto.ReferralReviewOutput{}, domain.ErrReferralNotOnHold
+	}
+
+	return referralReviewToDTO(referral), nil
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type RejectSubmissionUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewRejectSubmissionUseCase(postgres PostgreSQLAdapter) *RejectSubmissionUseCase {
+	return &RejectSubmissionUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute отклоняет заявку. Причина отклонения показывается пользователю.
+func (uc *RejectSubmissionUseCase) Execute(ctx context.Context, moderatorIDStr, submissionIDStr string, input dto.RejectSubmissionInput) (dto.SubmissionOutput, error) {
+	moderatorID, err := domain.UserIDFromString(moderatorIDStr)
+	if err != nil {
+		return dto.SubmissionOutput{}, err
+	}
+
+	submission, err := getSubmission(ctx, uc.postgres, submissionIDStr)
+	if err != nil {
+		return dto.SubmissionOutput{}, err
+	}
+
+	if err := submission.Reject(moderatorID, input.Reason, time.Now()); err != nil {
+		return dto.SubmissionOutput{}, err
+	}
+
+	if err := uc.postgres.UpdateSubmissionReview(ctx, submission); err != nil {
+		return dto.SubmissionOutput{}, fmt.Errorf("ошибка при сохранении решения по заявке: %w", err)
+	}
+
+	return submissionToDTO(submission), nil
+}
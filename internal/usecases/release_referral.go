@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type ReleaseReferralUseCase struct {
+	postgres     PostgreSQLAdapter
+	vester       *ReferralVester
+	achievements *AchievementEngine
+}
+
+func NewReleaseReferralUseCase(postgres PostgreSQLAdapter, vester *ReferralVester, achievements *AchievementEngine) *ReleaseReferralUseCase {
+	return &ReleaseReferralUseCase{
+		postgres:     postgres,
+		vester:       vester,
+		achievements: achievements,
+	}
+}
+
+// Execute снимает задержку со связи: она возвращается в состояние pending и сразу
+// проверяется по условиям политики, так что уже выполненные условия дают бонусы немедленно.
+func (uc *ReleaseReferralUseCase) Execute(ctx context.Context, moderatorIDStr, referralIDStr string) (dto.ReferralReviewOutput, error) {
+	moderatorID, err := domain.UserIDFromString(moderatorIDStr)
+	if err != nil {
+		return dto.ReferralReviewOutput{}, err
+	}
+
+	var referral domain.Referral
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		referral, err = getReferral(ctx, uc.postgres, referralIDStr)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := referral.Release(moderatorID, now); err != nil {
+			return err
+		}
+
+		updated, err := uc.postgres.UpdateReferralStatus(ctx, referral, domain.ReferralOnHold)
+		if err != nil {
+			return fmt.Errorf("ошибка при сохранении решения по реферальной связи: %w", err)
+		}
+		if !updated {
+			return domain.ErrReferralNotOnHold
+		}
+
+		referral, err = uc.vester.Evaluate(ctx, referral, now)
+		return err
+	})
+
+	if err != nil {
+		return dto.ReferralReviewOutput{}, err
+	}
+
+	if referral.Status == domain.ReferralVested {
+		uc.achievements.EvaluateCommitted(ctx, referral.ReferrerID, referral.ReferredUserID)
+	}
+
+	return referralReviewToDTO(referral), nil
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type RequestLoginCodeUseCase struct {
+	postgres    PostgreSQLAdapter
+	mailer      Mailer
+	codeTTL     time.Duration
+	maxAttempts int
+	throttle    domain.LoginCodeThrottle
+}
+
+// NewRequestLoginCodeUseCase создает use case выдачи кодов входа.
+// throttle ограничивает число запросов кода на один email и с одного IP адреса.
+func NewRequestLoginCodeUseCase(postgres PostgreSQLAdapter, mailer Mailer, codeTTL time.Duration, maxAttempts int, throttle domain.LoginCodeThrottle) *RequestLoginCodeUseCase {
+	return &RequestLoginCodeUseCase{
+		postgres:    postgres,
+		mailer:      mailer,
+		codeTTL:     codeTTL,
+		maxAttempts: maxAttempts,
+		throttle:    throttle,
+	}
+}
+
+// Execute отправляет одноразовый код входа на email пользователя.
+// Для неизвестного email ничего не отправляется, но и ошибка не возвращается,
+// чтобы по ответу нельзя было проверить, зарегистрирован ли адрес.
+// Лимит запросов проверяется до поиска пользователя и одинаково для любых адресов.
+func (uc *RequestLoginCodeUseCase) Execute(ctx context.Context, input dto.RequestLoginCodeInput) error {
+	email, err := domain.NewEmail(input.Email)
+	if err != nil {
+		return err
+	}
+
+	emailRequests, ipRequests, err := uc.postgres.RecordLoginCodeRequest(ctx, email.String(), input.Client.IP, time.Now().Add(-uc.throttle.Window))
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении запроса кода входа: %w", err)
+	}
+	if !uc.throttle.Allows(emailRequests, ipRequests) {
+		slog.Warn("Превышен лимит запросов кода входа", "ip", input.Client.IP, "email_requests", emailRequests, "ip_requests", ipRequests)
+		return domain.ErrTooManyLoginCodeRequests
+	}
+
+	user, err := uc.postgres.GetUserByEmail(ctx, email.String())
+	if err != nil {
+		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+	if user == nil {
+		slog.Info("Запрошен код входа для незарегистрированного email")
+		return nil
+	}
+
+	code, plain, err := domain.NewLoginCode(user.ID, uc.codeTTL, uc.maxAttempts)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.postgres.CreateLoginCode(ctx, code); err != nil {
+		return fmt.Errorf("ошибка при сохранении кода входа: %w", err)
+	}
+
+	message := EmailMessage{
+		To:      user.Email.String(),
+		Subject: "Код для входа",
+		Body: strings.Join([]string{
+			"Здравствуйте, " + user.Username.String() + "!",
+			"",
+			"Ваш код для входа: " + plain,
+			fmt.Sprintf("Код действует %d мин. Если вы не запрашивали код, просто проигнорируйте это письмо.", int(uc.codeTTL.Minutes())),
+		}, "\n"),
+	}
+
+	if err := uc.mailer.Send(ctx, message); err != nil {
+		return fmt.Errorf("ошибка при отправке письма: %w", err)
+	}
+
+	return nil
+}
+
+// Cleanup удаляет запросы кодов входа, которые уже не учитываются в лимите.
+// Возвращает число удаленных записей.
+func (uc *RequestLoginCodeUseCase) Cleanup(ctx context.Context) (int64, error) {
+	return uc.postgres.DeleteLoginCodeRequests(ctx, time.Now().Add(-uc.throttle.Window))
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+// seasonStore запоминает границы, с которыми use case сезонов обращается к базе данных
+type seasonStore struct {
+	PostgreSQLAdapter
+
+	season domain.Season
+	bounds []time.Time
+}
+
+func (s *seasonStore) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func (s *seasonStore) HasOverlappingSeason(ctx context.Context, startsAt, endsAt time.Time) (bool, error) {
+	s.bounds = append(s.bounds, startsAt, endsAt)
+	return false, nil
+}
+
+func (s *seasonStore) CreateSeason(ctx context.Context, season domain.Season) error {
+	s.bounds = append(s.bounds, season.StartsAt, season.EndsAt)
+	return nil
+}
+
+func (s *seasonStore) GetSeason(ctx context.Context, seasonID domain.SeasonID) (*domain.Season, error) {
+	season := s.season
+	return &season, nil
+}
+
+func (s *seasonStore) GetPeriodLeaderboard(ctx context.Context, since, until time.Time, limit int, after *LeaderboardCursor) ([]LeaderboardEntry, error) {
+	s.bounds = append(s.bounds, since, until)
+	return nil, nil
+}
+
+func (s *seasonStore) CountPeriodLeaderboardUsers(ctx context.Context, since, until time.Time) (int, error) {
+	s.bounds = append(s.bounds, since, until)
+	return 0, nil
+}
+
+func (s *seasonStore) ListSeasonsToFinalize(ctx context.Context, now time.Time) ([]domain.Season, error) {
+	s.bounds = append(s.bounds, now)
+	return []domain.Season{s.season}, nil
+}
+
+func (s *seasonStore) FinalizeSeason(ctx context.Context, season domain.Season, finalizedAt time.Time) (bool, error) {
+	s.bounds = append(s.bounds, finalizedAt)
+	return true, nil
+}
+
+// assertStoredBounds проверяет, что все границы переданы в часовом поясе процесса
+func assertStoredBounds(t *testing.T, bounds []time.Time, want int) {
+	t.Helper()
+
+	if len(bounds) != want {
+		t.Fatalf("передано %d границ, ожидалось %d", len(bounds), want)
+	}
+	for _, bound := range bounds {
+		if bound.Location() != time.Local {
+			t.Errorf("граница %v передана в часовом поясе %v, ожидался часовой пояс процесса", bound, bound.Location())
+		}
+	}
+}
+
+func TestSeasonBoundsUseStoredTimezone(t *testing.T) {
+	setProcessLocation(t, time.FixedZone("UTC-7", -7*60*60))
+
+	startsAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	endsAt := startsAt.AddDate(0, 1, 0)
+
+	t.Run("create", func(t *testing.T) {
+		store := &seasonStore{}
+		output, err := NewCreateSeasonUseCase(store).Execute(context.Background(), dto.CreateSeasonInput{
+			Name:     "Весна",
+			StartsAt: startsAt,
+			EndsAt:   endsAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertStoredBounds(t, store.bounds, 4)
+		if !store.bounds[0].Equal(startsAt) || !store.bounds[1].Equal(endsAt) {
+			t.Errorf("границы сдвинуты: %v - %v", store.bounds[0], store.bounds[1])
+		}
+		if !output.StartsAt.Equal(startsAt) || !output.EndsAt.Equal(endsAt) {
+			t.Errorf("в ответе сезон %v - %v", output.StartsAt, output.EndsAt)
+		}
+	})
+
+	t.Run("live leaderboard", func(t *testing.T) {
+		season, err := domain.NewSeason("Весна", time.Now().Add(-time.Hour).UTC(), time.Now().Add(time.Hour).UTC())
+		if err != nil {
+			t.Fatal(err)
+		}
+		store := &seasonStore{season: season}
+
+		if _, err := NewGetSeasonLeaderboardUseCase(store, 100).Execute(context.Background(), season.ID.String(), 10, ""); err != nil {
+			t.Fatal(err)
+		}
+		assertStoredBounds(t, store.bounds, 4)
+	})
+
+	t.Run("finalize", func(t *testing.T) {
+		season, err := domain.NewSeason("Весна", startsAt, endsAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store := &seasonStore{season: season}
+
+		if _, err := NewFinalizeSeasonsUseCase(store).Execute(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		assertStoredBounds(t, store.bounds, 2)
+	})
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type SetReferralCodeUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewSetReferralCodeUseCase(postgres PostgreSQLAdapter) *SetReferralCodeUseCase {
+	return &SetReferralCodeUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute сохраняет собственный реферальный код пользователя.
+// Собственный код выбирается один раз, сгенерированный код продолжает работать.
+func (uc *SetReferralCodeUseCase) Execute(ctx context.Context, userIDStr string, input dto.SetReferralCodeInput) (dto.ReferralCodesOutput, error) {
+	userID, err := domain.UserIDFromString(userIDStr)
+	if err != nil {
+		return dto.ReferralCodesOutput{}, err
+	}
+
+	vanity, err := domain.NewVanityReferralCode(userID, input.Code)
+	if err != nil {
+		return dto.ReferralCodesOutput{}, err
+	}
+
+	var codes []domain.ReferralCode
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.postgres.LockUser(ctx, userID); err != nil {
+			return err
+		}
+
+		codes, err = uc.postgres.GetReferralCodesByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении реферальных кодов: %w", err)
+		}
+		for _, code := range codes {
+			if code.Kind == domain.ReferralCodeVanity {
+				return domain.ErrVanityCodeExists
+			}
+		}
+
+		created, err := uc.postgres.CreateReferralCode(ctx, vanity)
+		if err != nil {
+			return fmt.Errorf("ошибка при сохранении реферального кода: %w", err)
+		}
+		if !created {
+			return domain.ErrReferralCodeTaken
+		}
+
+		codes = append(codes, vanity)
+		return nil
+	})
+
+	if err != nil {
+		return dto.ReferralCodesOutput{}, err
+	}
+
+	return referralCodesToDTO(codes), nil
+}
+
+func referralCodesToDTO(codes []domain.ReferralCode) dto.ReferralCodesOutput {
+	var output dto.ReferralCodesOutput
+	for _, code := range codes {
+		switch code.Kind {
+		case domain.ReferralCodeGenerated:
+			output.ReferralCode = code.Code
+		case domain.ReferralCodeVanity:
+			output.VanityReferralCode = code.Code
+		}
+	}
+	return output
+}
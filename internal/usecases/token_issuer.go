@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenSigner подписывает claims access токена
+type TokenSigner interface {
+	Sign(claims jwt.MapClaims) (string, error)
+}
+
+// TokenIssuer выпускает access токены и refresh токены пользователям
+type TokenIssuer struct {
+	postgres        PostgreSQLAdapter
+	signer          TokenSigner
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewTokenIssuer(postgres PostgreSQLAdapter, signer TokenSigner, accessTokenTTL, refreshTokenTTL time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		postgres:        postgres,
+		signer:          signer,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+// IssueTokens выпускает access токен и refresh токен нового семейства
+func (i *TokenIssuer) IssueTokens(ctx context.Context, user domain.User) (dto.TokenOutput, error) {
+	refreshToken, plain, err := domain.NewRefreshToken(user.ID, i.refreshTokenTTL)
+	if err != nil {
+		return dto.TokenOutput{}, err
+	}
+	return i.issue(ctx, user, refreshToken, plain)
+}
+
+// RotateTokens выпускает access токен и следующий refresh токен семейства previous
+func (i *TokenIssuer) RotateTokens(ctx context.Context, user domain.User, previous domain.RefreshToken) (dto.TokenOutput, error) {
+	refreshToken, plain, err := previous.Rotate(i.refreshTokenTTL)
+	if err != nil {
+		return dto.TokenOutput{}, err
+	}
+	return i.issue(ctx, user, refreshToken, plain)
+}
+
+func (i *TokenIssuer) issue(ctx context.Context, user domain.User, refreshToken domain.RefreshToken, plain string) (dto.TokenOutput, error) {
+	if err := i.postgres.CreateRefreshToken(ctx, refreshToken); err != nil {
+		return dto.TokenOutput{}, fmt.Errorf("ошибка при сохранении refresh токена: %w", err)
+	}
+
+	now := time.Now()
+	accessToken, err := i.generateJWT(user, now, now.Add(i.accessTokenTTL))
+	if err != nil {
+		return dto.TokenOutput{}, fmt.Errorf("ошибка при генерации токена: %w", err)
+	}
+
+	return dto.TokenOutput{
+		AccessToken:  accessToken,
+		RefreshToken: plain,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(i.accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// IssueAccessToken выпускает только access токен, без refresh токена.
+// Токен действует не дольше notAfter, чтобы обмен токена не продлевал сессию.
+// Нулевой notAfter означает обычный срок действия access токена.
+func (i *TokenIssuer) IssueAccessToken(user domain.User, notAfter time.Time) (dto.TokenOutput, error) {
+	now := time.Now()
+	expiresAt := now.Add(i.accessTokenTTL)
+	if !notAfter.IsZero() && notAfter.Before(expiresAt) {
+		expiresAt = notAfter
+	}
+
+	accessToken, err := i.generateJWT(user, now, expiresAt)
+	if err != nil {
+		return dto.TokenOutput{}, fmt.Errorf("ошибка при генерации токена: %w", err)
+	}
+
+	return dto.TokenOutput{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(expiresAt.Sub(now).Seconds()),
+	}, nil
+}
+
+// generateJWT генерирует JWT токен для пользователя
+func (i *TokenIssuer) generateJWT(user domain.User, issuedAt, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": user.ID.String(),
+		"role":    user.Role.String(),
+		"exp":     expiresAt.Unix(),
+		"iat":     issuedAt.Unix(),
+	}
+
+	return i.signer.Sign(claims)
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type UpdateCatalogTaskUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewUpdateCatalogTaskUseCase(postgres PostgreSQLAdapter) *UpdateCatalogTaskUseCase {
+	return &UpdateCatalogTaskUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute изменяет задание каталога. Поинты за уже выполненные задания не пересчитываются.
+func (uc *UpdateCatalogTaskUseCase) Execute(ctx context.Context, key string, input dto.UpdateCatalogTaskInput) (dto.CatalogTaskOutput, error) {
+	task, err := getCatalogTask(ctx, uc.postgres, key)
+	if err != nil {
+		return dto.CatalogTaskOutput{}, err
+	}
+
+	title, description, points := task.Title, task.Description, task.Points
+	if input.Title != nil {
+		title = *input.Title
+	}
+	if input.Description != nil {
+		description = *input.Description
+	}
+	if input.Points != nil {
+		points = *input.Points
+	}
+
+	if err := task.Update(title, description, points); err != nil {
+		return dto.CatalogTaskOutput{}, err
+	}
+	if input.Repeat != nil {
+		task.Repeat, err = repeatPolicyFromDTO(*input.Repeat)
+		if err != nil {
+			return dto.CatalogTaskOutput{}, err
+		}
+	}
+	if input.ProofType != nil {
+		task.ProofType, err = domain.NewProofType(*input.ProofType)
+		if err != nil {
+			return dto.CatalogTaskOutput{}, err
+		}
+	}
+	if input.Active != nil {
+		if err := task.SetActive(*input.Active); err != nil {
+			return dto.CatalogTaskOutput{}, err
+		}
+	}
+
+	if err := uc.postgres.UpdateCatalogTask(ctx, task); err != nil {
+		return dto.CatalogTaskOutput{}, fmt.Errorf("ошибка при обновлении задания: %w", err)
+	}
+
+	return catalogTaskToDTO(task), nil
+}
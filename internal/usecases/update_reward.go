@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"user-rewards-api/internal/dto"
+)
+
+type UpdateRewardUseCase struct {
+	postgres PostgreSQLAdapter
+}
+
+func NewUpdateRewardUseCase(postgres PostgreSQLAdapter) *UpdateRewardUseCase {
+	return &UpdateRewardUseCase{
+		postgres: postgres,
+	}
+}
+
+// Execute изменяет товар магазина наград. Уже оформленные заказы не пересчитываются.
+func (uc *UpdateRewardUseCase) Execute(ctx context.Context, rewardIDStr string, input dto.UpdateRewardInput) (dto.RewardOutput, error) {
+	reward, err := getReward(ctx, uc.postgres, rewardIDStr)
+	if err != nil {
+		return dto.RewardOutput{}, err
+	}
+
+	title, description, cost := reward.Title, reward.Description, reward.Cost
+	stock, perUserLimit := reward.Stock, reward.PerUserLimit
+	availableFrom, availableUntil := reward.AvailableFrom, reward.AvailableUntil
+	if input.Title != nil {
+		title = *input.Title
+	}
+	if input.Description != nil {
+		description = *input.Description
+	}
+	if input.Cost != nil {
+		cost = *input.Cost
+	}
+	if input.Stock != nil {
+		stock = input.Stock
+	}
+	if input.PerUserLimit != nil {
+		perUserLimit = *input.PerUserLimit
+	}
+	if input.AvailableFrom != nil {
+		availableFrom = input.AvailableFrom
+	}
+	if input.AvailableUntil != nil {
+		availableUntil = input.AvailableUntil
+	}
+
+	if err := reward.Update(title, description, cost, stock, perUserLimit, availableFrom, availableUntil); err != nil {
+		return dto.RewardOutput{}, err
+	}
+	if input.Active != nil {
+		reward.Active = *input.Active
+	}
+
+	if err := uc.postgres.UpdateReward(ctx, reward); err != nil {
+		return dto.RewardOutput{}, fmt.Errorf("ошибка при обновлении товара: %w", err)
+	}
+
+	return rewardToDTO(reward), nil
+}
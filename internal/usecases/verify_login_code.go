@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"user-rewards-api/internal/domain"
+	"user-rewards-api/internal/dto"
+)
+
+type VerifyLoginCodeUseCase struct {
+	postgres    PostgreSQLAdapter
+	tokenIssuer *TokenIssuer
+}
+
+func NewVerifyLoginCodeUseCase(postgres PostgreSQLAdapter, tokenIssuer *TokenIssuer) *VerifyLoginCodeUseCase {
+	return &VerifyLoginCodeUseCase{
+		postgres:    postgres,
+		tokenIssuer: tokenIssuer,
+	}
+}
+
+// Execute обменивает одноразовый код из письма на пару токенов
+func (uc *VerifyLoginCodeUseCase) Execute(ctx context.Context, input dto.VerifyLoginCodeInput) (dto.TokenOutput, error) {
+	email, err := domain.NewEmail(input.Email)
+	if err != nil {
+		return dto.TokenOutput{}, domain.ErrInvalidLoginCode
+	}
+
+	user, err := uc.postgres.GetUserByEmail(ctx, email.String())
+	if err != nil {
+		return dto.TokenOutput{}, fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+	if user == nil {
+		return dto.TokenOutput{}, domain.ErrInvalidLoginCode
+	}
+
+	code, err := uc.postgres.GetLatestLoginCode(ctx, user.ID)
+	if err != nil {
+		return dto.TokenOutput{}, fmt.Errorf("ошибка при поиске кода входа: %w", err)
+	}
+	if code == nil || !code.IsUsable(time.Now()) {
+		return dto.TokenOutput{}, domain.ErrInvalidLoginCode
+	}
+
+	// Попытка засчитывается до сравнения, чтобы параллельные запросы не обошли лимит попыток
+	claimed, err := uc.postgres.ClaimLoginCodeAttempt(ctx, code.ID, time.Now())
+	if err != nil {
+		return dto.TokenOutput{}, fmt.Errorf("ошибка при обновлении кода входа: %w", err)
+	}
+	if !claimed || !code.Matches(strings.TrimSpace(input.Code)) {
+		return dto.TokenOutput{}, domain.ErrInvalidLoginCode
+	}
+
+	var output dto.TokenOutput
+
+	err = uc.postgres.WithTransaction(ctx, func(ctx context.Context) error {
+		consumed, err := uc.postgres.ConsumeLoginCode(ctx, code.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка при погашении кода входа: %w", err)
+		}
+		if !consumed {
+			return domain.ErrInvalidLoginCode
+		}
+
+		output, err = uc.tokenIssuer.IssueTokens(ctx, *user)
+		return err
+	})
+
+	if err != nil {
+		return dto.TokenOutput{}, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"user-rewards-api/internal/domain"
+)
+
+// vestReferralsBatchSize число связей, загружаемых за один запрос
+const vestReferralsBatchSize = 500
+
+type VestReferralsUseCase struct {
+	postgres     PostgreSQLAdapter
+	vester       *ReferralVester
+	achievements *AchievementEngine
+}
+
+func NewVestReferralsUseCase(postgres PostgreSQLAdapter, vester *ReferralVester, achievements *AchievementEngine) *VestReferralsUseCase {
+	return &VestReferralsUseCase{
+		postgres:     postgres,
+		vester:       vester,
+		achievements: achievements,
+	}
+}
+
+// Execute проверяет ожидающие реферальные связи, условия по которым зависят от времени,
+// например возраст аккаунта или истечение срока. Возвращает число начисленных и отмененных связей.
+func (uc *VestReferralsUseCase) Execute(ctx context.Context) (vested, cancelled int, err error) {
+	var after *domain.Referral
+	for {
+		referrals, err := uc.postgres.ListPendingReferrals(ctx, after, vestReferralsBatchSize)
+		if err != nil {
+			return vested, cancelled, fmt.Errorf("ошибка при получении ожидающих реферальных связей: %w", err)
+		}
+
+		for _, referral := range referrals {
+			var result domain.Referral
+			err := uc.postgres.WithTransaction(ctx, func(txCtx context.Context) error {
+				var err error
+				result, err = uc.vester.Evaluate(txCtx, referral, time.Now())
+				return err
+			})
+			if err != nil {
+				return vested, cancelled, fmt.Errorf("ошибка при проверке реферальной связи %s: %w", referral.ID, err)
+			}
+
+			switch result.Status {
+			case domain.ReferralVested:
+				vested++
+				uc.achievements.EvaluateCommitted(ctx, result.ReferrerID, result.ReferredUserID)
+			case domain.ReferralCancelled:
+				cancelled++
+			}
+		}
+
+		if len(referrals) < vestReferralsBatchSize {
+			return vested, cancelled, nil
+		}
+		after = &referrals[len(referrals)-1]
+	}
+}
@@ -0,0 +1,29 @@
+CREATE TABLE point_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount <> 0),
+    source VARCHAR(20) NOT NULL,
+    reference_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE(user_id, source, reference_id)
+);
+
+CREATE INDEX idx_point_entries_user_id ON point_entries(user_id);
+CREATE INDEX idx_point_entries_created_at ON point_entries(created_at);
+
+INSERT INTO point_entries (user_id, amount, source, reference_id, created_at)
+SELECT user_id, points, 'task', id::text, completed_at
+FROM user_tasks
+WHERE points <> 0;
+
+INSERT INTO point_entries (user_id, amount, source, reference_id, created_at)
+SELECT referred_user_id, bonus_points, 'referral', id::text, created_at
+FROM referrals
+WHERE bonus_points <> 0;
+
+INSERT INTO point_entries (user_id, amount, source, reference_id, created_at)
+SELECT u.id, u.balance - COALESCE(SUM(p.amount), 0), 'admin', 'opening_balance', u.updated_at
+FROM users u
+LEFT JOIN point_entries p ON p.user_id = u.id
+GROUP BY u.id, u.balance, u.updated_at
+HAVING u.balance - COALESCE(SUM(p.amount), 0) <> 0;
@@ -0,0 +1,2 @@
+ALTER TABLE user_tasks DROP CONSTRAINT IF EXISTS fk_user_tasks_task_type;
+DROP TABLE IF EXISTS tasks;
@@ -0,0 +1,27 @@
+CREATE TABLE tasks (
+    key VARCHAR(50) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT DEFAULT '' NOT NULL,
+    points INTEGER NOT NULL CHECK (points > 0),
+    active BOOLEAN DEFAULT TRUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP
+);
+
+INSERT INTO tasks (key, title, description, points) VALUES
+    ('survey', 'Пройти опрос', 'Ответьте на несколько вопросов о сервисе', 10),
+    ('subscribe_telegram', 'Подписаться на Telegram', 'Подпишитесь на наш Telegram канал', 50),
+    ('subscribe_twitter', 'Подписаться на Twitter', 'Подпишитесь на наш аккаунт в Twitter', 50),
+    ('invite_friend', 'Пригласить друга', 'Пригласите друга, поинты получите и вы, и ваш реферер', 100);
+
+-- типы заданий, которые уже удалены из кода, но остались в истории выполнений
+INSERT INTO tasks (key, title, points, active, archived_at)
+SELECT task_type, task_type, GREATEST(MAX(points), 1), FALSE, CURRENT_TIMESTAMP
+FROM user_tasks
+WHERE task_type NOT IN (SELECT key FROM tasks)
+GROUP BY task_type;
+
+ALTER TABLE user_tasks
+    ADD CONSTRAINT fk_user_tasks_task_type FOREIGN KEY (task_type) REFERENCES tasks(key);
+
+CREATE INDEX idx_tasks_active ON tasks(active);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_user_tasks_user_id_task_type;
+
+ALTER TABLE user_tasks ADD CONSTRAINT user_tasks_user_id_task_type_key UNIQUE (user_id, task_type);
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS repeat_policy,
+    DROP COLUMN IF EXISTS repeat_interval_hours,
+    DROP COLUMN IF EXISTS reset_minute,
+    DROP COLUMN IF EXISTS reset_weekday,
+    DROP COLUMN IF EXISTS max_completions;
@@ -0,0 +1,10 @@
+ALTER TABLE tasks
+    ADD COLUMN repeat_policy VARCHAR(20) DEFAULT 'once' NOT NULL,
+    ADD COLUMN repeat_interval_hours INTEGER,
+    ADD COLUMN reset_minute INTEGER DEFAULT 0 NOT NULL,
+    ADD COLUMN reset_weekday SMALLINT DEFAULT 1 NOT NULL,
+    ADD COLUMN max_completions INTEGER;
+
+ALTER TABLE user_tasks DROP CONSTRAINT IF EXISTS user_tasks_user_id_task_type_key;
+
+CREATE INDEX idx_user_tasks_user_id_task_type ON user_tasks(user_id, task_type, completed_at DESC);
@@ -0,0 +1,20 @@
+ALTER TABLE tasks ADD COLUMN proof_type VARCHAR(20) DEFAULT 'none' NOT NULL;
+
+CREATE TABLE task_submissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_type VARCHAR(50) NOT NULL REFERENCES tasks(key),
+    proof_type VARCHAR(20) NOT NULL,
+    proof TEXT NOT NULL,
+    points INTEGER NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending' NOT NULL,
+    rejection_reason TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    user_task_id UUID REFERENCES user_tasks(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_task_submissions_status ON task_submissions(status, created_at);
+CREATE INDEX idx_task_submissions_user_id ON task_submissions(user_id, created_at DESC);
+CREATE INDEX idx_task_submissions_user_id_task_type ON task_submissions(user_id, task_type) WHERE status = 'pending';
@@ -0,0 +1,4 @@
+UPDATE tasks SET proof_type = 'none' WHERE proof_type = 'external';
+
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS external_accounts;
@@ -0,0 +1,17 @@
+CREATE TABLE external_accounts (
+    provider VARCHAR(20) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (provider, external_id),
+    UNIQUE (user_id, provider)
+);
+
+CREATE TABLE webhook_events (
+    partner VARCHAR(20) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (partner, event_id)
+);
+
+UPDATE tasks SET proof_type = 'external' WHERE key IN ('subscribe_telegram', 'subscribe_twitter');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_leaderboard;
+
+CREATE INDEX idx_users_balance ON users(balance DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_balance;
+
+CREATE INDEX idx_users_leaderboard ON users(balance DESC, created_at ASC, id ASC);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_point_entries_earned;
@@ -0,0 +1 @@
+CREATE INDEX idx_point_entries_earned ON point_entries(created_at, user_id) INCLUDE (amount) WHERE amount > 0;
@@ -0,0 +1,22 @@
+CREATE TABLE seasons (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    finalized_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_seasons_starts_at ON seasons(starts_at DESC);
+CREATE INDEX idx_seasons_unfinalized ON seasons(ends_at) WHERE finalized_at IS NULL;
+
+CREATE TABLE season_standings (
+    season_id UUID NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(255) NOT NULL,
+    score INTEGER NOT NULL,
+    PRIMARY KEY (season_id, rank),
+    UNIQUE (season_id, user_id)
+);
@@ -0,0 +1,38 @@
+CREATE TABLE referral_codes (
+    code VARCHAR(16) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('generated', 'vanity')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (user_id, kind)
+);
+
+-- Сгенерированные коды для уже зарегистрированных пользователей: 8 символов Crockford base32.
+-- Случайные коды могут совпасть, поэтому совпавшие пропускаются, и вставка повторяется
+-- для пользователей, которым код еще не достался.
+DO $$
+BEGIN
+    LOOP
+        INSERT INTO referral_codes (code, user_id, kind)
+        SELECT
+            (
+                SELECT string_agg(substr('0123456789ABCDEFGHJKMNPQRSTVWXYZ', floor(random() * 32)::int + 1, 1), '')
+                FROM generate_series(1, 8)
+                WHERE u.id IS NOT NULL
+            ),
+            u.id,
+            'generated'
+        FROM users u
+        WHERE NOT EXISTS (
+            SELECT 1 FROM referral_codes rc WHERE rc.user_id = u.id AND rc.kind = 'generated'
+        )
+        ON CONFLICT DO NOTHING;
+
+        EXIT WHEN NOT EXISTS (
+            SELECT 1
+            FROM users u
+            WHERE NOT EXISTS (
+                SELECT 1 FROM referral_codes rc WHERE rc.user_id = u.id AND rc.kind = 'generated'
+            )
+        );
+    END LOOP;
+END $$;
@@ -0,0 +1,14 @@
+CREATE TABLE referral_commissions (
+    id UUID PRIMARY KEY,
+    beneficiary_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    earner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    level INTEGER NOT NULL CHECK (level > 0),
+    source_entry_id UUID NOT NULL REFERENCES point_entries(id) ON DELETE CASCADE,
+    base_points INTEGER NOT NULL CHECK (base_points > 0),
+    rate_bps INTEGER NOT NULL CHECK (rate_bps > 0 AND rate_bps <= 10000),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (source_entry_id, beneficiary_id)
+);
+
+CREATE INDEX idx_referral_commissions_beneficiary ON referral_commissions(beneficiary_id, level);
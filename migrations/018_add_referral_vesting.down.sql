@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_referrals_pending;
+
+ALTER TABLE referrals
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS vested_at,
+    DROP COLUMN IF EXISTS referrer_bonus_points,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,12 @@
+-- Уже существующие связи были оплачены сразу при создании, поэтому считаются завершенными
+ALTER TABLE referrals
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'vested' CHECK (status IN ('pending', 'vested', 'cancelled')),
+    ADD COLUMN referrer_bonus_points INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN vested_at TIMESTAMP,
+    ADD COLUMN cancelled_at TIMESTAMP;
+
+UPDATE referrals SET vested_at = created_at;
+
+ALTER TABLE referrals ALTER COLUMN status SET DEFAULT 'pending';
+
+CREATE INDEX idx_referrals_pending ON referrals(created_at) WHERE status = 'pending';
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS referral_fraud_signals;
+
+DROP INDEX IF EXISTS idx_referrals_referrer_created_at;
+DROP INDEX IF EXISTS idx_referrals_on_hold;
+
+UPDATE referrals SET status = 'pending' WHERE status = 'on_hold';
+
+ALTER TABLE referrals
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS fraud_score,
+    DROP CONSTRAINT referrals_status_check;
+ALTER TABLE referrals ADD CONSTRAINT referrals_status_check CHECK (status IN ('pending', 'vested', 'cancelled'));
+
+DROP TABLE IF EXISTS client_events;
@@ -0,0 +1,31 @@
+CREATE TABLE client_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('signup', 'referral')),
+    ip VARCHAR(45) NOT NULL,
+    user_agent_hash VARCHAR(64) NOT NULL,
+    device_id VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_client_events_user_id ON client_events(user_id);
+CREATE INDEX idx_client_events_ip ON client_events(ip, created_at);
+CREATE INDEX idx_client_events_device_id ON client_events(device_id, created_at) WHERE device_id IS NOT NULL;
+
+ALTER TABLE referrals DROP CONSTRAINT referrals_status_check;
+ALTER TABLE referrals
+    ADD CONSTRAINT referrals_status_check CHECK (status IN ('pending', 'on_hold', 'vested', 'cancelled')),
+    ADD COLUMN fraud_score INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN reviewed_at TIMESTAMP;
+
+CREATE INDEX idx_referrals_on_hold ON referrals(created_at) WHERE status = 'on_hold';
+CREATE INDEX idx_referrals_referrer_created_at ON referrals(referrer_id, created_at);
+
+CREATE TABLE referral_fraud_signals (
+    referral_id UUID NOT NULL REFERENCES referrals(id) ON DELETE CASCADE,
+    rule VARCHAR(50) NOT NULL,
+    score INTEGER NOT NULL CHECK (score > 0),
+    reason TEXT NOT NULL,
+    PRIMARY KEY (referral_id, rule)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_referrals_leaderboard;
+DROP INDEX IF EXISTS idx_referrals_referrer_created_at;
+
+CREATE INDEX idx_referrals_referrer_created_at ON referrals(referrer_id, created_at);
+CREATE INDEX idx_referrals_referrer_id ON referrals(referrer_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_referrals_referrer_id;
+DROP INDEX IF EXISTS idx_referrals_referrer_created_at;
+
+CREATE INDEX idx_referrals_referrer_created_at ON referrals(referrer_id, created_at, id);
+CREATE INDEX idx_referrals_leaderboard ON referrals(created_at, referrer_id) WHERE status IN ('pending', 'vested');
@@ -0,0 +1,33 @@
+CREATE TABLE rewards (
+    id UUID PRIMARY KEY,
+    title VARCHAR(100) NOT NULL,
+    description TEXT DEFAULT '' NOT NULL,
+    cost INTEGER NOT NULL CHECK (cost > 0),
+    stock INTEGER CHECK (stock >= 0),
+    per_user_limit INTEGER DEFAULT 0 NOT NULL CHECK (per_user_limit >= 0),
+    available_from TIMESTAMP,
+    available_until TIMESTAMP,
+    active BOOLEAN DEFAULT TRUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK (available_until IS NULL OR available_from IS NULL OR available_until > available_from)
+);
+
+CREATE INDEX idx_rewards_active ON rewards(active);
+
+CREATE TABLE reward_orders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reward_id UUID NOT NULL REFERENCES rewards(id),
+    title VARCHAR(100) NOT NULL,
+    cost INTEGER NOT NULL CHECK (cost > 0),
+    ledger_entry_id UUID NOT NULL UNIQUE REFERENCES point_entries(id),
+    status VARCHAR(20) DEFAULT 'pending' NOT NULL CHECK (status IN ('pending', 'fulfilled')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    fulfilled_at TIMESTAMP,
+    fulfilled_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_reward_orders_user_id ON reward_orders(user_id, created_at DESC);
+CREATE INDEX idx_reward_orders_reward_user ON reward_orders(reward_id, user_id);
+CREATE INDEX idx_reward_orders_pending ON reward_orders(created_at) WHERE status = 'pending';
@@ -0,0 +1,32 @@
+CREATE TABLE point_lots (
+    entry_id UUID PRIMARY KEY REFERENCES point_entries(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    remaining INTEGER NOT NULL CHECK (remaining >= 0 AND remaining <= amount),
+    earned_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    -- Заполняется только для партий, разложенных этой миграцией: срок сгорания не раньше этого момента
+    min_expires_at TIMESTAMP
+);
+
+CREATE INDEX idx_point_lots_open ON point_lots(user_id, expires_at) WHERE remaining > 0;
+CREATE INDEX idx_point_lots_expiring ON point_lots(expires_at) WHERE remaining > 0;
+CREATE INDEX idx_point_lots_backfilled ON point_lots(entry_id) WHERE min_expires_at IS NOT NULL AND remaining > 0;
+
+-- Текущий баланс раскладывается по самым новым начислениям: списания расходуют старые партии первыми.
+-- Поинты, которые по сроку уже должны были сгореть, сгорают не раньше чем через 30 дней,
+-- чтобы пользователи успели увидеть их в статусе.
+-- Срок считается по политике по умолчанию (12 месяцев). Если в конфигурации задан другой срок,
+-- задание сгорания поинтов пересчитывает срок таких партий по действующей политике.
+INSERT INTO point_lots (entry_id, user_id, amount, remaining, earned_at, expires_at, min_expires_at)
+SELECT id, user_id, amount, LEAST(amount, balance - covered_after), created_at,
+    GREATEST(created_at + INTERVAL '12 months', CURRENT_TIMESTAMP + INTERVAL '30 days'),
+    CURRENT_TIMESTAMP + INTERVAL '30 days'
+FROM (
+    SELECT p.id, p.user_id, p.amount, p.created_at, u.balance,
+        SUM(p.amount) OVER (PARTITION BY p.user_id ORDER BY p.created_at DESC, p.id DESC) - p.amount AS covered_after
+    FROM point_entries p
+    JOIN users u ON u.id = p.user_id
+    WHERE p.amount > 0 AND u.balance > 0
+) lots
+WHERE covered_after < balance;
@@ -0,0 +1,6 @@
+CREATE TABLE user_badges (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    badge_key VARCHAR(50) NOT NULL,
+    awarded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, badge_key)
+);
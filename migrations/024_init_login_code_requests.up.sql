@@ -0,0 +1,10 @@
+CREATE TABLE login_code_requests (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_login_code_requests_email ON login_code_requests(email, created_at);
+CREATE INDEX idx_login_code_requests_ip ON login_code_requests(ip, created_at);
+CREATE INDEX idx_login_code_requests_created_at ON login_code_requests(created_at);
@@ -0,0 +1 @@
+ALTER TABLE point_entries DROP COLUMN IF EXISTS note;
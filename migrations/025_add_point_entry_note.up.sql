@@ -0,0 +1 @@
+ALTER TABLE point_entries ADD COLUMN note VARCHAR(255);
@@ -0,0 +1 @@
+-- Удаленные ответы не восстанавливаются
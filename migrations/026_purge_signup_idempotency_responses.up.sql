@@ -0,0 +1,2 @@
+-- Ответы на регистрацию содержали токены нового пользователя и больше не сохраняются
+DELETE FROM idempotency_keys WHERE route = 'POST /users';